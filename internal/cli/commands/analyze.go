@@ -22,10 +22,16 @@ var ExitCode = 0
 type AnalyzeOptions struct {
 	Output    string
 	TimeRange string
+	Now       string
 	Rules     []string
 	Verbose   bool
 	Quiet     bool
 
+	// Severity and tag options
+	MinSeverity string
+	Tags        []string
+	FailOn      string
+
 	// Webhook options
 	WebhookURL     string
 	WebhookToken   string
@@ -47,8 +53,8 @@ Detects:
   - Conditional absence (trigger without expected consequence)
 
 Exit codes:
-  0 - No missing logs detected
-  1 - Missing logs detected
+  0 - No missing logs detected (at or above the --fail-on severity)
+  1 - Missing logs detected (at or above the --fail-on severity)
   2 - Configuration or runtime error`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -58,11 +64,17 @@ Exit codes:
 
 	// Flags
 	cmd.Flags().StringVarP(&opts.Output, "output", "o", "text", "Output format (text|json)")
-	cmd.Flags().StringVar(&opts.TimeRange, "time-range", "", "Limit analysis to time window (e.g., 2h, 24h)")
+	cmd.Flags().StringVar(&opts.TimeRange, "time-range", "", "Limit analysis to time window ending at --now, or the current time (e.g., 2h, 24h)")
+	cmd.Flags().StringVar(&opts.Now, "now", "", "Evaluation time for timeouts (RFC 3339, default: last log timestamp)")
 	cmd.Flags().StringSliceVar(&opts.Rules, "rule", nil, "Run specific rule(s) only (can be repeated)")
 	cmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "Show matched logs, not just missing ones")
 	cmd.Flags().BoolVarP(&opts.Quiet, "quiet", "q", false, "Summary only, no details")
 
+	// Severity and tag flags
+	cmd.Flags().StringVar(&opts.MinSeverity, "min-severity", "", "Run rules of at least this severity only (info|warning|critical)")
+	cmd.Flags().StringSliceVar(&opts.Tags, "tag", nil, "Run rules with any of these tags only (can be repeated)")
+	cmd.Flags().StringVar(&opts.FailOn, "fail-on", "info", "Lowest severity whose issues set exit code 1 (info|warning|critical)")
+
 	// Webhook flags
 	cmd.Flags().StringVar(&opts.WebhookURL, "webhook-url", "", "Webhook endpoint URL")
 	cmd.Flags().StringVar(&opts.WebhookToken, "webhook-token", "", "Bearer token for webhook auth")
@@ -83,6 +95,9 @@ func runAnalyze(cmd *cobra.Command, args []string, opts *AnalyzeOptions) error {
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
+	if err := cfg.RunRosterCommands(ctx); err != nil {
+		return fmt.Errorf("loading rosters: %w", err)
+	}
 
 	// Expand log source globs
 	files, err := parser.ExpandGlobs(cfg.LogSources)
@@ -94,15 +109,28 @@ func runAnalyze(cmd *cobra.Command, args []string, opts *AnalyzeOptions) error {
 		return fmt.Errorf("no log files matched patterns: %v", cfg.LogSources)
 	}
 
-	// Parse time range if specified
 	var analyzerOpts []analyzer.AnalyzerOption
 
+	var now time.Time
+	if opts.Now != "" {
+		now, err = time.Parse(time.RFC3339, opts.Now)
+		if err != nil {
+			return fmt.Errorf("invalid now %q (use RFC 3339, e.g. 2024-01-15T10:00:00Z): %w", opts.Now, err)
+		}
+		analyzerOpts = append(analyzerOpts, analyzer.WithNow(now))
+	}
+
+	// Parse time range if specified; it ends at --now when given, so
+	// historical logs can be replayed
 	if opts.TimeRange != "" {
 		duration, err := time.ParseDuration(opts.TimeRange)
 		if err != nil {
 			return fmt.Errorf("invalid time-range %q: %w", opts.TimeRange, err)
 		}
-		end := time.Now()
+		end := now
+		if end.IsZero() {
+			end = time.Now()
+		}
 		start := end.Add(-duration)
 		analyzerOpts = append(analyzerOpts, analyzer.WithTimeRange(start, end))
 	}
@@ -111,7 +139,25 @@ func runAnalyze(cmd *cobra.Command, args []string, opts *AnalyzeOptions) error {
 		analyzerOpts = append(analyzerOpts, analyzer.WithRuleFilter(opts.Rules))
 	}
 
+	if opts.MinSeverity != "" {
+		minSeverity, err := config.ParseSeverity(opts.MinSeverity)
+		if err != nil {
+			return fmt.Errorf("min-severity: %w", err)
+		}
+		analyzerOpts = append(analyzerOpts, analyzer.WithMinSeverity(minSeverity))
+	}
+
+	if len(opts.Tags) > 0 {
+		analyzerOpts = append(analyzerOpts, analyzer.WithTags(opts.Tags))
+	}
+
+	failOn, err := config.ParseSeverity(opts.FailOn)
+	if err != nil {
+		return fmt.Errorf("fail-on: %w", err)
+	}
+
 	analyzerOpts = append(analyzerOpts, analyzer.WithVerbose(opts.Verbose))
+	analyzerOpts = append(analyzerOpts, analyzer.WithSources(files))
 
 	// Create analyzer
 	a, err := analyzer.NewAnalyzer(cfg, analyzerOpts...)
@@ -119,27 +165,7 @@ func runAnalyze(cmd *cobra.Command, args []string, opts *AnalyzeOptions) error {
 		return fmt.Errorf("creating analyzer: %w", err)
 	}
 
-	// Create log source with timestamp-ordered merging across files
-	var source parser.LogSource
-	if len(files) == 1 {
-		// Single file - use simple FileSource
-		source = parser.NewFileSource(
-			files,
-			cfg.TimestampFormat.CompiledPattern(),
-			cfg.TimestampFormat.Layout,
-		)
-	} else {
-		// Multiple files - use MergedSource for chronological ordering
-		sources := make([]parser.LogSource, len(files))
-		for i, file := range files {
-			sources[i] = parser.NewFileSource(
-				[]string{file},
-				cfg.TimestampFormat.CompiledPattern(),
-				cfg.TimestampFormat.Layout,
-			)
-		}
-		source = parser.NewMergedSource(sources...)
-	}
+	source := newLogSource(cfg, files)
 	defer source.Close()
 
 	// Run analysis
@@ -165,14 +191,37 @@ func runAnalyze(cmd *cobra.Command, args []string, opts *AnalyzeOptions) error {
 	// Send webhooks (errors logged but don't fail analysis)
 	sendWebhooks(ctx, cfg, opts, report)
 
-	// Set exit code based on results
-	if report.HasIssues() {
+	// Set exit code based on results at or above the fail-on severity
+	if report.HasIssuesAtLeast(failOn) {
 		ExitCode = 1
 	}
 
 	return nil
 }
 
+// newLogSource creates a log source with timestamp-ordered merging across files.
+func newLogSource(cfg *config.Config, files []string) parser.LogSource {
+	if len(files) == 1 {
+		// Single file - use simple FileSource
+		return parser.NewFileSource(
+			files,
+			cfg.TimestampFormat.CompiledPattern(),
+			cfg.TimestampFormat.Layout,
+		)
+	}
+
+	// Multiple files - use MergedSource for chronological ordering
+	sources := make([]parser.LogSource, len(files))
+	for i, file := range files {
+		sources[i] = parser.NewFileSource(
+			[]string{file},
+			cfg.TimestampFormat.CompiledPattern(),
+			cfg.TimestampFormat.Layout,
+		)
+	}
+	return parser.NewMergedSource(sources...)
+}
+
 func createFormatter(opts *AnalyzeOptions) (output.Formatter, error) {
 	formatOpts := output.FormatOptions{
 		Verbose: opts.Verbose,
@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ccollicutt/negalog/pkg/baseline"
+	"github.com/ccollicutt/negalog/pkg/config"
+	"github.com/ccollicutt/negalog/pkg/parser"
+)
+
+// BaselineOptions holds command-line options for the baseline command.
+type BaselineOptions struct {
+	Logs  []string
+	Rules []string
+}
+
+// NewBaselineCommand creates the baseline command.
+func NewBaselineCommand() *cobra.Command {
+	opts := &BaselineOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "baseline <config-file>",
+		Short: "Build baseline files from past logs",
+		Long: `Learn the expected volume per hour of the week for each baseline rule
+and write it to the rule's baseline_file.
+
+Only hours entirely covered by the logs are learned, so give it at least a
+few weeks of history. Hours with no matches count as zero.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBaseline(cmd, args, opts)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&opts.Logs, "logs", nil, "Historical log files to learn from (default: the config's log_sources)")
+	cmd.Flags().StringSliceVar(&opts.Rules, "rule", nil, "Build specific rule(s) only (can be repeated)")
+
+	return cmd
+}
+
+func runBaseline(cmd *cobra.Command, args []string, opts *BaselineOptions) error {
+	configPath := args[0]
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cfg, err := config.Load(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	var rules []*config.RuleConfig
+	var learners []*baseline.Learner
+	for i := range cfg.Rules {
+		rule := &cfg.Rules[i]
+		if rule.RuleTypeEnum() != config.RuleTypeBaseline {
+			continue
+		}
+		if len(opts.Rules) > 0 && !slices.Contains(opts.Rules, rule.Name) {
+			continue
+		}
+		rules = append(rules, rule)
+		learners = append(learners, baseline.NewLearner(rule.Name, rule.CompiledPattern(), rule.Location()))
+	}
+	if len(rules) == 0 {
+		return fmt.Errorf("no baseline rules to build (check --rule filter)")
+	}
+
+	patterns := cfg.LogSources
+	if len(opts.Logs) > 0 {
+		patterns = opts.Logs
+	}
+	files, err := parser.ExpandGlobs(patterns)
+	if err != nil {
+		return fmt.Errorf("expanding log sources: %w", err)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no log files matched patterns: %v", patterns)
+	}
+
+	source := newLogSource(cfg, files)
+	defer source.Close()
+
+	for {
+		line, err := source.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("reading log source: %w", err)
+		}
+		for i, l := range learners {
+			if rules[i].CoversSource(line.Source) {
+				l.Add(line)
+			}
+		}
+	}
+
+	for i, rule := range rules {
+		b := learners[i].Baseline()
+		if err := b.Save(rule.BaselineFile); err != nil {
+			return fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+
+		hours := 0
+		for _, slot := range b.Slots {
+			hours += slot.Samples
+		}
+		fmt.Printf("%s: learned %d hour(s) from %s to %s, wrote %s\n",
+			rule.Name, hours,
+			b.Start.Format("2006-01-02 15:04"), b.End.Format("2006-01-02 15:04"),
+			rule.BaselineFile)
+	}
+
+	return nil
+}
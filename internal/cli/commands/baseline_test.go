@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ccollicutt/negalog/pkg/baseline"
+)
+
+func TestRunBaseline(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	logPath := filepath.Join(tmpDir, "orders.log")
+	baselinePath := filepath.Join(tmpDir, "orders.json")
+
+	// Two weeks of history with 3 orders every hour
+	var logs strings.Builder
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for h := 0; h < 2*baseline.HoursPerWeek; h++ {
+		hour := start.Add(time.Duration(h) * time.Hour)
+		for i := 0; i < 3; i++ {
+			fmt.Fprintf(&logs, "%s ORDER_ACCEPTED\n", hour.Add(time.Duration(i)*time.Minute).Format(time.RFC3339))
+		}
+	}
+	fmt.Fprintf(&logs, "%s end\n", start.Add(2*7*24*time.Hour).Format(time.RFC3339))
+	if err := os.WriteFile(logPath, []byte(logs.String()), 0644); err != nil {
+		t.Fatalf("Failed to create log file: %v", err)
+	}
+
+	config := `log_sources:
+  - ` + logPath + `
+timestamp_format:
+  pattern: '^(\S+)'
+  layout: "2006-01-02T15:04:05Z07:00"
+rules:
+  - name: order-volume
+    type: baseline
+    pattern: 'ORDER_ACCEPTED'
+    baseline_file: ` + baselinePath + `
+    min_fraction: 0.5
+`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	cmd := NewBaselineCommand()
+	cmd.SetArgs([]string{configPath})
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("baseline failed: %v", err)
+	}
+
+	b, err := baseline.Load(baselinePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	for i, slot := range b.Slots {
+		if slot.Samples != 2 || slot.Mean != 3 || slot.StdDev != 0 {
+			t.Fatalf("Slots[%d] = %+v, want 2 samples of 3", i, slot)
+		}
+	}
+}
+
+func TestRunBaseline_NoBaselineRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	config := `log_sources:
+  - /tmp/*.log
+timestamp_format:
+  pattern: '^(\d{4})'
+  layout: "2006"
+rules:
+  - name: test
+    type: periodic
+    pattern: 'x'
+    max_gap: 1h
+`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	cmd := NewBaselineCommand()
+	cmd.SetArgs([]string{configPath})
+
+	err := cmd.ExecuteContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "no baseline rules") {
+		t.Errorf("Expected 'no baseline rules' error, got: %v", err)
+	}
+}
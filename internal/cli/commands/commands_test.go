@@ -3,10 +3,12 @@ package commands
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ccollicutt/negalog/pkg/detector"
 )
@@ -106,6 +108,41 @@ func TestRunValidate_InvalidConfig(t *testing.T) {
 	}
 }
 
+func TestRunValidate_RosterCommandNotRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	marker := filepath.Join(tmpDir, "ran")
+
+	config := `log_sources:
+  - /tmp/*.log
+timestamp_format:
+  pattern: '^(\d{4})'
+  layout: "2006"
+rules:
+  - name: heartbeats
+    type: periodic
+    pattern: 'HEARTBEAT host=(?P<host>\S+)'
+    max_gap: 1h
+    group_by: host
+    roster:
+      command: 'touch ` + marker + `'
+`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	cmd := NewValidateCommand()
+	cmd.SetArgs([]string{configPath})
+	cmd.SetOut(&bytes.Buffer{})
+
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Error("validate ran the roster command")
+	}
+}
+
 func TestRunValidate_MissingFile(t *testing.T) {
 	cmd := NewValidateCommand()
 	cmd.SetArgs([]string{"/nonexistent/config.yaml"})
@@ -157,6 +194,107 @@ rules:
 	}
 }
 
+func TestRunAnalyze_RosterCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	config := `log_sources:
+  - /tmp/*.log
+timestamp_format:
+  pattern: '^(\d{4})'
+  layout: "2006"
+rules:
+  - name: heartbeats
+    type: periodic
+    pattern: 'HEARTBEAT host=(?P<host>\S+)'
+    max_gap: 1h
+    group_by: host
+    roster:
+      command: 'echo inventory down >&2; exit 1'
+`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	cmd := NewAnalyzeCommand()
+	cmd.SetArgs([]string{configPath})
+
+	err := cmd.ExecuteContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "inventory down") {
+		t.Errorf("error = %v, want the roster command's failure", err)
+	}
+}
+
+func TestRunAnalyze_TimeRangeEndsAtNow(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	logPath := filepath.Join(tmpDir, "app.log")
+
+	logs := `2024-01-15T07:30:00Z HEARTBEAT
+2024-01-15T08:30:00Z HEARTBEAT
+2024-01-15T09:30:00Z HEARTBEAT
+2024-01-15T10:30:00Z HEARTBEAT
+`
+	if err := os.WriteFile(logPath, []byte(logs), 0644); err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	config := `log_sources:
+  - ` + logPath + `
+timestamp_format:
+  pattern: '^(\S+)'
+  layout: "2006-01-02T15:04:05Z07:00"
+rules:
+  - name: heartbeat
+    type: periodic
+    pattern: 'HEARTBEAT'
+    max_gap: 2h
+`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	cmd := NewAnalyzeCommand()
+	cmd.SetArgs([]string{"--now", "2024-01-15T10:00:00Z", "--time-range", "2h", "-o", "json", configPath})
+
+	// Capture stdout
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := cmd.ExecuteContext(context.Background())
+
+	w.Close()
+	os.Stdout = old
+
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(r)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var report struct {
+		Summary  struct{ LinesProcessed int }
+		Metadata struct {
+			TimeRange struct{ Start, End time.Time }
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("Invalid JSON output: %v\n%s", err, buf.String())
+	}
+
+	// Only the 08:30 and 09:30 lines fall within 08:00-10:00
+	if report.Summary.LinesProcessed != 2 {
+		t.Errorf("LinesProcessed = %d, want 2", report.Summary.LinesProcessed)
+	}
+	wantStart := time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC)
+	wantEnd := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	if tr := report.Metadata.TimeRange; !tr.Start.Equal(wantStart) || !tr.End.Equal(wantEnd) {
+		t.Errorf("TimeRange = %v - %v, want %v - %v", tr.Start, tr.End, wantStart, wantEnd)
+	}
+}
+
 func TestCreateFormatter(t *testing.T) {
 	tests := []struct {
 		output  string
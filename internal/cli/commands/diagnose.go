@@ -407,6 +407,9 @@ func checkRules(cfg *config.Config) []DiagnosticResult {
 		return results
 	}
 
+	// Log source problems are reported by checkLogSources
+	files, _ := parser.ExpandGlobs(cfg.LogSources)
+
 	for _, rule := range cfg.Rules {
 		result := DiagnosticResult{
 			Check: fmt.Sprintf("Rule: %s", rule.Name),
@@ -418,13 +421,15 @@ func checkRules(cfg *config.Config) []DiagnosticResult {
 		// Check rule type
 		switch rule.Type {
 		case "sequence":
-			if rule.StartPattern == "" {
-				issues = append(issues, "Missing start_pattern")
-			}
-			if rule.EndPattern == "" {
-				issues = append(issues, "Missing end_pattern")
+			if len(rule.Steps) == 0 {
+				if rule.StartPattern == "" {
+					issues = append(issues, "Missing start_pattern")
+				}
+				if rule.EndPattern == "" {
+					issues = append(issues, "Missing end_pattern")
+				}
 			}
-			if rule.CorrelationField == 0 {
+			if len(rule.SequenceCorrelationKey()) == 0 {
 				warnings = append(warnings, "No correlation_field - all sequences will be matched together")
 			}
 			if rule.Timeout == 0 {
@@ -434,21 +439,71 @@ func checkRules(cfg *config.Config) []DiagnosticResult {
 			if rule.Pattern == "" {
 				issues = append(issues, "Missing pattern")
 			}
-			if rule.MaxGap == 0 {
-				issues = append(issues, "Missing max_gap")
+			if rule.MaxGap == 0 && rule.Schedule == "" {
+				issues = append(issues, "Missing max_gap or schedule")
 			}
 		case "conditional":
 			if rule.TriggerPattern == "" {
 				issues = append(issues, "Missing trigger_pattern")
 			}
-			if rule.ExpectedPattern == "" {
-				issues = append(issues, "Missing expected_pattern")
+			if rule.ExpectedPattern == "" && rule.ForbiddenPattern == "" && rule.PrecededBy == "" {
+				issues = append(issues, "Missing expected_pattern, forbidden_pattern, or preceded_by")
 			}
 			if rule.Timeout == 0 {
 				issues = append(issues, "Missing timeout")
 			}
+		case "rate":
+			if rule.Pattern == "" {
+				issues = append(issues, "Missing pattern")
+			}
+			if rule.Window == 0 {
+				issues = append(issues, "Missing window")
+			}
+			if rule.MinCount == 0 {
+				issues = append(issues, "Missing min_count")
+			}
+		case "baseline":
+			if rule.Pattern == "" {
+				issues = append(issues, "Missing pattern")
+			}
+			if rule.BaselineFile == "" {
+				issues = append(issues, "Missing baseline_file")
+			} else if _, err := os.Stat(rule.BaselineFile); err != nil {
+				warnings = append(warnings, "Baseline file not found - build it with 'negalog baseline'")
+			}
+			if rule.MinFraction == 0 && rule.MaxDeviations == 0 {
+				issues = append(issues, "Missing min_fraction or max_deviations")
+			}
+		case "source_silence":
+			if rule.MaxSilence == 0 {
+				issues = append(issues, "Missing max_silence")
+			}
+		case "ordering":
+			if rule.BeforePattern == "" || rule.AfterPattern == "" {
+				issues = append(issues, "Missing before_pattern or after_pattern")
+			}
+		case "reconciliation":
+			if rule.LeftPattern == "" || rule.RightPattern == "" {
+				issues = append(issues, "Missing left_pattern or right_pattern")
+			}
+			if rule.Window == 0 {
+				issues = append(issues, "Missing window")
+			}
 		default:
-			issues = append(issues, fmt.Sprintf("Unknown rule type: %s (expected: sequence, periodic, conditional)", rule.Type))
+			issues = append(issues, fmt.Sprintf("Unknown rule type: %s (expected: sequence, periodic, conditional, rate, reconciliation, baseline, source_silence, ordering)", rule.Type))
+		}
+
+		if len(rule.Sources) > 0 || len(rule.MatchLabels) > 0 {
+			covered := false
+			for _, file := range files {
+				if rule.CoversSource(file) {
+					covered = true
+					break
+				}
+			}
+			if !covered {
+				warnings = append(warnings, "sources/match_labels cover none of the log files - the rule will see no lines")
+			}
 		}
 
 		if len(issues) > 0 {
@@ -265,6 +265,47 @@ rules:
 	}
 }
 
+func TestCheckRules_StepCorrelatedSequence(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	logPath := filepath.Join(tmpDir, "test.log")
+
+	if err := os.WriteFile(logPath, []byte("test"), 0644); err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	// Correlated per step, with no rule-level correlation
+	config := `log_sources:
+  - ` + logPath + `
+timestamp_format:
+  pattern: '^(\d{4})'
+  layout: "2006"
+rules:
+  - name: checkout
+    type: sequence
+    steps:
+      - pattern: 'CART id=(?P<id>\w+)'
+        correlation_groups: [id]
+      - pattern: 'PAID id=(?P<id>\w+)'
+        correlation_groups: [id]
+    timeout: 1h
+`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	cfg, result := checkConfigParseable(configPath)
+	if cfg == nil {
+		t.Fatalf("Config parsing failed: %s", result.Message)
+	}
+
+	for _, r := range checkRules(cfg) {
+		if r.Check == "Rule: checkout" && r.Status != "ok" {
+			t.Errorf("status = %s, want ok: %v", r.Status, r.Details)
+		}
+	}
+}
+
 func TestCheckRules_PeriodicRule(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "config.yaml")
@@ -311,6 +352,49 @@ rules:
 	}
 }
 
+func TestCheckRules_SourcesCoverNoFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	logPath := filepath.Join(tmpDir, "test.log")
+
+	if err := os.WriteFile(logPath, []byte("test"), 0644); err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	config := `log_sources:
+  - ` + logPath + `
+timestamp_format:
+  pattern: '^(\d{4})'
+  layout: "2006"
+rules:
+  - name: covered
+    type: periodic
+    pattern: 'HEARTBEAT'
+    max_gap: 5m
+    sources: ['` + filepath.Join(tmpDir, "*.log") + `']
+  - name: uncovered
+    type: periodic
+    pattern: 'HEARTBEAT'
+    max_gap: 5m
+    sources: ['` + filepath.Join(tmpDir, "staging-*.log") + `']
+`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	cfg, result := checkConfigParseable(configPath)
+	if cfg == nil {
+		t.Fatalf("Config parsing failed: %s", result.Message)
+	}
+
+	want := map[string]string{"Rule: covered": "ok", "Rule: uncovered": "warning"}
+	for _, r := range checkRules(cfg) {
+		if status, ok := want[r.Check]; ok && r.Status != status {
+			t.Errorf("%s: status = %s, want %s: %v", r.Check, r.Status, status, r.Details)
+		}
+	}
+}
+
 func TestTruncate(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -93,6 +93,7 @@ PLUGINS:
 
 	// Add subcommands
 	rootCmd.AddCommand(commands.NewAnalyzeCommand())
+	rootCmd.AddCommand(commands.NewBaselineCommand())
 	rootCmd.AddCommand(commands.NewDetectCommand())
 	rootCmd.AddCommand(commands.NewDiagnoseCommand())
 	rootCmd.AddCommand(commands.NewValidateCommand())
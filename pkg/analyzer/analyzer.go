@@ -14,12 +14,18 @@ import (
 type Analyzer struct {
 	cfg     *config.Config
 	engines []RuleEngine
+	rules   []*config.RuleConfig // rule of each engine
+	scopes  map[string][]bool    // per source file, whether each engine sees its lines
 
 	// Options
-	timeRange  *TimeRange
-	ruleFilter map[string]bool // nil means all rules
-	verbose    bool
-	keepState  bool // don't reset engines between analyses
+	timeRange   *TimeRange
+	now         time.Time       // evaluation clock override, zero means last log timestamp
+	ruleFilter  map[string]bool // nil means all rules
+	minSeverity config.Severity // empty means all severities
+	tags        []string        // nil means all rules
+	verbose     bool
+	keepState   bool     // don't reset engines between analyses
+	files       []string // log files that matched log_sources, nil means those seen
 }
 
 // TimeRange defines a time window for filtering log lines.
@@ -38,6 +44,14 @@ func WithTimeRange(start, end time.Time) AnalyzerOption {
 	}
 }
 
+// WithNow sets the evaluation clock used to decide whether open expectations
+// have timed out. By default the timestamp of the last log line is used.
+func WithNow(now time.Time) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.now = now
+	}
+}
+
 // WithRuleFilter limits analysis to the specified rules.
 func WithRuleFilter(rules []string) AnalyzerOption {
 	return func(a *Analyzer) {
@@ -50,6 +64,22 @@ func WithRuleFilter(rules []string) AnalyzerOption {
 	}
 }
 
+// WithMinSeverity limits analysis to rules of at least the given severity.
+func WithMinSeverity(min config.Severity) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.minSeverity = min
+	}
+}
+
+// WithTags limits analysis to rules that have any of the given tags.
+func WithTags(tags []string) AnalyzerOption {
+	return func(a *Analyzer) {
+		if len(tags) > 0 {
+			a.tags = tags
+		}
+	}
+}
+
 // WithVerbose enables verbose output.
 func WithVerbose(v bool) AnalyzerOption {
 	return func(a *Analyzer) {
@@ -65,6 +95,15 @@ func WithKeepState(keep bool) AnalyzerOption {
 	}
 }
 
+// WithSources sets the log files that matched log_sources, so files that
+// produced no parseable lines can be reported. By default only the files
+// lines were read from are known.
+func WithSources(files []string) AnalyzerOption {
+	return func(a *Analyzer) {
+		a.files = files
+	}
+}
+
 // NewAnalyzer creates a new analyzer from configuration.
 func NewAnalyzer(cfg *config.Config, opts ...AnalyzerOption) (*Analyzer, error) {
 	a := &Analyzer{
@@ -81,8 +120,8 @@ func NewAnalyzer(cfg *config.Config, opts ...AnalyzerOption) (*Analyzer, error)
 	for i := range cfg.Rules {
 		rule := &cfg.Rules[i]
 
-		// Skip if rule filter is active and this rule isn't included
-		if a.ruleFilter != nil && !a.ruleFilter[rule.Name] {
+		// Skip rules excluded by the rule, severity or tag filters
+		if !a.selected(rule) {
 			continue
 		}
 
@@ -91,15 +130,30 @@ func NewAnalyzer(cfg *config.Config, opts ...AnalyzerOption) (*Analyzer, error)
 			return nil, fmt.Errorf("creating engine for rule %q: %w", rule.Name, err)
 		}
 		a.engines = append(a.engines, engine)
+		a.rules = append(a.rules, rule)
 	}
 
 	if len(a.engines) == 0 {
-		return nil, fmt.Errorf("no rules to execute (check --rule filter)")
+		return nil, fmt.Errorf("no rules to execute (check --rule, --min-severity and --tag filters)")
 	}
 
 	return a, nil
 }
 
+// selected returns true if a rule passes every filter that is set.
+func (a *Analyzer) selected(rule *config.RuleConfig) bool {
+	if a.ruleFilter != nil && !a.ruleFilter[rule.Name] {
+		return false
+	}
+	if a.minSeverity != "" && !rule.Severity.AtLeast(a.minSeverity) {
+		return false
+	}
+	if a.tags != nil && !rule.HasTag(a.tags...) {
+		return false
+	}
+	return true
+}
+
 // createEngine creates the appropriate rule engine based on rule type.
 func createEngine(rule *config.RuleConfig) (RuleEngine, error) {
 	switch rule.RuleTypeEnum() {
@@ -109,6 +163,16 @@ func createEngine(rule *config.RuleConfig) (RuleEngine, error) {
 		return NewPeriodicEngine(rule)
 	case config.RuleTypeConditional:
 		return NewConditionalEngine(rule)
+	case config.RuleTypeRate:
+		return NewRateEngine(rule)
+	case config.RuleTypeReconciliation:
+		return NewReconciliationEngine(rule)
+	case config.RuleTypeBaseline:
+		return NewBaselineEngine(rule)
+	case config.RuleTypeSourceSilence:
+		return NewSourceSilenceEngine(rule)
+	case config.RuleTypeOrdering:
+		return NewOrderingEngine(rule)
 	default:
 		return nil, fmt.Errorf("unknown rule type: %s", rule.Type)
 	}
@@ -158,6 +222,9 @@ type EngineState struct {
 	Sequences []SequenceState `json:"sequences,omitempty"`
 	Triggers  []TriggerState  `json:"triggers,omitempty"`
 	Periodic  *PeriodicState  `json:"periodic,omitempty"`
+
+	// Preceding holds the preceded_by index of conditional rules.
+	Preceding []TriggerState `json:"preceding,omitempty"`
 }
 
 // TotalIssues returns the total number of issues across all rules.
@@ -169,6 +236,16 @@ func (r *AnalysisResult) TotalIssues() int {
 	return total
 }
 
+// TotalSuppressed returns the number of issues suppressed by maintenance
+// windows across all rules.
+func (r *AnalysisResult) TotalSuppressed() int {
+	total := 0
+	for _, result := range r.Results {
+		total += len(result.Suppressed)
+	}
+	return total
+}
+
 // RulesWithIssues returns the count of rules that detected issues.
 func (r *AnalysisResult) RulesWithIssues() int {
 	count := 0
@@ -200,6 +277,9 @@ func (a *Analyzer) Analyze(ctx context.Context, source parser.LogSource) (*Analy
 	// Track sources seen
 	sourcesMap := make(map[string]bool)
 
+	// Track the span of processed log timestamps for the analysis window
+	var window TimeRange
+
 	// Process all log lines
 	for {
 		select {
@@ -231,20 +311,59 @@ func (a *Analyzer) Analyze(ctx context.Context, source parser.LogSource) (*Analy
 
 		result.Metadata.LinesProcessed++
 
-		// Process line through all engines
-		for _, engine := range a.engines {
+		if result.Metadata.LinesProcessed == 1 || line.Timestamp.Before(window.Start) {
+			window.Start = line.Timestamp
+		}
+		if result.Metadata.LinesProcessed == 1 || line.Timestamp.After(window.End) {
+			window.End = line.Timestamp
+		}
+
+		// Process line through the engines whose rules cover its source
+		scope := a.scope(line.Source)
+		for i, engine := range a.engines {
+			if !scope[i] {
+				continue
+			}
 			if err := engine.Process(ctx, line); err != nil {
 				return nil, fmt.Errorf("processing line with rule %q: %w", engine.Name(), err)
 			}
 		}
 	}
 
-	// Finalize all engines
+	// Provide the analysis window to engines that evaluate against a clock
+	window = a.window(window)
 	for _, engine := range a.engines {
+		if wa, ok := engine.(WindowAware); ok {
+			wa.SetWindow(window)
+		}
+	}
+
+	// Provide the log files to engines that judge sources
+	files := a.files
+	if files == nil {
+		files = result.Metadata.Sources
+	}
+	for i, engine := range a.engines {
+		if sa, ok := engine.(SourceAware); ok {
+			var covered []string
+			for _, file := range files {
+				if a.scope(file)[i] {
+					covered = append(covered, file)
+				}
+			}
+			sa.SetSources(covered, sourcesMap)
+		}
+	}
+
+	// Finalize all engines
+	for i, engine := range a.engines {
 		ruleResult, err := engine.Finalize(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("finalizing rule %q: %w", engine.Name(), err)
 		}
+		ruleResult.Severity = a.rules[i].Severity
+		ruleResult.Tags = a.rules[i].Tags
+		a.suppress(a.rules[i], ruleResult)
 		result.Results = append(result.Results, ruleResult)
 	}
 
@@ -253,6 +372,98 @@ func (a *Analyzer) Analyze(ctx context.Context, source parser.LogSource) (*Analy
 	return result, nil
 }
 
+// scope returns, for each engine, whether its rule covers the source file.
+// Results are cached per file.
+func (a *Analyzer) scope(source string) []bool {
+	if scope, ok := a.scopes[source]; ok {
+		return scope
+	}
+
+	scope := make([]bool, len(a.rules))
+	for i, rule := range a.rules {
+		scope[i] = rule.CoversSource(source)
+	}
+	if a.scopes == nil {
+		a.scopes = make(map[string][]bool)
+	}
+	a.scopes[source] = scope
+	return scope
+}
+
+// suppress moves issues that overlap a maintenance window from Issues to
+// Suppressed.
+func (a *Analyzer) suppress(rule *config.RuleConfig, result *RuleResult) {
+	if len(a.cfg.MaintenanceWindows) == 0 {
+		return
+	}
+
+	issues := result.Issues[:0]
+	for _, issue := range result.Issues {
+		if name := a.maintenanceWindow(rule, &issue); name != "" {
+			issue.SuppressedBy = name
+			result.Suppressed = append(result.Suppressed, issue)
+			continue
+		}
+		issues = append(issues, issue)
+	}
+	result.Issues = issues
+}
+
+// maintenanceWindow returns the name of the first maintenance window an
+// issue of the rule overlaps, or "" if none. Issues with no time, such as
+// empty sources, are never suppressed.
+func (a *Analyzer) maintenanceWindow(rule *config.RuleConfig, issue *Issue) string {
+	start := issue.Context.StartTime
+	if start.IsZero() {
+		start = issue.Context.ScheduledTime
+	}
+	if start.IsZero() {
+		return ""
+	}
+	end := start
+	if issue.Context.EndTime.After(start) {
+		end = issue.Context.EndTime
+	} else if timedOut(issue.Type) {
+		if deadline := rule.ActiveCalendar().Add(start, issue.Context.Timeout); deadline.After(start) {
+			end = deadline
+		}
+	}
+
+	labels := a.cfg.SourceLabels.For(issue.Context.Source)
+	for i := range a.cfg.MaintenanceWindows {
+		m := &a.cfg.MaintenanceWindows[i]
+		if m.Applies(rule.Name, labels) && m.Overlaps(start, end) {
+			return m.Name
+		}
+	}
+	return ""
+}
+
+// timedOut returns true for issues of expectations that were still open at
+// their timeout, which span from their start to that deadline.
+func timedOut(t IssueType) bool {
+	switch t {
+	case IssueTypeMissingEnd, IssueTypeMissingConsequence, IssueTypeConsequenceCount:
+		return true
+	default:
+		return false
+	}
+}
+
+// window resolves the analysis window from the span of processed lines.
+// The --time-range bounds and the --now clock take precedence when set.
+func (a *Analyzer) window(seen TimeRange) TimeRange {
+	window := seen
+	if a.timeRange != nil {
+		window.Start = a.timeRange.Start
+		window.End = a.timeRange.End
+	}
+	if !a.now.IsZero() {
+		window.End = a.now
+	}
+	return window
+}
+
 // ExportState exports the current state of all engines for persistence.
 // This allows state to be saved and restored across process restarts.
 func (a *Analyzer) ExportState() *AnalyzerState {
@@ -274,6 +485,7 @@ func (a *Analyzer) ExportState() *AnalyzerState {
 		case *ConditionalEngine:
 			es.Type = config.RuleTypeConditional
 			es.Triggers = e.ExportState()
+			es.Preceding = e.ExportPreceding()
 		}
 
 		state.Engines = append(state.Engines, es)
@@ -309,6 +521,7 @@ func (a *Analyzer) ImportState(state *AnalyzerState) {
 			e.ImportState(es.Periodic)
 		case *ConditionalEngine:
 			e.ImportState(es.Triggers)
+			e.ImportPreceding(es.Preceding)
 		}
 	}
 }
@@ -3,6 +3,7 @@ package analyzer
 import (
 	"context"
 	"io"
+	"reflect"
 	"testing"
 	"time"
 
@@ -69,12 +70,14 @@ func TestNewAnalyzer_NoRules(t *testing.T) {
 func TestAnalyzer_Analyze(t *testing.T) {
 	cfg := createTestConfig(t)
 
-	a, err := NewAnalyzer(cfg)
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// Evaluate after def's 60s timeout has elapsed
+	a, err := NewAnalyzer(cfg, WithNow(baseTime.Add(2*time.Minute)))
 	if err != nil {
 		t.Fatalf("NewAnalyzer() error = %v", err)
 	}
 
-	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
 	source := &mockSource{
 		lines: []*parser.ParsedLine{
 			{Raw: "START id=abc", Timestamp: baseTime, Source: "test.log", LineNum: 1},
@@ -98,6 +101,127 @@ func TestAnalyzer_Analyze(t *testing.T) {
 	}
 }
 
+func TestAnalyzer_PendingAtLastLine(t *testing.T) {
+	cfg := createTestConfig(t)
+
+	a, err := NewAnalyzer(cfg)
+	if err != nil {
+		t.Fatalf("NewAnalyzer() error = %v", err)
+	}
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	source := &mockSource{
+		lines: []*parser.ParsedLine{
+			{Raw: "START id=old", Timestamp: baseTime, Source: "test.log", LineNum: 1},
+			{Raw: "START id=new", Timestamp: baseTime.Add(90 * time.Second), Source: "test.log", LineNum: 2},
+			{Raw: "OTHER", Timestamp: baseTime.Add(92 * time.Second), Source: "test.log", LineNum: 3},
+		},
+	}
+
+	result, err := a.Analyze(context.Background(), source)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	// The clock is the last log line: old has timed out, new is still pending
+	rr := result.Results[0]
+	if len(rr.Issues) != 1 || rr.Issues[0].Context.CorrelationID != "old" {
+		t.Errorf("Issues = %+v, want only old", rr.Issues)
+	}
+	if rr.Stats.Pending != 1 {
+		t.Errorf("Pending = %d, want 1", rr.Stats.Pending)
+	}
+
+	states := a.ExportState().Engines[0].Sequences
+	pending := 0
+	for _, s := range states {
+		if s.Pending {
+			pending++
+			if s.CorrelationID != "new" {
+				t.Errorf("pending sequence = %q, want %q", s.CorrelationID, "new")
+			}
+		}
+	}
+	if pending != 1 {
+		t.Errorf("exported pending sequences = %d, want 1", pending)
+	}
+}
+
+func TestAnalyzer_ExportState_Preceding(t *testing.T) {
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:             "refunds",
+			Type:             "conditional",
+			TriggerPattern:   `REFUND_ISSUED order=(\w+)`,
+			PrecededBy:       `REFUND_APPROVED order=(\w+)`,
+			CorrelationField: 1,
+			Timeout:          24 * time.Hour,
+		}},
+	}
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	first, err := NewAnalyzer(cfg)
+	if err != nil {
+		t.Fatalf("NewAnalyzer() error = %v", err)
+	}
+	if _, err := first.Analyze(context.Background(), &mockSource{lines: []*parser.ParsedLine{
+		{Raw: "REFUND_APPROVED order=a1", Timestamp: baseTime},
+	}}); err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	second, err := NewAnalyzer(cfg, WithKeepState(true))
+	if err != nil {
+		t.Fatalf("NewAnalyzer() error = %v", err)
+	}
+	second.ImportState(first.ExportState())
+
+	result, err := second.Analyze(context.Background(), &mockSource{lines: []*parser.ParsedLine{
+		{Raw: "REFUND_ISSUED order=a1", Timestamp: baseTime.Add(time.Hour)},
+		{Raw: "REFUND_ISSUED order=b2", Timestamp: baseTime.Add(time.Hour)},
+	}})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	issues := result.Results[0].Issues
+	if len(issues) != 1 || issues[0].Context.CorrelationID != "b2" {
+		t.Errorf("Issues = %+v, want only b2", issues)
+	}
+}
+
+func TestAnalyzer_WithNow(t *testing.T) {
+	cfg := createTestConfig(t)
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	a, err := NewAnalyzer(cfg, WithNow(baseTime.Add(5*time.Minute)))
+	if err != nil {
+		t.Fatalf("NewAnalyzer() error = %v", err)
+	}
+
+	source := &mockSource{
+		lines: []*parser.ParsedLine{
+			{Raw: "START id=abc", Timestamp: baseTime, Source: "test.log", LineNum: 1},
+		},
+	}
+
+	result, err := a.Analyze(context.Background(), source)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if result.TotalIssues() != 1 {
+		t.Errorf("TotalIssues() = %d, want 1 (timed out relative to --now)", result.TotalIssues())
+	}
+}
+
 func TestAnalyzer_WithTimeRange(t *testing.T) {
 	cfg := createTestConfig(t)
 
@@ -138,6 +262,44 @@ func TestAnalyzer_WithTimeRange(t *testing.T) {
 	}
 }
 
+func TestAnalyzer_WithSources(t *testing.T) {
+	cfg := &config.Config{
+		LogSources:      []string{"*.log"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:       "sources",
+			Type:       "source_silence",
+			MaxSilence: time.Minute,
+		}},
+	}
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	a, err := NewAnalyzer(cfg, WithSources([]string{"app.log", "empty.log"}))
+	if err != nil {
+		t.Fatalf("NewAnalyzer() error = %v", err)
+	}
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	source := &mockSource{
+		lines: []*parser.ParsedLine{
+			{Raw: "started", Timestamp: baseTime, Source: "app.log", LineNum: 1},
+			{Raw: "running", Timestamp: baseTime.Add(30 * time.Second), Source: "app.log", LineNum: 2},
+		},
+	}
+
+	result, err := a.Analyze(context.Background(), source)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	issues := result.Results[0].Issues
+	if len(issues) != 1 || issues[0].Type != IssueTypeEmptySource || issues[0].Context.Source != "empty.log" {
+		t.Errorf("Issues = %v, want empty.log empty_source", issues)
+	}
+}
+
 func TestAnalyzer_WithRuleFilter(t *testing.T) {
 	cfg := &config.Config{
 		LogSources:      []string{"/tmp"},
@@ -192,6 +354,131 @@ func TestAnalyzer_WithRuleFilter(t *testing.T) {
 	}
 }
 
+func TestAnalyzer_SeverityAndTags(t *testing.T) {
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{
+			{Name: "debug", Type: "periodic", Pattern: `DEBUG`, Severity: config.SeverityInfo},
+			{Name: "payments", Type: "periodic", Pattern: `PAYMENT`, Severity: config.SeverityCritical, Tags: []string{"payments", "prod"}},
+			{Name: "heartbeat", Type: "periodic", Pattern: `HEARTBEAT`, Tags: []string{"prod"}},
+		},
+	}
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts []AnalyzerOption
+		want []string
+	}{
+		{"all", nil, []string{"debug", "payments", "heartbeat"}},
+		{"min severity", []AnalyzerOption{WithMinSeverity(config.SeverityWarning)}, []string{"payments", "heartbeat"}},
+		{"tag", []AnalyzerOption{WithTags([]string{"payments"})}, []string{"payments"}},
+		{"any tag", []AnalyzerOption{WithTags([]string{"payments", "prod"})}, []string{"payments", "heartbeat"}},
+		{
+			"severity and tag",
+			[]AnalyzerOption{WithMinSeverity(config.SeverityCritical), WithTags([]string{"prod"})},
+			[]string{"payments"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAnalyzer(cfg, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewAnalyzer() error = %v", err)
+			}
+
+			result, err := a.Analyze(context.Background(), &mockSource{})
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+
+			var names []string
+			for _, r := range result.Results {
+				names = append(names, r.RuleName)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("rules = %v, want %v", names, tt.want)
+			}
+		})
+	}
+
+	a, err := NewAnalyzer(cfg, WithRuleFilter([]string{"payments"}))
+	if err != nil {
+		t.Fatalf("NewAnalyzer() error = %v", err)
+	}
+	result, err := a.Analyze(context.Background(), &mockSource{})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if r := result.Results[0]; r.Severity != config.SeverityCritical || !reflect.DeepEqual(r.Tags, []string{"payments", "prod"}) {
+		t.Errorf("Severity = %q, Tags = %v, want critical [payments prod]", r.Severity, r.Tags)
+	}
+
+	if _, err := NewAnalyzer(cfg, WithTags([]string{"staging"})); err == nil {
+		t.Error("NewAnalyzer() error = nil, want no rules to execute")
+	}
+}
+
+func TestAnalyzer_SourceScope(t *testing.T) {
+	cfg := &config.Config{
+		LogSources: []string{"*.log"},
+		SourceLabels: config.SourceLabels{
+			{Path: "prod-*.log", Labels: map[string]string{"env": "prod"}},
+			{Path: "staging-*.log", Labels: map[string]string{"env": "staging"}},
+		},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{
+			{Name: "prod-heartbeat", Type: "periodic", Pattern: `HEARTBEAT`, MaxGap: time.Minute, MatchLabels: map[string]string{"env": "prod"}},
+			{Name: "any-heartbeat", Type: "periodic", Pattern: `HEARTBEAT`, MaxGap: time.Minute},
+			{Name: "prod-files", Type: "source_silence", MaxSilence: time.Hour, Sources: []string{"prod-*.log"}},
+		},
+	}
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	a, err := NewAnalyzer(cfg, WithSources([]string{"prod-api.log", "prod-db.log", "staging-api.log", "staging-db.log"}))
+	if err != nil {
+		t.Fatalf("NewAnalyzer() error = %v", err)
+	}
+
+	// Prod stops after 10:00 while staging carries on
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	source := &mockSource{
+		lines: []*parser.ParsedLine{
+			{Raw: "HEARTBEAT", Timestamp: baseTime, Source: "prod-api.log", LineNum: 1},
+			{Raw: "HEARTBEAT", Timestamp: baseTime.Add(30 * time.Second), Source: "staging-api.log", LineNum: 1},
+			{Raw: "HEARTBEAT", Timestamp: baseTime.Add(60 * time.Second), Source: "staging-api.log", LineNum: 2},
+			{Raw: "HEARTBEAT", Timestamp: baseTime.Add(90 * time.Second), Source: "staging-api.log", LineNum: 3},
+		},
+	}
+
+	result, err := a.Analyze(context.Background(), source)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	prod, anyEnv, files := result.Results[0], result.Results[1], result.Results[2]
+	if prod.Stats.LinesProcessed != 1 {
+		t.Errorf("prod-heartbeat LinesProcessed = %d, want 1", prod.Stats.LinesProcessed)
+	}
+	if len(prod.Issues) != 1 || prod.Issues[0].Type != IssueTypeTrailingSilence {
+		t.Errorf("prod-heartbeat Issues = %v, want trailing_silence", prod.Issues)
+	}
+	if len(anyEnv.Issues) != 0 {
+		t.Errorf("any-heartbeat Issues = %v, want none", anyEnv.Issues)
+	}
+
+	// Only the prod files are judged, and only prod-db.log is empty
+	if len(files.Issues) != 1 || files.Issues[0].Type != IssueTypeEmptySource || files.Issues[0].Context.Source != "prod-db.log" {
+		t.Errorf("prod-files Issues = %v, want prod-db.log empty_source", files.Issues)
+	}
+}
+
 func TestAnalyzer_ContextCancellation(t *testing.T) {
 	cfg := createTestConfig(t)
 
@@ -215,11 +502,117 @@ func TestAnalyzer_ContextCancellation(t *testing.T) {
 	}
 }
 
+func TestAnalyzer_MaintenanceWindows(t *testing.T) {
+	cfg := createTestConfig(t)
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	cfg.SourceLabels = config.SourceLabels{
+		{Path: "db.log", Labels: map[string]string{"service": "db"}},
+	}
+	cfg.MaintenanceWindows = []config.MaintenanceWindow{
+		// Other rules only
+		{Name: "elsewhere", Start: baseTime, End: baseTime.Add(time.Hour), Rules: []string{"other"}},
+		// The db's nightly restart at 10:00
+		{Name: "db-restart", Schedule: "0 10 * * *", Duration: 15 * time.Minute, Labels: map[string]string{"service": "db"}},
+	}
+	cfg.Rules = append(cfg.Rules, config.RuleConfig{Name: "other", Type: "source_silence", MaxSilence: time.Hour})
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	a, err := NewAnalyzer(cfg, WithRuleFilter([]string{"test"}))
+	if err != nil {
+		t.Fatalf("NewAnalyzer() error = %v", err)
+	}
+
+	source := &mockSource{
+		lines: []*parser.ParsedLine{
+			{Raw: "START id=db1", Timestamp: baseTime.Add(5 * time.Minute), Source: "db.log", LineNum: 1},
+			{Raw: "START id=app1", Timestamp: baseTime.Add(5 * time.Minute), Source: "app.log", LineNum: 1},
+			{Raw: "START id=db2", Timestamp: baseTime.Add(20 * time.Minute), Source: "db.log", LineNum: 2},
+			{Raw: "tick", Timestamp: baseTime.Add(30 * time.Minute), Source: "app.log", LineNum: 2},
+		},
+	}
+
+	result, err := a.Analyze(context.Background(), source)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	rule := result.Results[0]
+	if len(rule.Suppressed) != 1 || rule.Suppressed[0].Context.CorrelationID != "db1" {
+		t.Fatalf("Suppressed = %v, want db1", rule.Suppressed)
+	}
+	if rule.Suppressed[0].SuppressedBy != "db-restart" {
+		t.Errorf("SuppressedBy = %q, want db-restart", rule.Suppressed[0].SuppressedBy)
+	}
+	if result.TotalIssues() != 2 || result.TotalSuppressed() != 1 {
+		t.Errorf("TotalIssues() = %d, TotalSuppressed() = %d, want 2 and 1",
+			result.TotalIssues(), result.TotalSuppressed())
+	}
+}
+
+func TestAnalyzer_MaintenanceWindows_Deadline(t *testing.T) {
+	monday := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		calendar string
+		start    time.Time // of the sequence that never ends
+		window   time.Time // 30-minute maintenance window
+	}{
+		// Times out at 10:25, inside the window that opened after it started
+		{"wall clock", "", monday.Add(9*time.Hour + 55*time.Minute), monday.Add(10 * time.Hour)},
+		// Friday 16:50 times out at 09:20 Monday in business hours
+		{"calendar", "business-hours", monday.Add(-3*24*time.Hour + 16*time.Hour + 50*time.Minute), monday.Add(9 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig(t)
+			cfg.Calendars = businessHours()
+			cfg.Rules[0].Timeout = 30 * time.Minute
+			cfg.Rules[0].Calendar = tt.calendar
+			cfg.MaintenanceWindows = []config.MaintenanceWindow{
+				{Name: "deploy", Start: tt.window, End: tt.window.Add(30 * time.Minute)},
+			}
+			if err := config.Validate(cfg); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			a, err := NewAnalyzer(cfg)
+			if err != nil {
+				t.Fatalf("NewAnalyzer() error = %v", err)
+			}
+
+			source := &mockSource{
+				lines: []*parser.ParsedLine{
+					{Raw: "START id=1", Timestamp: tt.start, Source: "app.log", LineNum: 1},
+					{Raw: "tick", Timestamp: tt.window.Add(2 * time.Hour), Source: "app.log", LineNum: 2},
+				},
+			}
+
+			result, err := a.Analyze(context.Background(), source)
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+
+			rule := result.Results[0]
+			if len(rule.Issues) != 0 || len(rule.Suppressed) != 1 {
+				t.Fatalf("Issues = %v, Suppressed = %v, want the missing end suppressed", rule.Issues, rule.Suppressed)
+			}
+			if rule.Suppressed[0].SuppressedBy != "deploy" {
+				t.Errorf("SuppressedBy = %q, want deploy", rule.Suppressed[0].SuppressedBy)
+			}
+		})
+	}
+}
+
 func TestAnalysisResult_Methods(t *testing.T) {
 	result := &AnalysisResult{
 		Results: []*RuleResult{
 			{RuleName: "r1", Issues: []Issue{{}, {}}},
-			{RuleName: "r2", Issues: []Issue{}},
+			{RuleName: "r2", Issues: []Issue{}, Suppressed: []Issue{{}}},
 			{RuleName: "r3", Issues: []Issue{{}}},
 		},
 	}
@@ -231,6 +624,10 @@ func TestAnalysisResult_Methods(t *testing.T) {
 	if result.RulesWithIssues() != 2 {
 		t.Errorf("RulesWithIssues() = %d, want 2", result.RulesWithIssues())
 	}
+
+	if result.TotalSuppressed() != 1 {
+		t.Errorf("TotalSuppressed() = %d, want 1", result.TotalSuppressed())
+	}
 }
 
 func createTestConfig(t *testing.T) *config.Config {
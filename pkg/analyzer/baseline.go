@@ -0,0 +1,203 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/ccollicutt/negalog/pkg/baseline"
+	"github.com/ccollicutt/negalog/pkg/config"
+	"github.com/ccollicutt/negalog/pkg/parser"
+)
+
+// BaselineEngine implements RuleEngine for volume-drop detection.
+// It counts pattern matches per hour and reports hours that fall well below
+// the volume learned for that hour of the week.
+type BaselineEngine struct {
+	name          string
+	description   string
+	minFraction   float64 // zero if not set
+	maxDeviations float64 // zero if not set
+
+	pattern  *regexp.Regexp
+	loc      *time.Location
+	baseline *baseline.Baseline
+
+	// State
+	mu      sync.Mutex
+	buckets map[time.Time]int // match counts per hour, keyed by baseline.HourStart
+	seen    TimeRange         // span of line timestamps processed
+	window  TimeRange         // analysis window set by the analyzer
+	stats   RuleStats
+}
+
+// NewBaselineEngine creates a new baseline engine from a rule config,
+// loading the rule's baseline file.
+func NewBaselineEngine(rule *config.RuleConfig) (*BaselineEngine, error) {
+	if rule.RuleTypeEnum() != config.RuleTypeBaseline {
+		return nil, fmt.Errorf("rule %q is not a baseline rule", rule.Name)
+	}
+
+	pattern := rule.CompiledPattern()
+	if pattern == nil {
+		return nil, fmt.Errorf("rule %q has uncompiled pattern", rule.Name)
+	}
+
+	b, err := baseline.Load(rule.BaselineFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w (build it with 'negalog baseline')", err)
+	}
+	if b.Pattern != pattern.String() || b.Timezone != rule.Location().String() {
+		return nil, fmt.Errorf("baseline %s was built for a different pattern or timezone; rebuild it with 'negalog baseline'",
+			rule.BaselineFile)
+	}
+
+	return &BaselineEngine{
+		name:          rule.Name,
+		description:   rule.Description,
+		minFraction:   rule.MinFraction,
+		maxDeviations: rule.MaxDeviations,
+		pattern:       pattern,
+		loc:           rule.Location(),
+		baseline:      b,
+		buckets:       make(map[time.Time]int),
+	}, nil
+}
+
+// Name returns the rule name.
+func (e *BaselineEngine) Name() string {
+	return e.name
+}
+
+// Type returns the rule type.
+func (e *BaselineEngine) Type() RuleType {
+	return RuleTypeBaseline
+}
+
+// Process handles a single log line.
+func (e *BaselineEngine) Process(ctx context.Context, line *parser.ParsedLine) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stats.LinesProcessed++
+	if e.stats.LinesProcessed == 1 || line.Timestamp.Before(e.seen.Start) {
+		e.seen.Start = line.Timestamp
+	}
+	if e.stats.LinesProcessed == 1 || line.Timestamp.After(e.seen.End) {
+		e.seen.End = line.Timestamp
+	}
+
+	if !e.pattern.MatchString(line.Raw) {
+		return nil
+	}
+
+	e.buckets[baseline.HourStart(line.Timestamp, e.loc)]++
+	e.stats.LinesMatched++
+
+	return nil
+}
+
+// SetWindow sets the analysis window. Only hours that lie entirely within
+// it are judged.
+func (e *BaselineEngine) SetWindow(window TimeRange) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.window = window
+}
+
+// evalWindow returns the analysis window, falling back to the span of
+// lines this engine has processed when no window was set.
+func (e *BaselineEngine) evalWindow() TimeRange {
+	window := e.window
+	if window.Start.IsZero() {
+		window.Start = e.seen.Start
+	}
+	if window.End.IsZero() {
+		window.End = e.seen.End
+	}
+	return window
+}
+
+// floor returns the lowest count an hour may have given its baseline slot:
+// the higher of the fraction and standard-deviation bounds that are set.
+func (e *BaselineEngine) floor(slot baseline.Slot) float64 {
+	var floor float64
+	if e.minFraction > 0 {
+		floor = e.minFraction * slot.Mean
+	}
+	if e.maxDeviations > 0 {
+		floor = max(floor, slot.Mean-e.maxDeviations*slot.StdDev)
+	}
+	return floor
+}
+
+// Finalize completes analysis and returns detected issues.
+func (e *BaselineEngine) Finalize(ctx context.Context) (*RuleResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stats.EndTime = time.Now()
+
+	result := &RuleResult{
+		RuleName:    e.name,
+		RuleType:    RuleTypeBaseline,
+		Description: e.description,
+		Issues:      make([]Issue, 0),
+		Stats:       e.stats,
+	}
+
+	window := e.evalWindow()
+	if window.Start.IsZero() || window.End.IsZero() {
+		return result, nil
+	}
+
+	// Hours cut off by either end of the analysis window would undercount
+	start := baseline.HourStart(window.Start, e.loc)
+	if start.Before(window.Start) {
+		start = start.Add(time.Hour)
+	}
+
+	for hs := start; !hs.Add(time.Hour).After(window.End); hs = hs.Add(time.Hour) {
+		slot := e.baseline.Slots[baseline.HourOfWeek(hs, e.loc)]
+		if slot.Samples == 0 {
+			continue
+		}
+
+		count := e.buckets[hs]
+		floor := e.floor(slot)
+		if float64(count) >= floor {
+			continue
+		}
+
+		he := hs.Add(time.Hour)
+		result.Issues = append(result.Issues, Issue{
+			Type: IssueTypeBelowBaseline,
+			Description: fmt.Sprintf("Only %d occurrences between %s and %s (baseline: %.1f ± %.1f)",
+				count, hs.Format(time.RFC3339), he.Format(time.RFC3339), slot.Mean, slot.StdDev),
+			Context: IssueContext{
+				StartTime:   hs,
+				EndTime:     he,
+				Occurrences: count,
+				MinRequired: int(math.Ceil(floor)),
+				Baseline:    slot.Mean,
+			},
+		})
+	}
+
+	return result, nil
+}
+
+// Reset clears internal state for reuse.
+func (e *BaselineEngine) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.buckets = make(map[time.Time]int)
+	e.seen = TimeRange{}
+	e.window = TimeRange{}
+	e.stats = RuleStats{}
+}
@@ -0,0 +1,204 @@
+package analyzer
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ccollicutt/negalog/pkg/baseline"
+	"github.com/ccollicutt/negalog/pkg/config"
+	"github.com/ccollicutt/negalog/pkg/parser"
+)
+
+func TestNewBaselineEngine_WrongType(t *testing.T) {
+	rule := &config.RuleConfig{
+		Name: "test",
+		Type: "rate",
+	}
+	if _, err := NewBaselineEngine(rule); err == nil {
+		t.Error("NewBaselineEngine() expected error for wrong type")
+	}
+}
+
+func TestNewBaselineEngine_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	other := filepath.Join(dir, "other.json")
+	b := &baseline.Baseline{Pattern: "ORDER_REJECTED", Timezone: "UTC", Slots: make([]baseline.Slot, baseline.HoursPerWeek)}
+	if err := b.Save(other); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		file   string
+		errMsg string
+	}{
+		{"missing file", filepath.Join(dir, "missing.json"), "build it with 'negalog baseline'"},
+		{"other pattern", other, "different pattern or timezone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := baselineRule(t, tt.file, 0.5, 0)
+			_, err := NewBaselineEngine(rule)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("NewBaselineEngine() error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestBaselineEngine_Thresholds(t *testing.T) {
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// Every hour averages 100 orders with a standard deviation of 10
+	b := &baseline.Baseline{Pattern: "ORDER_ACCEPTED", Timezone: "UTC", Slots: make([]baseline.Slot, baseline.HoursPerWeek)}
+	for i := range b.Slots {
+		b.Slots[i] = baseline.Slot{Mean: 100, StdDev: 10, Samples: 4}
+	}
+	// Except 12:00, which has never been seen
+	b.Slots[baseline.HourOfWeek(baseTime.Add(2*time.Hour), time.UTC)].Samples = 0
+
+	path := filepath.Join(t.TempDir(), "orders.json")
+	if err := b.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Orders at 10:00, 11:00, 12:00, and 13:00
+	counts := []int{95, 60, 0, 25}
+
+	tests := []struct {
+		name          string
+		minFraction   float64
+		maxDeviations float64
+		want          []int // reported hours
+		wantMin       int
+	}{
+		{name: "fraction", minFraction: 0.5, want: []int{3}, wantMin: 50},
+		{name: "deviations", maxDeviations: 3, want: []int{1, 3}, wantMin: 70},
+		{name: "both", minFraction: 0.5, maxDeviations: 3, want: []int{1, 3}, wantMin: 70},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := NewBaselineEngine(baselineRule(t, path, tt.minFraction, tt.maxDeviations))
+			if err != nil {
+				t.Fatalf("NewBaselineEngine() error = %v", err)
+			}
+
+			ctx := context.Background()
+			for h, n := range counts {
+				for i := 0; i < n; i++ {
+					line := &parser.ParsedLine{Raw: "ORDER_ACCEPTED", Timestamp: baseTime.Add(time.Duration(h)*time.Hour + time.Duration(i)*time.Second)}
+					if err := engine.Process(ctx, line); err != nil {
+						t.Fatalf("Process() error = %v", err)
+					}
+				}
+			}
+			engine.SetWindow(TimeRange{Start: baseTime, End: baseTime.Add(4 * time.Hour)})
+
+			result, err := engine.Finalize(ctx)
+			if err != nil {
+				t.Fatalf("Finalize() error = %v", err)
+			}
+
+			if len(result.Issues) != len(tt.want) {
+				t.Fatalf("Issues = %v, want hours %v", result.Issues, tt.want)
+			}
+			for i, h := range tt.want {
+				issue := result.Issues[i]
+				if issue.Type != IssueTypeBelowBaseline {
+					t.Errorf("Type = %v, want %v", issue.Type, IssueTypeBelowBaseline)
+				}
+				if !issue.Context.StartTime.Equal(baseTime.Add(time.Duration(h) * time.Hour)) {
+					t.Errorf("StartTime = %v, want hour %d", issue.Context.StartTime, h)
+				}
+				if issue.Context.Occurrences != counts[h] || issue.Context.Baseline != 100 ||
+					issue.Context.MinRequired != tt.wantMin {
+					t.Errorf("Context = %+v, want %d against 100 (minimum %d)", issue.Context, counts[h], tt.wantMin)
+				}
+			}
+		})
+	}
+}
+
+func TestBaselineEngine_HalfHourZone(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	// Every hour averages 100 orders
+	b := &baseline.Baseline{Pattern: "ORDER_ACCEPTED", Timezone: "Asia/Kolkata", Slots: make([]baseline.Slot, baseline.HoursPerWeek)}
+	for i := range b.Slots {
+		b.Slots[i] = baseline.Slot{Mean: 100, StdDev: 10, Samples: 4}
+	}
+	path := filepath.Join(t.TempDir(), "orders.json")
+	if err := b.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	rule := baselineRule(t, path, 0.5, 0)
+	rule.Timezone = "Asia/Kolkata"
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules:           []config.RuleConfig{*rule},
+	}
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	engine, err := NewBaselineEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewBaselineEngine() error = %v", err)
+	}
+
+	// 100 orders spread over 15:00-16:00 local (09:30-10:30 UTC) and none in
+	// the hour after. Hours taken in UTC would see 50 in 10:00-11:00 instead.
+	hour := time.Date(2024, 1, 15, 15, 0, 0, 0, kolkata)
+	ctx := context.Background()
+	for i := 0; i < 100; i++ {
+		line := &parser.ParsedLine{Raw: "ORDER_ACCEPTED", Timestamp: hour.Add(time.Duration(i) * 36 * time.Second)}
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+	engine.SetWindow(TimeRange{Start: hour, End: hour.Add(2 * time.Hour)})
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %+v, want only 16:00", result.Issues)
+	}
+	if ctx := result.Issues[0].Context; !ctx.StartTime.Equal(hour.Add(time.Hour)) || ctx.Occurrences != 0 {
+		t.Errorf("Context = %+v, want 0 occurrences from 16:00", ctx)
+	}
+}
+
+func baselineRule(t *testing.T, file string, minFraction, maxDeviations float64) *config.RuleConfig {
+	t.Helper()
+
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:          "test",
+			Type:          "baseline",
+			Pattern:       `ORDER_ACCEPTED`,
+			BaselineFile:  file,
+			MinFraction:   minFraction,
+			MaxDeviations: maxDeviations,
+		}},
+	}
+
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	return &cfg.Rules[0]
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/ccollicutt/negalog/pkg/calendar"
 	"github.com/ccollicutt/negalog/pkg/config"
 	"github.com/ccollicutt/negalog/pkg/parser"
 )
@@ -17,25 +20,82 @@ type triggerEvent struct {
 	timestamp     time.Time
 	source        string
 	lineNum       int
+	seq           uint64 // arrival order, for reporting
+
+	// With expected_count: expected events seen within the timeout, the
+	// time of the last one, and the bounds the count must fall in
+	count    int
+	lastSeen time.Time
+	min, max int // max is -1 for no upper bound
 }
 
 // ConditionalEngine implements RuleEngine for conditional absence detection.
 // It tracks trigger events and looks for expected consequences within a timeout.
+//
+// With a forbidden pattern the rule is inverted: a forbidden event within the
+// timeout of a trigger is the issue, and triggers that time out are fine.
+// With preceded_by it looks backward: each trigger needs a preceded_by match
+// within the timeout before it. With expected_count, each trigger needs a
+// number of expected events within the timeout, and is judged once its
+// timeout has passed (or once the count is reached, if there is no maximum).
+//
+// Pending triggers are indexed so that each consequence is matched without
+// scanning every trigger: correlated rules keep them per correlation ID, and
+// uncorrelated rules keep a queue in arrival order, which assumes lines
+// arrive in timestamp order.
 type ConditionalEngine struct {
-	name        string
-	description string
-	timeout     time.Duration
-	corrField   int // 0 means no correlation, 1+ is capture group index
-
-	triggerPattern  *regexp.Regexp
-	expectedPattern *regexp.Regexp
+	name          string
+	description   string
+	timeout       time.Duration
+	calendar      *calendar.Calendar    // timeouts pause outside active time; nil means always active
+	triggerKey    config.CorrelationKey // empty means no correlation
+	expectedKey   config.CorrelationKey
+	forbiddenKey  config.CorrelationKey
+	precededByKey config.CorrelationKey
+
+	triggerPattern   *regexp.Regexp
+	expectedPattern  *regexp.Regexp // nil if the rule uses forbidden_pattern or preceded_by
+	forbiddenPattern *regexp.Regexp
+	precededBy       *regexp.Regexp
+
+	roster []string // trigger correlation IDs expected to appear
+
+	// expected_count bounds; counted is false for rules without one
+	counted    bool
+	countMin   int
+	countMax   int // -1 for no upper bound
+	countGroup int // trigger capture group giving an exact count, 0 if fixed
 
 	// State
-	mu       sync.Mutex
-	triggers []triggerEvent // active triggers awaiting consequence
-	stats    RuleStats
+	mu      sync.Mutex
+	byID    map[string][]triggerEvent // pending triggers per correlation ID, correlated rules only
+	queue   []triggerEvent            // pending triggers in arrival order, uncorrelated rules only
+	expired []triggerEvent            // uncorrelated triggers older than any future consequence allows
+	nextSeq uint64
+	latency *latencySketch  // trigger-to-consequence times, correlated rules only
+	present map[string]bool // roster members, set once seen as a trigger
+	issues  []Issue         // issues found while processing
+	seen    TimeRange       // span of line timestamps processed
+	window  TimeRange       // analysis window set by the analyzer
+	stats   RuleStats
+
+	// Latest preceded_by match per correlation ID. Entries older than the
+	// timeout are pruned once the map reaches pruneAt.
+	preceding map[string]triggerEvent
+	pruneAt   int
+
+	// With a forbidden pattern, correlation IDs whose triggers are all past
+	// the timeout are pruned once byID reaches forbidPruneAt.
+	forbidPruneAt int
 }
 
+// precedingPruneMin is the smallest preceded_by index size that triggers pruning.
+const precedingPruneMin = 1024
+
+// forbidPruneMin is the smallest pending-trigger index size that triggers
+// pruning for rules with a forbidden pattern.
+const forbidPruneMin = 1024
+
 // NewConditionalEngine creates a new conditional detection engine from a rule config.
 func NewConditionalEngine(rule *config.RuleConfig) (*ConditionalEngine, error) {
 	if rule.RuleTypeEnum() != config.RuleTypeConditional {
@@ -44,20 +104,59 @@ func NewConditionalEngine(rule *config.RuleConfig) (*ConditionalEngine, error) {
 
 	triggerPattern := rule.CompiledTriggerPattern()
 	expectedPattern := rule.CompiledExpectedPattern()
+	forbiddenPattern := rule.CompiledForbiddenPattern()
+	precededBy := rule.CompiledPrecededBy()
 
-	if triggerPattern == nil || expectedPattern == nil {
+	if triggerPattern == nil || (expectedPattern == nil && forbiddenPattern == nil && precededBy == nil) {
 		return nil, fmt.Errorf("rule %q has uncompiled patterns", rule.Name)
 	}
 
-	return &ConditionalEngine{
-		name:            rule.Name,
-		description:     rule.Description,
-		timeout:         rule.Timeout,
-		corrField:       rule.CorrelationField,
-		triggerPattern:  triggerPattern,
-		expectedPattern: expectedPattern,
-		triggers:        make([]triggerEvent, 0),
-	}, nil
+	var roster []string
+	if rule.Roster != nil {
+		roster = rule.Roster.Expected()
+	}
+
+	e := &ConditionalEngine{
+		name:             rule.Name,
+		description:      rule.Description,
+		timeout:          rule.Timeout,
+		calendar:         rule.ActiveCalendar(),
+		triggerKey:       rule.TriggerCorrelationKey(),
+		expectedKey:      rule.ExpectedCorrelationKey(),
+		forbiddenKey:     rule.ForbiddenCorrelationKey(),
+		precededByKey:    rule.PrecededByCorrelationKey(),
+		triggerPattern:   triggerPattern,
+		expectedPattern:  expectedPattern,
+		forbiddenPattern: forbiddenPattern,
+		precededBy:       precededBy,
+		byID:             make(map[string][]triggerEvent),
+		preceding:        make(map[string]triggerEvent),
+		pruneAt:          precedingPruneMin,
+		forbidPruneAt:    forbidPruneMin,
+		latency:          newLatencySketch(),
+		roster:           roster,
+		present:          rosterFlags(roster),
+	}
+
+	if c := rule.ExpectedCount; c != nil {
+		e.counted = true
+		e.countMin, e.countMax = c.Min, c.Max
+		if c.Max == 0 {
+			e.countMax = -1
+		}
+		e.countGroup = c.GroupIndex()
+	}
+
+	return e, nil
+}
+
+// rosterFlags returns a presence flag for each roster member, all unset.
+func rosterFlags(roster []string) map[string]bool {
+	flags := make(map[string]bool, len(roster))
+	for _, member := range roster {
+		flags[member] = false
+	}
+	return flags
 }
 
 // Name returns the rule name.
@@ -76,6 +175,24 @@ func (e *ConditionalEngine) Process(ctx context.Context, line *parser.ParsedLine
 	defer e.mu.Unlock()
 
 	e.stats.LinesProcessed++
+	if e.stats.LinesProcessed == 1 || line.Timestamp.Before(e.seen.Start) {
+		e.seen.Start = line.Timestamp
+	}
+	if e.stats.LinesProcessed == 1 || line.Timestamp.After(e.seen.End) {
+		e.seen.End = line.Timestamp
+	}
+
+	// Check for a forbidden event before the line can become a trigger
+	// itself, so a pattern may forbid its own repeat
+	if e.forbiddenPattern != nil {
+		if matches := e.forbiddenPattern.FindStringSubmatch(line.Raw); matches != nil {
+			var corrID string
+			if e.correlated() {
+				corrID, _ = e.forbiddenKey.Extract(matches)
+			}
+			e.reportForbidden(corrID, line)
+		}
+	}
 
 	// Check for trigger pattern match
 	if matches := e.triggerPattern.FindStringSubmatch(line.Raw); matches != nil {
@@ -86,61 +203,430 @@ func (e *ConditionalEngine) Process(ctx context.Context, line *parser.ParsedLine
 		}
 
 		// Extract correlation ID if configured
-		if e.corrField > 0 && e.corrField <= len(matches)-1 {
-			trigger.correlationID = matches[e.corrField]
+		if e.correlated() {
+			trigger.correlationID, _ = e.triggerKey.Extract(matches)
 		}
 
-		e.triggers = append(e.triggers, trigger)
+		switch {
+		case e.precededBy != nil:
+			e.checkPreceded(trigger)
+		case e.counted:
+			if e.setCountBounds(&trigger, matches) {
+				e.addTrigger(trigger)
+			}
+		default:
+			e.addTrigger(trigger)
+		}
 		e.stats.LinesMatched++
+		if _, ok := e.present[trigger.correlationID]; ok {
+			e.present[trigger.correlationID] = true
+		}
+	}
+
+	// Record an event later triggers may need as their precondition. This
+	// comes after the trigger check so that a line cannot precede itself.
+	if e.precededBy != nil {
+		if matches := e.precededBy.FindStringSubmatch(line.Raw); matches != nil {
+			event := triggerEvent{
+				timestamp: line.Timestamp,
+				source:    line.Source,
+				lineNum:   line.LineNum,
+			}
+			if e.correlated() {
+				event.correlationID, _ = e.precededByKey.Extract(matches)
+			}
+			e.recordPreceding(event)
+		}
 	}
 
 	// Check for expected pattern match
+	if e.expectedPattern == nil {
+		return nil
+	}
 	if matches := e.expectedPattern.FindStringSubmatch(line.Raw); matches != nil {
 		var corrID string
-		if e.corrField > 0 && e.corrField <= len(matches)-1 {
-			corrID = matches[e.corrField]
+		if e.correlated() {
+			corrID, _ = e.expectedKey.Extract(matches)
 		}
 
-		// Remove matching triggers that are within timeout
-		e.removeSatisfiedTriggers(corrID, line.Timestamp)
+		if e.counted {
+			e.countConsequence(corrID, line.Timestamp)
+		} else {
+			// Remove matching triggers that are within timeout
+			e.removeSatisfiedTriggers(corrID, line.Timestamp)
+		}
 	}
 
 	return nil
 }
 
+// correlated returns true if triggers are matched to expected events by correlation ID.
+func (e *ConditionalEngine) correlated() bool {
+	return len(e.triggerKey) > 0
+}
+
+// addTrigger records a trigger as pending.
+func (e *ConditionalEngine) addTrigger(trigger triggerEvent) {
+	trigger.seq = e.nextSeq
+	e.nextSeq++
+
+	// With a forbidden pattern, earlier triggers past their timeout can no
+	// longer be violated, so they are dropped
+	if e.forbiddenPattern != nil {
+		if e.correlated() {
+			e.byID[trigger.correlationID] = e.dropExpired(e.byID[trigger.correlationID], trigger.timestamp)
+		} else {
+			e.queue = e.dropExpired(e.queue, trigger.timestamp)
+		}
+	}
+
+	if e.correlated() {
+		e.byID[trigger.correlationID] = append(e.byID[trigger.correlationID], trigger)
+	} else {
+		e.queue = append(e.queue, trigger)
+	}
+
+	// IDs that never trigger again would otherwise keep their dead triggers
+	if e.forbiddenPattern != nil && e.correlated() && len(e.byID) >= e.forbidPruneAt {
+		e.pruneForbidden(trigger.timestamp)
+		e.forbidPruneAt = max(2*len(e.byID), forbidPruneMin)
+	}
+}
+
+// pruneForbidden drops triggers of every correlation ID that are past the
+// timeout at now, so can no longer be violated, along with emptied IDs.
+func (e *ConditionalEngine) pruneForbidden(now time.Time) {
+	for id, pending := range e.byID {
+		if pending = e.dropExpired(pending, now); len(pending) == 0 {
+			delete(e.byID, id)
+		} else {
+			e.byID[id] = pending
+		}
+	}
+}
+
+// dropExpired removes triggers from the front of a time-ordered list that
+// are more than the timeout before now.
+func (e *ConditionalEngine) dropExpired(triggers []triggerEvent, now time.Time) []triggerEvent {
+	for len(triggers) > 0 && e.calendar.Elapsed(triggers[0].timestamp, now) > e.timeout {
+		triggers = triggers[1:]
+	}
+	return triggers
+}
+
 // removeSatisfiedTriggers removes triggers that are satisfied by the expected event.
+// With correlation, every pending trigger with the same ID within timeout is
+// satisfied. Without, only the oldest trigger within timeout is.
 func (e *ConditionalEngine) removeSatisfiedTriggers(corrID string, eventTime time.Time) {
-	newTriggers := make([]triggerEvent, 0, len(e.triggers))
-
-	for _, trigger := range e.triggers {
-		// Check if this trigger is satisfied
-		satisfied := false
-
-		// If we have correlation IDs, they must match
-		if e.corrField > 0 {
-			if trigger.correlationID == corrID {
-				// Check timeout
-				if eventTime.Sub(trigger.timestamp) <= e.timeout {
-					satisfied = true
-				}
-			}
-		} else {
-			// No correlation - any expected event within timeout satisfies oldest trigger
-			if eventTime.Sub(trigger.timestamp) <= e.timeout {
-				satisfied = true
-			}
+	if !e.correlated() {
+		// Triggers too old for this event are too old for any later one, so
+		// they move out of the queue and stay pending
+		for len(e.queue) > 0 && e.calendar.Elapsed(e.queue[0].timestamp, eventTime) > e.timeout {
+			e.expired = append(e.expired, e.queue[0])
+			e.queue = e.queue[1:]
+		}
+		if len(e.queue) > 0 {
+			e.queue = e.queue[1:]
+		}
+		return
+	}
+
+	pending, ok := e.byID[corrID]
+	if !ok {
+		return
+	}
+
+	remaining := pending[:0]
+	for _, trigger := range pending {
+		if e.calendar.Elapsed(trigger.timestamp, eventTime) <= e.timeout {
+			e.stats.Completed++
+			e.latency.Add(eventTime.Sub(trigger.timestamp))
+			continue
+		}
+		remaining = append(remaining, trigger)
+	}
+
+	if len(remaining) == 0 {
+		delete(e.byID, corrID)
+	} else {
+		e.byID[corrID] = remaining
+	}
+}
+
+// setCountBounds sets the expected_count bounds of a trigger, taking an
+// exact count from the trigger line when configured. It records an issue and
+// returns false if that count is not a number.
+func (e *ConditionalEngine) setCountBounds(trigger *triggerEvent, matches []string) bool {
+	if e.countGroup == 0 {
+		trigger.min, trigger.max = e.countMin, e.countMax
+		return true
+	}
+
+	n, err := strconv.Atoi(matches[e.countGroup])
+	if err != nil || n < 0 {
+		e.issues = append(e.issues, Issue{
+			Type: IssueTypeConsequenceCount,
+			Description: fmt.Sprintf("Trigger event (id=%s) has invalid expected count %q",
+				trigger.correlationID, matches[e.countGroup]),
+			Context: IssueContext{
+				CorrelationID: trigger.correlationID,
+				StartTime:     trigger.timestamp,
+				Source:        trigger.source,
+				LineNum:       trigger.lineNum,
+				Timeout:       e.timeout,
+				InvalidCount:  matches[e.countGroup],
+			},
+		})
+		return false
+	}
+
+	trigger.min, trigger.max = n, n
+	return true
+}
+
+// countConsequence counts an expected event toward every pending trigger with
+// the same ID whose timeout it falls within. Triggers it is too late for have
+// seen all they will, so they are judged and removed; so are triggers that
+// reached their count and have no maximum.
+func (e *ConditionalEngine) countConsequence(corrID string, eventTime time.Time) {
+	pending, ok := e.byID[corrID]
+	if !ok {
+		return
+	}
+
+	remaining := pending[:0]
+	for _, trigger := range pending {
+		if e.calendar.Elapsed(trigger.timestamp, eventTime) > e.timeout {
+			e.judgeCount(trigger)
+			continue
+		}
+
+		trigger.count++
+		trigger.lastSeen = eventTime
+		if trigger.max < 0 && trigger.count >= trigger.min {
+			e.judgeCount(trigger)
+			continue
+		}
+		remaining = append(remaining, trigger)
+	}
+
+	if len(remaining) == 0 {
+		delete(e.byID, corrID)
+	} else {
+		e.byID[corrID] = remaining
+	}
+}
+
+// judgeCount records a trigger whose counting is over as completed if its
+// count is within bounds, and as an issue otherwise.
+func (e *ConditionalEngine) judgeCount(trigger triggerEvent) {
+	if issue, ok := e.countIssue(trigger); ok {
+		e.issues = append(e.issues, issue)
+		return
+	}
+
+	e.stats.Completed++
+	if !trigger.lastSeen.IsZero() {
+		e.latency.Add(trigger.lastSeen.Sub(trigger.timestamp))
+	}
+}
+
+// countIssue returns the issue for a trigger whose count is out of bounds.
+func (e *ConditionalEngine) countIssue(trigger triggerEvent) (Issue, bool) {
+	if trigger.count >= trigger.min && (trigger.max < 0 || trigger.count <= trigger.max) {
+		return Issue{}, false
+	}
+
+	issue := Issue{
+		Type: IssueTypeConsequenceCount,
+		Description: fmt.Sprintf("Trigger event (id=%s) followed by %d expected events within %s, want %s",
+			trigger.correlationID, trigger.count, e.timeout, CountBounds(trigger.min, trigger.max)),
+		Context: IssueContext{
+			CorrelationID: trigger.correlationID,
+			StartTime:     trigger.timestamp,
+			Source:        trigger.source,
+			LineNum:       trigger.lineNum,
+			Timeout:       e.timeout,
+			Occurrences:   trigger.count,
+			MinRequired:   trigger.min,
+		},
+	}
+	if trigger.max >= 0 {
+		issue.Context.MaxAllowed = trigger.max
+	} else {
+		issue.Context.NoMaxAllowed = true
+	}
+	return issue, true
+}
+
+// CountBounds describes the count a trigger needs, e.g. "exactly 3". A
+// negative max means there is no maximum.
+func CountBounds(min, max int) string {
+	switch {
+	case min == max:
+		return fmt.Sprintf("exactly %d", min)
+	case max < 0:
+		return fmt.Sprintf("at least %d", min)
+	case min == 0:
+		return fmt.Sprintf("at most %d", max)
+	default:
+		return fmt.Sprintf("%d to %d", min, max)
+	}
+}
+
+// reportForbidden records an issue for each pending trigger a forbidden event
+// falls within the timeout of. Those triggers are done, as are any too old
+// for the event, so all are removed.
+func (e *ConditionalEngine) reportForbidden(corrID string, line *parser.ParsedLine) {
+	var pending []triggerEvent
+	if e.correlated() {
+		pending = e.byID[corrID]
+		delete(e.byID, corrID)
+	} else {
+		pending = e.queue
+		e.queue = nil
+	}
+
+	for _, trigger := range pending {
+		elapsed := line.Timestamp.Sub(trigger.timestamp)
+		if e.calendar.Elapsed(trigger.timestamp, line.Timestamp) > e.timeout {
+			continue
+		}
+
+		desc := fmt.Sprintf("Forbidden event %s after trigger (window: %s)", elapsed, e.timeout)
+		if trigger.correlationID != "" {
+			desc = fmt.Sprintf("Forbidden event %s after trigger (id=%s, window: %s)",
+				elapsed, trigger.correlationID, e.timeout)
+		}
+
+		e.issues = append(e.issues, Issue{
+			Type:        IssueTypeForbiddenEvent,
+			Description: desc,
+			Context: IssueContext{
+				CorrelationID: trigger.correlationID,
+				StartTime:     trigger.timestamp,
+				EndTime:       line.Timestamp,
+				Source:        trigger.source,
+				LineNum:       trigger.lineNum,
+				EndSource:     line.Source,
+				EndLineNum:    line.LineNum,
+				Duration:      elapsed,
+				Timeout:       e.timeout,
+			},
+		})
+	}
+}
+
+// checkPreceded records an issue unless the trigger's latest preceded_by
+// event is within the timeout before it.
+func (e *ConditionalEngine) checkPreceded(trigger triggerEvent) {
+	prev, ok := e.preceding[trigger.correlationID]
+	if ok && e.calendar.Elapsed(prev.timestamp, trigger.timestamp) <= e.timeout {
+		if e.correlated() {
+			e.stats.Completed++
+			e.latency.Add(trigger.timestamp.Sub(prev.timestamp))
 		}
+		return
+	}
 
-		if !satisfied {
-			newTriggers = append(newTriggers, trigger)
-		} else if e.corrField == 0 {
-			// Without correlation, only satisfy the first matching trigger
-			newTriggers = append(newTriggers, e.triggers[len(newTriggers)+1:]...)
-			break
+	desc := fmt.Sprintf("Event not preceded by the required event within %s", e.timeout)
+	if trigger.correlationID != "" {
+		desc = fmt.Sprintf("Event (id=%s) not preceded by the required event within %s",
+			trigger.correlationID, e.timeout)
+	}
+	if ok {
+		desc += fmt.Sprintf("; the last one was %s earlier", trigger.timestamp.Sub(prev.timestamp).Round(time.Second))
+	}
+
+	e.issues = append(e.issues, Issue{
+		Type:        IssueTypeUnprecededEvent,
+		Description: desc,
+		Context: IssueContext{
+			CorrelationID: trigger.correlationID,
+			StartTime:     trigger.timestamp,
+			Source:        trigger.source,
+			LineNum:       trigger.lineNum,
+			Timeout:       e.timeout,
+		},
+	})
+}
+
+// recordPreceding indexes a preceded_by event by correlation ID, pruning
+// entries that are too old to precede anything still to come.
+func (e *ConditionalEngine) recordPreceding(event triggerEvent) {
+	e.preceding[event.correlationID] = event
+
+	if len(e.preceding) < e.pruneAt {
+		return
+	}
+	for id, prev := range e.preceding {
+		if e.calendar.Elapsed(prev.timestamp, event.timestamp) > e.timeout {
+			delete(e.preceding, id)
 		}
 	}
+	e.pruneAt = max(2*len(e.preceding), precedingPruneMin)
+}
+
+// SetWindow sets the analysis window. Its end is the evaluation clock that
+// decides whether a counted trigger is still within its timeout.
+func (e *ConditionalEngine) SetWindow(window TimeRange) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.window = window
+}
 
-	e.triggers = newTriggers
+// evalWindow returns the analysis window, falling back to the span of
+// lines this engine has processed when no window was set.
+func (e *ConditionalEngine) evalWindow() TimeRange {
+	window := e.window
+	if window.Start.IsZero() {
+		window.Start = e.seen.Start
+	}
+	if window.End.IsZero() {
+		window.End = e.seen.End
+	}
+	return window
+}
+
+// judgeOpenCounts judges counted triggers whose timeout has passed at the
+// evaluation clock and removes them. Triggers still within their timeout
+// may see more events, so they stay open and count as pending; only those
+// already over their maximum are reported.
+func (e *ConditionalEngine) judgeOpenCounts() {
+	clock := e.evalWindow().End
+	triggers := e.pending()
+
+	e.byID = make(map[string][]triggerEvent)
+	e.stats.Pending = 0
+	for _, trigger := range triggers {
+		if clock.IsZero() || e.calendar.Elapsed(trigger.timestamp, clock) > e.timeout {
+			e.judgeCount(trigger)
+			continue
+		}
+		if trigger.max >= 0 && trigger.count > trigger.max {
+			issue, _ := e.countIssue(trigger)
+			e.issues = append(e.issues, issue)
+			continue
+		}
+		e.stats.Pending++
+		e.byID[trigger.correlationID] = append(e.byID[trigger.correlationID], trigger)
+	}
+}
+
+// pending returns all pending triggers in arrival order.
+func (e *ConditionalEngine) pending() []triggerEvent {
+	triggers := make([]triggerEvent, 0, len(e.expired)+len(e.queue))
+	triggers = append(triggers, e.expired...)
+	triggers = append(triggers, e.queue...)
+	for _, group := range e.byID {
+		triggers = append(triggers, group...)
+	}
+
+	sort.Slice(triggers, func(i, j int) bool {
+		return triggers[i].seq < triggers[j].seq
+	})
+	return triggers
 }
 
 // Finalize completes analysis and returns detected issues.
@@ -149,17 +635,52 @@ func (e *ConditionalEngine) Finalize(ctx context.Context) (*RuleResult, error) {
 	defer e.mu.Unlock()
 
 	e.stats.EndTime = time.Now()
+	if e.counted {
+		e.judgeOpenCounts()
+	}
+	e.stats.Latency = e.latency.Summary()
+
+	triggers := e.pending()
 
 	result := &RuleResult{
 		RuleName:    e.name,
 		RuleType:    RuleTypeConditional,
 		Description: e.description,
-		Issues:      make([]Issue, 0, len(e.triggers)),
+		Issues:      make([]Issue, 0, len(triggers)),
 		Stats:       e.stats,
 	}
 
+	// Roster members that never triggered
+	for _, member := range e.roster {
+		if e.present[member] {
+			continue
+		}
+		result.Issues = append(result.Issues, Issue{
+			Type:        IssueTypeAbsentMember,
+			Description: fmt.Sprintf("Expected member %q produced no trigger event", member),
+			Context: IssueContext{
+				CorrelationID: member,
+			},
+		})
+	}
+	e.present = rosterFlags(e.roster)
+
+	// Forbidden, unpreceded, and counted events were found while processing
+	result.Issues = append(result.Issues, e.issues...)
+	e.issues = nil
+
+	// With a forbidden pattern, pending triggers have simply not seen one
+	if e.expectedPattern == nil {
+		return result, nil
+	}
+
+	// Counted triggers were judged above; those left are still open
+	if e.counted {
+		return result, nil
+	}
+
 	// All remaining triggers are missing their expected consequences
-	for _, trigger := range e.triggers {
+	for _, trigger := range triggers {
 		desc := fmt.Sprintf("Trigger event without expected consequence within %s", e.timeout)
 		if trigger.correlationID != "" {
 			desc = fmt.Sprintf("Trigger event (id=%s) without expected consequence within %s",
@@ -188,7 +709,18 @@ func (e *ConditionalEngine) Reset() {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	e.triggers = make([]triggerEvent, 0)
+	e.byID = make(map[string][]triggerEvent)
+	e.queue = nil
+	e.expired = nil
+	e.issues = nil
+	e.seen = TimeRange{}
+	e.window = TimeRange{}
+	e.nextSeq = 0
+	e.preceding = make(map[string]triggerEvent)
+	e.pruneAt = precedingPruneMin
+	e.forbidPruneAt = forbidPruneMin
+	e.latency = newLatencySketch()
+	e.present = rosterFlags(e.roster)
 	e.stats = RuleStats{}
 }
 
@@ -198,6 +730,12 @@ type TriggerState struct {
 	Timestamp     time.Time `json:"timestamp"`
 	Source        string    `json:"source"`
 	LineNum       int       `json:"line_num"`
+
+	// Count is the number of expected events seen so far (expected_count
+	// rules), and Expected the exact count taken from the trigger line when
+	// the rule uses from_group.
+	Count    int `json:"count,omitempty"`
+	Expected int `json:"expected,omitempty"`
 }
 
 // ExportState returns all pending triggers for serialization.
@@ -205,14 +743,24 @@ func (e *ConditionalEngine) ExportState() []TriggerState {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	states := make([]TriggerState, 0, len(e.triggers))
-	for _, trigger := range e.triggers {
-		states = append(states, TriggerState{
+	if clock := e.evalWindow().End; e.forbiddenPattern != nil && e.correlated() && !clock.IsZero() {
+		e.pruneForbidden(clock)
+	}
+
+	triggers := e.pending()
+	states := make([]TriggerState, 0, len(triggers))
+	for _, trigger := range triggers {
+		state := TriggerState{
 			CorrelationID: trigger.correlationID,
 			Timestamp:     trigger.timestamp,
 			Source:        trigger.source,
 			LineNum:       trigger.lineNum,
-		})
+			Count:         trigger.count,
+		}
+		if e.countGroup > 0 {
+			state.Expected = trigger.min
+		}
+		states = append(states, state)
 	}
 	return states
 }
@@ -223,11 +771,58 @@ func (e *ConditionalEngine) ImportState(states []TriggerState) {
 	defer e.mu.Unlock()
 
 	for _, s := range states {
-		e.triggers = append(e.triggers, triggerEvent{
+		trigger := triggerEvent{
 			correlationID: s.CorrelationID,
 			timestamp:     s.Timestamp,
 			source:        s.Source,
 			lineNum:       s.LineNum,
+			count:         s.Count,
+			min:           e.countMin,
+			max:           e.countMax,
+		}
+		if e.countGroup > 0 {
+			trigger.min, trigger.max = s.Expected, s.Expected
+		}
+		e.addTrigger(trigger)
+	}
+}
+
+// ExportPreceding returns the preceded_by index for serialization, sorted by
+// correlation ID.
+func (e *ConditionalEngine) ExportPreceding() []TriggerState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	states := make([]TriggerState, 0, len(e.preceding))
+	for _, event := range e.preceding {
+		states = append(states, TriggerState{
+			CorrelationID: event.correlationID,
+			Timestamp:     event.timestamp,
+			Source:        event.source,
+			LineNum:       event.lineNum,
 		})
 	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].CorrelationID < states[j].CorrelationID
+	})
+	return states
+}
+
+// ImportPreceding restores the preceded_by index from serialized state,
+// keeping the later event where an ID is already indexed.
+func (e *ConditionalEngine) ImportPreceding(states []TriggerState) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, s := range states {
+		if prev, ok := e.preceding[s.CorrelationID]; ok && prev.timestamp.After(s.Timestamp) {
+			continue
+		}
+		e.preceding[s.CorrelationID] = triggerEvent{
+			correlationID: s.CorrelationID,
+			timestamp:     s.Timestamp,
+			source:        s.Source,
+			lineNum:       s.LineNum,
+		}
+	}
 }
@@ -2,6 +2,10 @@ package analyzer
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -172,6 +176,164 @@ func TestConditionalEngine_WithCorrelation(t *testing.T) {
 	}
 }
 
+func TestConditionalEngine_Latency(t *testing.T) {
+	engine := createConditionalEngine(t, 10*time.Second, 1)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	lines := []*parser.ParsedLine{
+		{Raw: "ERROR code=500", Timestamp: baseTime},
+		{Raw: "ERROR code=404", Timestamp: baseTime},
+		{Raw: "ALERT code=500", Timestamp: baseTime.Add(3 * time.Second)},
+		{Raw: "ALERT code=404", Timestamp: baseTime.Add(8 * time.Second)},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if result.Stats.Completed != 2 {
+		t.Errorf("Completed = %d, want 2", result.Stats.Completed)
+	}
+	lat := result.Stats.Latency
+	if lat == nil {
+		t.Fatal("Latency should be set")
+	}
+	if lat.Min != 3*time.Second || lat.Max != 8*time.Second {
+		t.Errorf("Min/Max = %v/%v, want 3s/8s", lat.Min, lat.Max)
+	}
+}
+
+func TestConditionalEngine_Latency_Uncorrelated(t *testing.T) {
+	engine := createConditionalEngine(t, 10*time.Second, 0)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	for _, line := range []*parser.ParsedLine{
+		{Raw: "ERROR occurred", Timestamp: baseTime},
+		{Raw: "ALERT sent", Timestamp: baseTime.Add(2 * time.Second)},
+	} {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	// Without correlation, pairing is not meaningful enough to time
+	if result.Stats.Latency != nil {
+		t.Errorf("Latency = %v, want nil for uncorrelated rules", result.Stats.Latency)
+	}
+}
+
+func TestConditionalEngine_NamedCorrelationGroups(t *testing.T) {
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:                      "test",
+			Type:                      "conditional",
+			TriggerPattern:            `ERROR code=(\d+) req=(?P<req>\w+)`,
+			ExpectedPattern:           `ALERT for=(?P<req>\w+)`,
+			TriggerCorrelationGroups:  []string{"req"},
+			ExpectedCorrelationGroups: []string{"req"},
+			Timeout:                   10 * time.Second,
+		}},
+	}
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	engine, err := NewConditionalEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewConditionalEngine() error = %v", err)
+	}
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// The ID is the second group in the trigger but the first in the alert
+	for _, line := range []*parser.ParsedLine{
+		{Raw: "ERROR code=500 req=r1", Timestamp: baseTime},
+		{Raw: "ERROR code=500 req=r2", Timestamp: baseTime},
+		{Raw: "ALERT for=r1", Timestamp: baseTime.Add(time.Second)},
+	} {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %d, want 1", len(result.Issues))
+	}
+	if got := result.Issues[0].Context.CorrelationID; got != "r2" {
+		t.Errorf("CorrelationID = %q, want %q", got, "r2")
+	}
+}
+
+func TestConditionalEngine_Roster(t *testing.T) {
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:             "test",
+			Type:             "conditional",
+			TriggerPattern:   `ERROR code=(\d+)`,
+			ExpectedPattern:  `ALERT code=(\d+)`,
+			CorrelationField: 1,
+			Timeout:          10 * time.Second,
+			Roster:           &config.RosterConfig{Members: []string{"500", "503"}},
+		}},
+	}
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	engine, err := NewConditionalEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewConditionalEngine() error = %v", err)
+	}
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	for _, line := range []*parser.ParsedLine{
+		{Raw: "ERROR code=500", Timestamp: baseTime},
+		{Raw: "ALERT code=500", Timestamp: baseTime.Add(time.Second)},
+	} {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %v, want 1", result.Issues)
+	}
+	issue := result.Issues[0]
+	if issue.Type != IssueTypeAbsentMember || issue.Context.CorrelationID != "503" {
+		t.Errorf("issue = %s id=%s, want absent_member id=503", issue.Type, issue.Context.CorrelationID)
+	}
+}
+
 func TestConditionalEngine_MultipleTriggers(t *testing.T) {
 	engine := createConditionalEngine(t, 10*time.Second, 0)
 
@@ -199,6 +361,550 @@ func TestConditionalEngine_MultipleTriggers(t *testing.T) {
 	}
 }
 
+func TestConditionalEngine_OldestEligibleTrigger(t *testing.T) {
+	engine := createConditionalEngine(t, 10*time.Second, 0)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	lines := []*parser.ParsedLine{
+		{Raw: "ERROR occurred", Timestamp: baseTime, LineNum: 1},
+		{Raw: "ERROR occurred", Timestamp: baseTime.Add(15 * time.Second), LineNum: 2},
+		{Raw: "ERROR occurred", Timestamp: baseTime.Add(18 * time.Second), LineNum: 3},
+		// Too late for line 1, which stays pending; satisfies line 2 only
+		{Raw: "ALERT sent", Timestamp: baseTime.Add(20 * time.Second), LineNum: 4},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	var missing []int
+	for _, issue := range result.Issues {
+		missing = append(missing, issue.Context.LineNum)
+	}
+	if len(missing) != 2 || missing[0] != 1 || missing[1] != 3 {
+		t.Errorf("missing triggers at lines %v, want [1 3]", missing)
+	}
+}
+
+func TestConditionalEngine_CorrelatedPendingOrder(t *testing.T) {
+	engine := createConditionalEngine(t, 10*time.Second, 1)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	lines := []*parser.ParsedLine{
+		{Raw: "ERROR code=503", Timestamp: baseTime, LineNum: 1},
+		{Raw: "ERROR code=500", Timestamp: baseTime.Add(time.Second), LineNum: 2},
+		{Raw: "ERROR code=503", Timestamp: baseTime.Add(2 * time.Second), LineNum: 3},
+		{Raw: "ERROR code=404", Timestamp: baseTime.Add(3 * time.Second), LineNum: 4},
+		{Raw: "ERROR code=500", Timestamp: baseTime.Add(20 * time.Second), LineNum: 5},
+		// Satisfies line 5, but is too late for line 2
+		{Raw: "ALERT code=500", Timestamp: baseTime.Add(25 * time.Second), LineNum: 6},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	state := engine.ExportState()
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	want := []int{1, 2, 3, 4}
+	if len(result.Issues) != len(want) {
+		t.Fatalf("Issues = %d, want %d", len(result.Issues), len(want))
+	}
+	for i, issue := range result.Issues {
+		if issue.Context.LineNum != want[i] {
+			t.Errorf("Issues[%d] at line %d, want %d", i, issue.Context.LineNum, want[i])
+		}
+	}
+	for i, st := range state {
+		if st.LineNum != want[i] {
+			t.Errorf("ExportState()[%d] at line %d, want %d", i, st.LineNum, want[i])
+		}
+	}
+	if result.Stats.Completed != 1 {
+		t.Errorf("Completed = %d, want 1", result.Stats.Completed)
+	}
+}
+
+func TestConditionalEngine_Forbidden(t *testing.T) {
+	engine := createForbiddenEngine(t, `DEPLOY_START`, `ROLLBACK`, 0)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	lines := []*parser.ParsedLine{
+		{Raw: "DEPLOY_START v1", Timestamp: baseTime, Source: "deploy.log", LineNum: 1},
+		{Raw: "ROLLBACK v1", Timestamp: baseTime.Add(3 * time.Minute), Source: "deploy.log", LineNum: 2},
+		{Raw: "DEPLOY_START v2", Timestamp: baseTime.Add(time.Hour), Source: "deploy.log", LineNum: 3},
+		// Outside the 10 minute window of v2, so allowed
+		{Raw: "ROLLBACK v2", Timestamp: baseTime.Add(time.Hour + 15*time.Minute), Source: "deploy.log", LineNum: 4},
+		// Pending without a rollback is fine
+		{Raw: "DEPLOY_START v3", Timestamp: baseTime.Add(2 * time.Hour), Source: "deploy.log", LineNum: 5},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %v, want 1", result.Issues)
+	}
+	issue := result.Issues[0]
+	if issue.Type != IssueTypeForbiddenEvent {
+		t.Errorf("Type = %v, want %v", issue.Type, IssueTypeForbiddenEvent)
+	}
+	if issue.Context.LineNum != 1 || issue.Context.EndLineNum != 2 {
+		t.Errorf("trigger line %d, forbidden line %d, want 1 and 2", issue.Context.LineNum, issue.Context.EndLineNum)
+	}
+	if issue.Context.Duration != 3*time.Minute {
+		t.Errorf("Duration = %v, want 3m", issue.Context.Duration)
+	}
+}
+
+func TestConditionalEngine_ForbiddenRepeat(t *testing.T) {
+	// The same pattern as trigger and forbidden event catches a double charge
+	engine := createForbiddenEngine(t, `PAYMENT_CAPTURED id=(\w+)`, `PAYMENT_CAPTURED id=(\w+)`, 1)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	lines := []*parser.ParsedLine{
+		{Raw: "PAYMENT_CAPTURED id=p1", Timestamp: baseTime, LineNum: 1},
+		{Raw: "PAYMENT_CAPTURED id=p2", Timestamp: baseTime.Add(time.Minute), LineNum: 2},
+		{Raw: "PAYMENT_CAPTURED id=p1", Timestamp: baseTime.Add(2 * time.Minute), LineNum: 3},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %v, want 1", result.Issues)
+	}
+	issue := result.Issues[0]
+	if issue.Context.CorrelationID != "p1" || issue.Context.LineNum != 1 || issue.Context.EndLineNum != 3 {
+		t.Errorf("issue = id=%s lines %d/%d, want id=p1 lines 1/3",
+			issue.Context.CorrelationID, issue.Context.LineNum, issue.Context.EndLineNum)
+	}
+
+	// The repeat is itself pending, so the state carries it forward
+	if state := engine.ExportState(); len(state) != 2 {
+		t.Errorf("ExportState() = %d triggers, want 2", len(state))
+	}
+}
+
+func TestConditionalEngine_ForbiddenUniqueIDs(t *testing.T) {
+	// One capture per payment, so no ID ever triggers twice
+	engine := createForbiddenEngine(t, `PAYMENT_CAPTURED id=(\w+)`, `PAYMENT_CAPTURED id=(\w+)`, 1)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	const payments = 10 * forbidPruneMin
+	for i := 0; i < payments; i++ {
+		line := &parser.ParsedLine{
+			Raw:       fmt.Sprintf("PAYMENT_CAPTURED id=p%d", i),
+			Timestamp: baseTime.Add(time.Duration(i) * time.Second),
+			LineNum:   i + 1,
+		}
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	if len(engine.byID) > 2*forbidPruneMin {
+		t.Errorf("byID = %d IDs, want expired ones pruned", len(engine.byID))
+	}
+
+	// Only the payments within the 10m timeout of the last line remain
+	if state := engine.ExportState(); len(state) != 601 {
+		t.Errorf("ExportState() = %d triggers, want 601", len(state))
+	}
+}
+
+func TestConditionalEngine_PrecededBy(t *testing.T) {
+	engine := createPrecededByEngine(t)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	lines := []*parser.ParsedLine{
+		{Raw: "REFUND_APPROVED order=a1", Timestamp: baseTime, LineNum: 1},
+		{Raw: "REFUND_APPROVED order=b2", Timestamp: baseTime, LineNum: 2},
+		{Raw: "REFUND_ISSUED order=a1", Timestamp: baseTime.Add(time.Hour), LineNum: 3},
+		// Never approved
+		{Raw: "REFUND_ISSUED order=c3", Timestamp: baseTime.Add(2 * time.Hour), LineNum: 4},
+		// Approved, but more than 24h earlier
+		{Raw: "REFUND_ISSUED order=b2", Timestamp: baseTime.Add(30 * time.Hour), LineNum: 5},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	var ids []string
+	for _, issue := range result.Issues {
+		if issue.Type != IssueTypeUnprecededEvent {
+			t.Errorf("Type = %v, want %v", issue.Type, IssueTypeUnprecededEvent)
+		}
+		ids = append(ids, issue.Context.CorrelationID)
+	}
+	if len(ids) != 2 || ids[0] != "c3" || ids[1] != "b2" {
+		t.Errorf("unpreceded = %v, want [c3 b2]", ids)
+	}
+	if !strings.Contains(result.Issues[1].Description, "30h0m0s earlier") {
+		t.Errorf("Description = %q, want the age of the stale approval", result.Issues[1].Description)
+	}
+	if result.Stats.Completed != 1 {
+		t.Errorf("Completed = %d, want 1", result.Stats.Completed)
+	}
+}
+
+func TestConditionalEngine_PrecededBy_State(t *testing.T) {
+	engine := createPrecededByEngine(t)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	if err := engine.Process(ctx, &parser.ParsedLine{
+		Raw:       "REFUND_APPROVED order=a1",
+		Timestamp: baseTime,
+	}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	state := engine.ExportPreceding()
+	if len(state) != 1 || state[0].CorrelationID != "a1" {
+		t.Fatalf("ExportPreceding() = %+v, want a1", state)
+	}
+
+	// A fresh run restores the approval from the previous one
+	restored := createPrecededByEngine(t)
+	restored.ImportPreceding(state)
+	if err := restored.Process(ctx, &parser.ParsedLine{
+		Raw:       "REFUND_ISSUED order=a1",
+		Timestamp: baseTime.Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	result, err := restored.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	if len(result.Issues) != 0 {
+		t.Errorf("Issues = %v, want none", result.Issues)
+	}
+}
+
+func TestConditionalEngine_PrecededBy_Prune(t *testing.T) {
+	engine := createPrecededByEngine(t)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// Old approvals are dropped once the index grows, recent ones kept
+	for i := 0; i < 2*precedingPruneMin; i++ {
+		ts := baseTime
+		if i >= precedingPruneMin/2 {
+			ts = baseTime.Add(48 * time.Hour)
+		}
+		if err := engine.Process(ctx, &parser.ParsedLine{
+			Raw:       "REFUND_APPROVED order=o" + strconv.Itoa(i),
+			Timestamp: ts,
+		}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	if got, want := len(engine.preceding), 2*precedingPruneMin-precedingPruneMin/2; got != want {
+		t.Errorf("index size = %d, want %d", got, want)
+	}
+}
+
+func TestConditionalEngine_ExpectedCount(t *testing.T) {
+	tests := []struct {
+		name  string
+		count config.CountConfig
+		items map[string]int // ITEM_DONE lines per batch
+		want  map[string]int // batch -> reported count
+		noMax bool
+	}{
+		{
+			name:  "from group",
+			count: config.CountConfig{FromGroup: "size"},
+			items: map[string]int{"b1": 3, "b2": 2, "b3": 4},
+			want:  map[string]int{"b2": 2, "b3": 4},
+		},
+		{
+			name:  "at least",
+			count: config.CountConfig{Min: 3},
+			items: map[string]int{"b1": 3, "b2": 2, "b3": 5},
+			want:  map[string]int{"b2": 2},
+			noMax: true,
+		},
+		{
+			name:  "bounds",
+			count: config.CountConfig{Min: 2, Max: 3},
+			items: map[string]int{"b1": 1, "b2": 2, "b3": 4},
+			want:  map[string]int{"b1": 1, "b3": 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count := tt.count
+			engine := createCountEngine(t, &count)
+
+			ctx := context.Background()
+			baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+			n := 0
+			process := func(raw string) {
+				n++
+				if err := engine.Process(ctx, &parser.ParsedLine{
+					Raw:       raw,
+					Timestamp: baseTime.Add(time.Duration(n) * time.Second),
+					LineNum:   n,
+				}); err != nil {
+					t.Fatalf("Process() error = %v", err)
+				}
+			}
+
+			for _, id := range []string{"b1", "b2", "b3"} {
+				process("BATCH_START id=" + id + " size=3")
+			}
+			for _, id := range []string{"b1", "b2", "b3"} {
+				for i := 0; i < tt.items[id]; i++ {
+					process("ITEM_DONE batch=" + id)
+				}
+			}
+
+			// Close the window past every batch's timeout
+			engine.SetWindow(TimeRange{Start: baseTime, End: baseTime.Add(time.Hour)})
+
+			result, err := engine.Finalize(ctx)
+			if err != nil {
+				t.Fatalf("Finalize() error = %v", err)
+			}
+
+			got := make(map[string]int)
+			for _, issue := range result.Issues {
+				if issue.Type != IssueTypeConsequenceCount {
+					t.Errorf("Type = %v, want %v", issue.Type, IssueTypeConsequenceCount)
+				}
+				if issue.Context.NoMaxAllowed != tt.noMax {
+					t.Errorf("NoMaxAllowed = %v, want %v", issue.Context.NoMaxAllowed, tt.noMax)
+				}
+				got[issue.Context.CorrelationID] = issue.Context.Occurrences
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("reported counts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionalEngine_ExpectedCount_WindowClosed(t *testing.T) {
+	engine := createCountEngine(t, &config.CountConfig{Min: 1, Max: 2})
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	lines := []*parser.ParsedLine{
+		{Raw: "BATCH_START id=b1 size=2", Timestamp: baseTime},
+		{Raw: "ITEM_DONE batch=b1", Timestamp: baseTime.Add(time.Minute)},
+		{Raw: "ITEM_DONE batch=b1", Timestamp: baseTime.Add(2 * time.Minute)},
+		{Raw: "ITEM_DONE batch=b1", Timestamp: baseTime.Add(3 * time.Minute)},
+		// Outside the 5m timeout, so not counted; it closes the window
+		{Raw: "ITEM_DONE batch=b1", Timestamp: baseTime.Add(10 * time.Minute)},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	if len(engine.byID) != 0 {
+		t.Errorf("pending = %v, want judged and removed", engine.byID)
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %d, want 1", len(result.Issues))
+	}
+	issue := result.Issues[0]
+	if issue.Context.Occurrences != 3 || issue.Context.MinRequired != 1 || issue.Context.MaxAllowed != 2 {
+		t.Errorf("Context = %+v, want 3 occurrences against 1 to 2", issue.Context)
+	}
+	if !strings.Contains(issue.Description, "want 1 to 2") {
+		t.Errorf("Description = %q", issue.Description)
+	}
+}
+
+func TestConditionalEngine_ExpectedCount_StillOpen(t *testing.T) {
+	engine := createCountEngine(t, &config.CountConfig{Min: 2, Max: 3})
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	lines := []*parser.ParsedLine{
+		// Timed out by the window end with a count in bounds
+		{Raw: "BATCH_START id=b1 size=2", Timestamp: baseTime},
+		{Raw: "ITEM_DONE batch=b1", Timestamp: baseTime.Add(time.Minute)},
+		{Raw: "ITEM_DONE batch=b1", Timestamp: baseTime.Add(2 * time.Minute)},
+		// Within its timeout and short, so it may still complete
+		{Raw: "BATCH_START id=b2 size=2", Timestamp: baseTime.Add(8 * time.Minute)},
+		{Raw: "ITEM_DONE batch=b2", Timestamp: baseTime.Add(9 * time.Minute)},
+		// Within its timeout but already over the maximum
+		{Raw: "BATCH_START id=b3 size=2", Timestamp: baseTime.Add(8 * time.Minute)},
+		{Raw: "ITEM_DONE batch=b3", Timestamp: baseTime.Add(9 * time.Minute)},
+		{Raw: "ITEM_DONE batch=b3", Timestamp: baseTime.Add(9 * time.Minute)},
+		{Raw: "ITEM_DONE batch=b3", Timestamp: baseTime.Add(9 * time.Minute)},
+		{Raw: "ITEM_DONE batch=b3", Timestamp: baseTime.Add(10 * time.Minute)},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Context.CorrelationID != "b3" {
+		t.Fatalf("Issues = %+v, want only b3 over its maximum", result.Issues)
+	}
+	if result.Stats.Completed != 1 {
+		t.Errorf("Completed = %d, want 1", result.Stats.Completed)
+	}
+	if result.Stats.Pending != 1 {
+		t.Errorf("Pending = %d, want 1", result.Stats.Pending)
+	}
+	if result.Stats.Latency == nil || result.Stats.Latency.Max != 2*time.Minute {
+		t.Errorf("Latency = %+v, want b1's 2m", result.Stats.Latency)
+	}
+}
+
+func TestConditionalEngine_ExpectedCount_InvalidGroup(t *testing.T) {
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:             "test",
+			Type:             "conditional",
+			TriggerPattern:   `BATCH_START id=(\w+) size=(\w+)`,
+			ExpectedPattern:  `ITEM_DONE batch=(\w+)`,
+			CorrelationField: 1,
+			ExpectedCount:    &config.CountConfig{FromGroup: "2"},
+			Timeout:          5 * time.Minute,
+		}},
+	}
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	engine, err := NewConditionalEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewConditionalEngine() error = %v", err)
+	}
+
+	ctx := context.Background()
+	if err := engine.Process(ctx, &parser.ParsedLine{
+		Raw:       "BATCH_START id=b1 size=many",
+		Timestamp: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+	}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	if len(result.Issues) != 1 || !strings.Contains(result.Issues[0].Description, `invalid expected count "many"`) {
+		t.Fatalf("Issues = %+v, want one invalid count", result.Issues)
+	}
+	if got := result.Issues[0].Context.InvalidCount; got != "many" {
+		t.Errorf("InvalidCount = %q, want many", got)
+	}
+}
+
+func TestConditionalEngine_ExpectedCount_State(t *testing.T) {
+	engine := createCountEngine(t, &config.CountConfig{FromGroup: "size"})
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	for i, raw := range []string{"BATCH_START id=b1 size=3", "ITEM_DONE batch=b1"} {
+		if err := engine.Process(ctx, &parser.ParsedLine{
+			Raw:       raw,
+			Timestamp: baseTime.Add(time.Duration(i) * time.Second),
+		}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	state := engine.ExportState()
+	if len(state) != 1 || state[0].Count != 1 || state[0].Expected != 3 {
+		t.Fatalf("ExportState() = %+v, want count 1 of 3", state)
+	}
+
+	restored := createCountEngine(t, &config.CountConfig{FromGroup: "size"})
+	restored.ImportState(state)
+	for i := 0; i < 2; i++ {
+		if err := restored.Process(ctx, &parser.ParsedLine{
+			Raw:       "ITEM_DONE batch=b1",
+			Timestamp: baseTime.Add(time.Minute),
+		}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := restored.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	if len(result.Issues) != 0 {
+		t.Errorf("Issues = %+v, want none", result.Issues)
+	}
+}
+
 func TestConditionalEngine_NoTriggers(t *testing.T) {
 	engine := createConditionalEngine(t, 10*time.Second, 0)
 
@@ -248,7 +954,149 @@ func TestConditionalEngine_Reset(t *testing.T) {
 	}
 }
 
-func createConditionalEngine(t *testing.T, timeout time.Duration, corrField int) *ConditionalEngine {
+func BenchmarkConditionalEngine_Pending(b *testing.B) {
+	const triggers = 1_000_000
+
+	benchmarks := []struct {
+		name      string
+		corrField int
+	}{
+		{"correlated", 1},
+		{"uncorrelated", 0},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			engine := createConditionalEngine(b, time.Hour, bm.corrField)
+
+			ctx := context.Background()
+			baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+			trigger := make([]string, triggers)
+			expected := make([]string, triggers)
+			for i := range trigger {
+				trigger[i] = "ERROR code=" + strconv.Itoa(i)
+				expected[i] = "ALERT code=" + strconv.Itoa(i)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				engine.Reset()
+				line := &parser.ParsedLine{Timestamp: baseTime}
+
+				// All triggers pend before any consequence arrives
+				for i := range trigger {
+					line.Raw = trigger[i]
+					if err := engine.Process(ctx, line); err != nil {
+						b.Fatalf("Process() error = %v", err)
+					}
+				}
+				for i := range expected {
+					line.Raw = expected[i]
+					if err := engine.Process(ctx, line); err != nil {
+						b.Fatalf("Process() error = %v", err)
+					}
+				}
+
+				result, err := engine.Finalize(ctx)
+				if err != nil {
+					b.Fatalf("Finalize() error = %v", err)
+				}
+				if len(result.Issues) != 0 {
+					b.Fatalf("Issues = %d, want 0", len(result.Issues))
+				}
+			}
+		})
+	}
+}
+
+func createForbiddenEngine(t *testing.T, trigger, forbidden string, corrField int) *ConditionalEngine {
+	t.Helper()
+
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:             "test",
+			Type:             "conditional",
+			TriggerPattern:   trigger,
+			ForbiddenPattern: forbidden,
+			CorrelationField: corrField,
+			Timeout:          10 * time.Minute,
+		}},
+	}
+
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	engine, err := NewConditionalEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewConditionalEngine() error = %v", err)
+	}
+
+	return engine
+}
+
+func createPrecededByEngine(t *testing.T) *ConditionalEngine {
+	t.Helper()
+
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:              "test",
+			Type:              "conditional",
+			TriggerPattern:    `REFUND_ISSUED order=(?P<order>\w+)`,
+			PrecededBy:        `REFUND_APPROVED order=(?P<order>\w+)`,
+			CorrelationGroups: []string{"order"},
+			Timeout:           24 * time.Hour,
+		}},
+	}
+
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	engine, err := NewConditionalEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewConditionalEngine() error = %v", err)
+	}
+
+	return engine
+}
+
+func createCountEngine(t *testing.T, count *config.CountConfig) *ConditionalEngine {
+	t.Helper()
+
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:              "test",
+			Type:              "conditional",
+			TriggerPattern:    `BATCH_START id=(?P<id>\w+) size=(?P<size>\d+)`,
+			ExpectedPattern:   `ITEM_DONE batch=(?P<id>\w+)`,
+			CorrelationGroups: []string{"id"},
+			ExpectedCount:     count,
+			Timeout:           5 * time.Minute,
+		}},
+	}
+
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	engine, err := NewConditionalEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewConditionalEngine() error = %v", err)
+	}
+
+	return engine
+}
+
+func createConditionalEngine(t testing.TB, timeout time.Duration, corrField int) *ConditionalEngine {
 	t.Helper()
 
 	triggerPattern := `ERROR`
@@ -282,3 +1130,54 @@ func createConditionalEngine(t *testing.T, timeout time.Duration, corrField int)
 
 	return engine
 }
+
+func TestConditionalEngine_Calendar(t *testing.T) {
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Calendars:       businessHours(),
+		Rules: []config.RuleConfig{{
+			Name:             "test",
+			Type:             "conditional",
+			TriggerPattern:   `ERROR code=(\d+)`,
+			ExpectedPattern:  `ALERT code=(\d+)`,
+			CorrelationField: 1,
+			Timeout:          time.Hour,
+			Calendar:         "business-hours",
+		}},
+	}
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	engine, err := NewConditionalEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewConditionalEngine() error = %v", err)
+	}
+
+	ctx := context.Background()
+	friday := time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)
+	lines := []*parser.ParsedLine{
+		{Raw: "ERROR code=500", Timestamp: friday.Add(16*time.Hour + 30*time.Minute)},
+		{Raw: "ERROR code=503", Timestamp: friday.Add(16*time.Hour + 40*time.Minute)},
+		// Monday: 45m and 1h05m of business hours after the triggers
+		{Raw: "ALERT code=500", Timestamp: friday.Add(3*24*time.Hour + 9*time.Hour + 15*time.Minute)},
+		{Raw: "ALERT code=503", Timestamp: friday.Add(3*24*time.Hour + 9*time.Hour + 45*time.Minute)},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %d, want 1", len(result.Issues))
+	}
+	if id := result.Issues[0].Context.CorrelationID; id != "503" {
+		t.Errorf("CorrelationID = %q, want 503", id)
+	}
+}
@@ -7,12 +7,12 @@ import (
 )
 
 // RuleEngine processes log lines and detects missing log patterns.
-// Each detection strategy (sequence, periodic, conditional) implements this interface.
+// Each detection strategy (sequence, periodic, conditional, rate, reconciliation, baseline, source_silence, ordering) implements this interface.
 type RuleEngine interface {
 	// Name returns the rule name for reporting.
 	Name() string
 
-	// Type returns the rule type (sequence, periodic, conditional).
+	// Type returns the rule type (sequence, periodic, conditional, rate, reconciliation, baseline, source_silence, ordering).
 	Type() RuleType
 
 	// Process handles a single log line, updating internal state.
@@ -26,3 +26,22 @@ type RuleEngine interface {
 	// Reset clears internal state for reuse.
 	Reset()
 }
+
+// WindowAware is implemented by engines whose findings depend on the bounds
+// of the analysis window, such as how long an open sequence has been waiting.
+// The analyzer calls SetWindow after all lines are processed and before Finalize.
+type WindowAware interface {
+	// SetWindow provides the analysis window. Window.End is the evaluation
+	// clock: the --now time if given, otherwise the last log timestamp seen.
+	SetWindow(window TimeRange)
+}
+
+// SourceAware is implemented by engines that judge the log files themselves
+// rather than the lines in them. The analyzer calls SetSources after all
+// lines are processed and before Finalize.
+type SourceAware interface {
+	// SetSources provides the log files that matched log_sources and, of
+	// those, the ones that produced at least one parseable line (whether or
+	// not it fell within the time range).
+	SetSources(files []string, parsed map[string]bool)
+}
@@ -0,0 +1,232 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/ccollicutt/negalog/pkg/config"
+	"github.com/ccollicutt/negalog/pkg/parser"
+)
+
+// orderingKey identifies the events whose order is checked together.
+type orderingKey struct {
+	source string // empty unless the rule is per_source
+	id     string // correlation ID, empty if the rule is not correlated
+}
+
+// orderingEvent is an after_pattern match waiting to be contradicted.
+type orderingEvent struct {
+	timestamp time.Time
+	source    string
+	lineNum   int
+}
+
+// OrderingEngine implements RuleEngine for ordering violations.
+// It remembers the first after_pattern match per correlation ID and reports
+// a before_pattern match for the same ID that comes later, with both lines.
+// Order is decided by timestamp, and by line number within one file, since
+// lines from different files do not arrive in time order.
+type OrderingEngine struct {
+	name        string
+	description string
+	perSource   bool
+	window      time.Duration // zero means after_pattern matches never expire
+
+	before    *regexp.Regexp
+	after     *regexp.Regexp
+	beforeKey config.CorrelationKey // empty if not correlated
+	afterKey  config.CorrelationKey
+
+	// State
+	mu     sync.Mutex
+	afters map[orderingKey]orderingEvent
+	issues []Issue
+	stats  RuleStats
+
+	// Entries older than the window are pruned once afters reaches pruneAt.
+	pruneAt int
+}
+
+// orderingPruneMin is the smallest afters index size that triggers pruning.
+const orderingPruneMin = 1024
+
+// NewOrderingEngine creates a new ordering engine from a rule config.
+func NewOrderingEngine(rule *config.RuleConfig) (*OrderingEngine, error) {
+	if rule.RuleTypeEnum() != config.RuleTypeOrdering {
+		return nil, fmt.Errorf("rule %q is not an ordering rule", rule.Name)
+	}
+
+	before := rule.CompiledBeforePattern()
+	after := rule.CompiledAfterPattern()
+	if before == nil || after == nil {
+		return nil, fmt.Errorf("rule %q has uncompiled patterns", rule.Name)
+	}
+
+	return &OrderingEngine{
+		name:        rule.Name,
+		description: rule.Description,
+		perSource:   rule.PerSource,
+		window:      rule.Window,
+		before:      before,
+		after:       after,
+		beforeKey:   rule.BeforeCorrelationKey(),
+		afterKey:    rule.AfterCorrelationKey(),
+		afters:      make(map[orderingKey]orderingEvent),
+		pruneAt:     orderingPruneMin,
+	}, nil
+}
+
+// Name returns the rule name.
+func (e *OrderingEngine) Name() string {
+	return e.name
+}
+
+// Type returns the rule type.
+func (e *OrderingEngine) Type() RuleType {
+	return RuleTypeOrdering
+}
+
+// key returns the ordering key of a match, and false if the correlation ID
+// could not be extracted.
+func (e *OrderingEngine) key(ck config.CorrelationKey, matches []string, line *parser.ParsedLine) (orderingKey, bool) {
+	var k orderingKey
+	if e.perSource {
+		k.source = line.Source
+	}
+	if len(ck) > 0 {
+		id, ok := ck.Extract(matches)
+		if !ok {
+			return k, false
+		}
+		k.id = id
+	}
+	return k, true
+}
+
+// Process handles a single log line.
+func (e *OrderingEngine) Process(ctx context.Context, line *parser.ParsedLine) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stats.LinesProcessed++
+
+	if matches := e.after.FindStringSubmatch(line.Raw); matches != nil {
+		e.stats.LinesMatched++
+		k, ok := e.key(e.afterKey, matches, line)
+		if !ok {
+			return nil
+		}
+		if _, exists := e.afters[k]; !exists {
+			e.recordAfter(k, orderingEvent{timestamp: line.Timestamp, source: line.Source, lineNum: line.LineNum})
+		}
+		return nil
+	}
+
+	matches := e.before.FindStringSubmatch(line.Raw)
+	if matches == nil {
+		return nil
+	}
+	e.stats.LinesMatched++
+
+	k, ok := e.key(e.beforeKey, matches, line)
+	if !ok {
+		return nil
+	}
+	after, ok := e.afters[k]
+	if !ok {
+		return nil
+	}
+	if e.expired(after, line.Timestamp) {
+		delete(e.afters, k)
+		return nil
+	}
+	if !after.timestamp.Before(line.Timestamp) && (after.source != line.Source || after.lineNum > line.LineNum) {
+		// The before_pattern event came first. A correlated pair is then
+		// done; without correlation any later line may still break the order.
+		if k.id != "" {
+			delete(e.afters, k)
+		}
+		return nil
+	}
+	delete(e.afters, k)
+
+	desc := fmt.Sprintf("after_pattern at %s (%s:%d) came before before_pattern at %s (%s:%d)",
+		after.timestamp.Format(time.RFC3339), after.source, after.lineNum,
+		line.Timestamp.Format(time.RFC3339), line.Source, line.LineNum)
+	if k.id != "" {
+		desc = fmt.Sprintf("%s: %s", k.id, desc)
+	}
+
+	e.issues = append(e.issues, Issue{
+		Type:        IssueTypeOrderViolation,
+		Description: desc,
+		Context: IssueContext{
+			CorrelationID: k.id,
+			StartTime:     after.timestamp,
+			EndTime:       line.Timestamp,
+			Source:        after.source,
+			LineNum:       after.lineNum,
+			EndSource:     line.Source,
+			EndLineNum:    line.LineNum,
+		},
+	})
+
+	return nil
+}
+
+// expired returns true if an after_pattern match is older than the window at t.
+func (e *OrderingEngine) expired(after orderingEvent, t time.Time) bool {
+	return e.window > 0 && t.Sub(after.timestamp) > e.window
+}
+
+// recordAfter indexes an after_pattern match, pruning entries that are too
+// old to be contradicted by anything still to come.
+func (e *OrderingEngine) recordAfter(k orderingKey, event orderingEvent) {
+	e.afters[k] = event
+
+	if e.window == 0 || len(e.afters) < e.pruneAt {
+		return
+	}
+	for key, after := range e.afters {
+		if e.expired(after, event.timestamp) {
+			delete(e.afters, key)
+		}
+	}
+	e.pruneAt = max(2*len(e.afters), orderingPruneMin)
+}
+
+// Finalize completes analysis and returns detected issues.
+func (e *OrderingEngine) Finalize(ctx context.Context) (*RuleResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stats.EndTime = time.Now()
+
+	result := &RuleResult{
+		RuleName:    e.name,
+		RuleType:    RuleTypeOrdering,
+		Description: e.description,
+		Issues:      e.issues,
+		Stats:       e.stats,
+	}
+	e.issues = nil
+	if result.Issues == nil {
+		result.Issues = make([]Issue, 0)
+	}
+
+	return result, nil
+}
+
+// Reset clears internal state for reuse.
+func (e *OrderingEngine) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.afters = make(map[orderingKey]orderingEvent)
+	e.issues = nil
+	e.stats = RuleStats{}
+	e.pruneAt = orderingPruneMin
+}
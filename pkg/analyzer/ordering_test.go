@@ -0,0 +1,241 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ccollicutt/negalog/pkg/config"
+	"github.com/ccollicutt/negalog/pkg/parser"
+)
+
+func TestNewOrderingEngine_WrongType(t *testing.T) {
+	rule := &config.RuleConfig{
+		Name: "test",
+		Type: "rate",
+	}
+	if _, err := NewOrderingEngine(rule); err == nil {
+		t.Error("NewOrderingEngine() expected error for wrong type")
+	}
+}
+
+func TestOrderingEngine_Correlated(t *testing.T) {
+	engine := createOrderingEngine(t, config.RuleConfig{
+		BeforePattern:    `PREPARE txn=(\w+)`,
+		AfterPattern:     `COMMIT txn=(\w+)`,
+		CorrelationField: 1,
+	})
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	lines := []*parser.ParsedLine{
+		{Raw: "PREPARE txn=1", Timestamp: baseTime, Source: "db.log", LineNum: 1},
+		{Raw: "COMMIT txn=1", Timestamp: baseTime.Add(time.Second), Source: "db.log", LineNum: 2},
+		{Raw: "COMMIT txn=2", Timestamp: baseTime.Add(2 * time.Second), Source: "db.log", LineNum: 3},
+		{Raw: "COMMIT txn=3", Timestamp: baseTime.Add(3 * time.Second), Source: "db.log", LineNum: 4},
+		{Raw: "PREPARE txn=2", Timestamp: baseTime.Add(4 * time.Second), Source: "coordinator.log", LineNum: 9},
+		{Raw: "PREPARE txn=2", Timestamp: baseTime.Add(5 * time.Second), Source: "coordinator.log", LineNum: 10},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	// txn=3 was never prepared, which is not an ordering violation
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %v, want 1", result.Issues)
+	}
+
+	issue := result.Issues[0]
+	if issue.Type != IssueTypeOrderViolation || issue.Context.CorrelationID != "2" {
+		t.Errorf("issue = %v, want txn=2 order_violation", issue)
+	}
+	if issue.Context.Source != "db.log" || issue.Context.LineNum != 3 {
+		t.Errorf("after line = %s:%d, want db.log:3", issue.Context.Source, issue.Context.LineNum)
+	}
+	if issue.Context.EndSource != "coordinator.log" || issue.Context.EndLineNum != 9 {
+		t.Errorf("before line = %s:%d, want coordinator.log:9", issue.Context.EndSource, issue.Context.EndLineNum)
+	}
+}
+
+func TestOrderingEngine_LastLine(t *testing.T) {
+	engine := createOrderingEngine(t, config.RuleConfig{
+		BeforePattern: `.`,
+		AfterPattern:  `SHUTDOWN_COMPLETE`,
+		PerSource:     true,
+	})
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	lines := []*parser.ParsedLine{
+		{Raw: "SHUTDOWN_BEGIN", Timestamp: baseTime, Source: "api.log", LineNum: 1},
+		{Raw: "SHUTDOWN_COMPLETE", Timestamp: baseTime.Add(time.Second), Source: "api.log", LineNum: 2},
+		{Raw: "SHUTDOWN_BEGIN", Timestamp: baseTime.Add(time.Second), Source: "worker.log", LineNum: 1},
+		{Raw: "SHUTDOWN_COMPLETE", Timestamp: baseTime.Add(2 * time.Second), Source: "worker.log", LineNum: 2},
+		// Other files writing on is fine; api.log writing on is not
+		{Raw: "db still running", Timestamp: baseTime.Add(3 * time.Second), Source: "db.log", LineNum: 1},
+		{Raw: "flushed late", Timestamp: baseTime.Add(4 * time.Second), Source: "api.log", LineNum: 3},
+		{Raw: "flushed again", Timestamp: baseTime.Add(5 * time.Second), Source: "api.log", LineNum: 4},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %v, want 1", result.Issues)
+	}
+	issue := result.Issues[0]
+	if issue.Context.LineNum != 2 || issue.Context.EndSource != "api.log" || issue.Context.EndLineNum != 3 {
+		t.Errorf("issue = %+v, want api.log:2 followed by api.log:3", issue.Context)
+	}
+}
+
+func TestOrderingEngine_Timestamps(t *testing.T) {
+	engine := createOrderingEngine(t, config.RuleConfig{
+		BeforePattern:    `PREPARE txn=(\w+)`,
+		AfterPattern:     `COMMIT txn=(\w+)`,
+		CorrelationField: 1,
+	})
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// db.log is read before coordinator.log, so arrival order says nothing
+	lines := []*parser.ParsedLine{
+		{Raw: "COMMIT txn=1", Timestamp: baseTime.Add(time.Second), Source: "db.log", LineNum: 1},
+		{Raw: "COMMIT txn=2", Timestamp: baseTime.Add(time.Second), Source: "db.log", LineNum: 2},
+		{Raw: "COMMIT txn=3", Timestamp: baseTime.Add(time.Second), Source: "db.log", LineNum: 3},
+		{Raw: "PREPARE txn=3", Timestamp: baseTime.Add(time.Second), Source: "db.log", LineNum: 4},
+		// Earlier than its commit
+		{Raw: "PREPARE txn=1", Timestamp: baseTime, Source: "coordinator.log", LineNum: 1},
+		// Tied with its commit in another file
+		{Raw: "PREPARE txn=2", Timestamp: baseTime.Add(time.Second), Source: "coordinator.log", LineNum: 2},
+		// Done, so a later prepare is not compared
+		{Raw: "PREPARE txn=1", Timestamp: baseTime.Add(time.Minute), Source: "coordinator.log", LineNum: 3},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	// Only txn=3, tied in the same file where line numbers give the order
+	if len(result.Issues) != 1 || result.Issues[0].Context.CorrelationID != "3" {
+		t.Fatalf("Issues = %v, want only txn=3", result.Issues)
+	}
+	if len(engine.afters) != 0 {
+		t.Errorf("afters = %v, want every pair done", engine.afters)
+	}
+}
+
+func TestOrderingEngine_Window(t *testing.T) {
+	engine := createOrderingEngine(t, config.RuleConfig{
+		BeforePattern:    `PREPARE txn=(\w+)`,
+		AfterPattern:     `COMMIT txn=(\w+)`,
+		CorrelationField: 1,
+		Window:           time.Hour,
+	})
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	process := func(raw string, ts time.Time) {
+		if err := engine.Process(ctx, &parser.ParsedLine{Raw: raw, Timestamp: ts, Source: "db.log"}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	// Commits that are never prepared again, then enough later ones to prune
+	for i := 0; i < orderingPruneMin; i++ {
+		process(fmt.Sprintf("COMMIT txn=old%d", i), baseTime)
+	}
+	later := baseTime.Add(2 * time.Hour)
+	for i := 0; i < orderingPruneMin; i++ {
+		process(fmt.Sprintf("COMMIT txn=new%d", i), later)
+	}
+	if len(engine.afters) > orderingPruneMin {
+		t.Errorf("afters = %d entries, want old commits pruned", len(engine.afters))
+	}
+
+	// Too late to compare with old0; within the window of new0
+	process("PREPARE txn=old0", later)
+	process("PREPARE txn=new0", later.Add(time.Minute))
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Context.CorrelationID != "new0" {
+		t.Errorf("Issues = %v, want only new0", result.Issues)
+	}
+}
+
+func TestOrderingEngine_Reset(t *testing.T) {
+	engine := createOrderingEngine(t, config.RuleConfig{
+		BeforePattern: `PREPARE`,
+		AfterPattern:  `COMMIT`,
+	})
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	if err := engine.Process(ctx, &parser.ParsedLine{Raw: "COMMIT", Timestamp: baseTime}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	engine.Reset()
+	if err := engine.Process(ctx, &parser.ParsedLine{Raw: "PREPARE", Timestamp: baseTime.Add(time.Second)}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	if len(result.Issues) != 0 {
+		t.Errorf("Issues = %v, want none after Reset", result.Issues)
+	}
+}
+
+func createOrderingEngine(t *testing.T, rule config.RuleConfig) *OrderingEngine {
+	t.Helper()
+
+	rule.Name = "test"
+	rule.Type = "ordering"
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules:           []config.RuleConfig{rule},
+	}
+
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	engine, err := NewOrderingEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewOrderingEngine() error = %v", err)
+	}
+
+	return engine
+}
@@ -4,13 +4,19 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/ccollicutt/negalog/pkg/calendar"
 	"github.com/ccollicutt/negalog/pkg/config"
+	"github.com/ccollicutt/negalog/pkg/cron"
 	"github.com/ccollicutt/negalog/pkg/parser"
 )
 
+// scheduleTimeFormat is how scheduled times are shown in issue descriptions.
+const scheduleTimeFormat = "2006-01-02 15:04 MST"
+
 // periodicMatch tracks a single match for periodic analysis.
 type periodicMatch struct {
 	timestamp time.Time
@@ -18,20 +24,47 @@ type periodicMatch struct {
 	lineNum   int
 }
 
+// periodicGroup holds the streaming state for one group_by value.
+type periodicGroup struct {
+	last periodicMatch // most recent occurrence, possibly restored from state
+
+	// Schedule mode: the slot the group's first occurrence in this window was
+	// matched against, and the next slot not yet met. Both are zero until
+	// that first occurrence.
+	firstSlot time.Time
+	slot      time.Time
+}
+
 // PeriodicEngine implements RuleEngine for periodic absence detection.
 // It tracks recurring log entries and reports gaps exceeding the threshold.
+//
+// Lines are evaluated as they stream in, which assumes they arrive in
+// timestamp order. Only the last occurrence of each group is kept, so memory
+// does not grow with the number of matches.
 type PeriodicEngine struct {
 	name           string
 	description    string
 	maxGap         time.Duration
 	minOccurrences int
+	calendar       *calendar.Calendar // gaps count active time only; nil means always active
 
-	pattern *regexp.Regexp
+	// Cron schedule mode, used instead of max_gap when schedule is set
+	schedule  *cron.Schedule
+	tolerance time.Duration
+	location  *time.Location
+
+	pattern    *regexp.Regexp
+	groupField int      // 0 means not grouped, 1+ is capture group index
+	roster     []string // group values expected to appear in the window
 
 	// State
-	mu      sync.Mutex
-	matches []periodicMatch
-	stats   RuleStats
+	mu     sync.Mutex
+	groups map[string]*periodicGroup // per group_by value, "" if not grouped
+	first  *periodicMatch            // earliest occurrence of any group
+	issues []Issue                   // issues found while processing
+	seen   TimeRange                 // span of line timestamps processed
+	window TimeRange                 // analysis window set by the analyzer
+	stats  RuleStats
 }
 
 // NewPeriodicEngine creates a new periodic detection engine from a rule config.
@@ -45,13 +78,24 @@ func NewPeriodicEngine(rule *config.RuleConfig) (*PeriodicEngine, error) {
 		return nil, fmt.Errorf("rule %q has uncompiled pattern", rule.Name)
 	}
 
+	var roster []string
+	if rule.Roster != nil {
+		roster = rule.Roster.Expected()
+	}
+
 	return &PeriodicEngine{
 		name:           rule.Name,
 		description:    rule.Description,
 		maxGap:         rule.MaxGap,
 		minOccurrences: rule.MinOccurrences,
+		calendar:       rule.ActiveCalendar(),
+		schedule:       rule.CompiledSchedule(),
+		tolerance:      rule.Tolerance,
+		location:       rule.Location(),
 		pattern:        pattern,
-		matches:        make([]periodicMatch, 0),
+		groupField:     rule.GroupIndex(),
+		roster:         roster,
+		groups:         make(map[string]*periodicGroup),
 	}, nil
 }
 
@@ -71,20 +115,160 @@ func (e *PeriodicEngine) Process(ctx context.Context, line *parser.ParsedLine) e
 	defer e.mu.Unlock()
 
 	e.stats.LinesProcessed++
+	if e.stats.LinesProcessed == 1 || line.Timestamp.Before(e.seen.Start) {
+		e.seen.Start = line.Timestamp
+	}
+	if e.stats.LinesProcessed == 1 || line.Timestamp.After(e.seen.End) {
+		e.seen.End = line.Timestamp
+	}
 
-	if e.pattern.MatchString(line.Raw) {
-		e.matches = append(e.matches, periodicMatch{
-			timestamp: line.Timestamp,
-			source:    line.Source,
-			lineNum:   line.LineNum,
-		})
-		e.stats.LinesMatched++
+	var key string
+	if e.groupField > 0 {
+		matches := e.pattern.FindStringSubmatch(line.Raw)
+		if matches == nil || e.groupField > len(matches)-1 {
+			return nil
+		}
+		key = matches[e.groupField]
+	} else if !e.pattern.MatchString(line.Raw) {
+		return nil
+	}
+
+	m := periodicMatch{
+		timestamp: line.Timestamp,
+		source:    line.Source,
+		lineNum:   line.LineNum,
 	}
+	e.stats.LinesMatched++
+
+	if e.first == nil {
+		e.first = &periodicMatch{}
+		*e.first = m
+	}
+
+	g, ok := e.groups[key]
+	if !ok {
+		g = &periodicGroup{}
+		e.groups[key] = g
+	}
+
+	if e.schedule != nil {
+		e.matchSlot(key, g, m)
+	} else if ok {
+		e.checkGap(key, g.last, m)
+	}
+	g.last = m
 
 	return nil
 }
 
-// Finalize completes analysis and returns detected issues.
+// checkGap records an issue if the gap between consecutive occurrences
+// exceeds max_gap.
+func (e *PeriodicEngine) checkGap(key string, prev, curr periodicMatch) {
+	gap := e.calendar.Elapsed(prev.timestamp, curr.timestamp)
+	if gap <= e.maxGap {
+		return
+	}
+
+	e.issues = append(e.issues, Issue{
+		Type: IssueTypeGapExceeded,
+		Description: fmt.Sprintf("Gap of %s between occurrences (max allowed: %s)",
+			gap.Round(time.Second), e.maxGap),
+		Context: IssueContext{
+			CorrelationID: key,
+			StartTime:     prev.timestamp,
+			EndTime:       curr.timestamp,
+			Source:        prev.source,
+			LineNum:       prev.lineNum,
+			ActualGap:     gap,
+			ExpectedGap:   e.maxGap,
+		},
+	})
+}
+
+// matchSlot matches an occurrence against the group's next scheduled slot.
+// The occurrence meets the slot if it is within tolerance of it; slots whose
+// tolerance ended before the occurrence were missed, and an occurrence too
+// early for the next slot is unexpected.
+func (e *PeriodicEngine) matchSlot(key string, g *periodicGroup, m periodicMatch) {
+	if g.firstSlot.IsZero() {
+		g.slot = e.schedule.Next(m.timestamp.Add(-e.tolerance).Add(-time.Nanosecond).In(e.location))
+		g.firstSlot = g.slot
+	}
+
+	for !g.slot.IsZero() && m.timestamp.After(g.slot.Add(e.tolerance)) {
+		e.issues = append(e.issues, e.missedSlot(key, g.slot))
+		g.slot = e.schedule.Next(g.slot)
+	}
+
+	if !g.slot.IsZero() && !m.timestamp.Before(g.slot.Add(-e.tolerance)) {
+		g.slot = e.schedule.Next(g.slot)
+		return
+	}
+
+	e.issues = append(e.issues, Issue{
+		Type: IssueTypeUnexpectedRun,
+		Description: fmt.Sprintf("Occurrence at %s is not within %s of any scheduled time (%s)",
+			m.timestamp.In(e.location).Format(scheduleTimeFormat), e.tolerance, e.schedule),
+		Context: IssueContext{
+			CorrelationID: key,
+			StartTime:     m.timestamp,
+			Source:        m.source,
+			LineNum:       m.lineNum,
+			Tolerance:     e.tolerance,
+		},
+	})
+}
+
+// missedSlot returns the issue for a scheduled slot with no occurrence.
+func (e *PeriodicEngine) missedSlot(key string, slot time.Time) Issue {
+	return Issue{
+		Type: IssueTypeMissedSlot,
+		Description: fmt.Sprintf("No occurrence within %s of scheduled time %s",
+			e.tolerance, slot.Format(scheduleTimeFormat)),
+		Context: IssueContext{
+			CorrelationID: key,
+			StartTime:     slot.Add(-e.tolerance),
+			EndTime:       slot.Add(e.tolerance),
+			ScheduledTime: slot,
+			Tolerance:     e.tolerance,
+		},
+	}
+}
+
+// groupKeys returns the group_by values seen, sorted.
+func (e *PeriodicEngine) groupKeys() []string {
+	keys := make([]string, 0, len(e.groups))
+	for key := range e.groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SetWindow sets the analysis window. Silence before the first match and
+// after the last match is measured against its start and end.
+func (e *PeriodicEngine) SetWindow(window TimeRange) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.window = window
+}
+
+// evalWindow returns the analysis window, falling back to the span of
+// lines this engine has processed when no window was set.
+func (e *PeriodicEngine) evalWindow() TimeRange {
+	window := e.window
+	if window.Start.IsZero() {
+		window.Start = e.seen.Start
+	}
+	if window.End.IsZero() {
+		window.End = e.seen.End
+	}
+	return window
+}
+
+// Finalize completes analysis and returns detected issues. Issues found
+// while processing are reported once and then cleared.
 func (e *PeriodicEngine) Finalize(ctx context.Context) (*RuleResult, error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -95,49 +279,184 @@ func (e *PeriodicEngine) Finalize(ctx context.Context) (*RuleResult, error) {
 		RuleName:    e.name,
 		RuleType:    RuleTypePeriodic,
 		Description: e.description,
-		Issues:      make([]Issue, 0),
+		Issues:      make([]Issue, 0, len(e.issues)),
 		Stats:       e.stats,
 	}
 
-	// Check for gaps between consecutive matches
-	for i := 1; i < len(e.matches); i++ {
-		prev := e.matches[i-1]
-		curr := e.matches[i]
-		gap := curr.timestamp.Sub(prev.timestamp)
+	window := e.evalWindow()
+	hasWindow := !window.Start.IsZero() && !window.End.IsZero()
+	keys := e.groupKeys()
 
-		if gap > e.maxGap {
-			issue := Issue{
-				Type: IssueTypeGapExceeded,
-				Description: fmt.Sprintf("Gap of %s between occurrences (max allowed: %s)",
-					gap.Round(time.Second), e.maxGap),
+	if hasWindow {
+		result.Issues = append(result.Issues, e.absentMembers(window)...)
+	}
+
+	if e.schedule != nil {
+		// Slots just before the window start belong to an earlier window
+		for _, issue := range e.issues {
+			switch {
+			case issue.Type == IssueTypeMissedSlot && issue.Context.ScheduledTime.Before(window.Start):
+			case issue.Type == IssueTypeUnexpectedRun && issue.Context.StartTime.Before(window.Start):
+			default:
+				result.Issues = append(result.Issues, issue)
+			}
+		}
+		e.issues = nil
+
+		if hasWindow {
+			// A rule without group_by expects its slots even if nothing matched
+			if e.groupField == 0 && len(keys) == 0 {
+				keys = []string{""}
+			}
+			e.stats.Pending = 0
+			for _, key := range keys {
+				result.Issues = append(result.Issues, e.remainingSlots(window, key, e.groups[key])...)
+			}
+			result.Stats = e.stats
+		}
+		e.appendMinOccurrences(result)
+		return result, nil
+	}
+
+	// Check for silence from the window start to the first match of any
+	// group, or across the whole window if nothing matched
+	if hasWindow {
+		first := periodicMatch{timestamp: window.End}
+		if e.first != nil {
+			first = *e.first
+		}
+		if gap := e.calendar.Elapsed(window.Start, first.timestamp); gap > e.maxGap {
+			desc := fmt.Sprintf("No occurrence for %s after the start of the window (max allowed: %s)",
+				gap.Round(time.Second), e.maxGap)
+			if e.first == nil {
+				desc = fmt.Sprintf("No occurrence in the whole %s window (max allowed: %s)",
+					gap.Round(time.Second), e.maxGap)
+			}
+			result.Issues = append(result.Issues, Issue{
+				Type:        IssueTypeLeadingSilence,
+				Description: desc,
 				Context: IssueContext{
-					StartTime:   prev.timestamp,
-					EndTime:     curr.timestamp,
-					Source:      prev.source,
-					LineNum:     prev.lineNum,
+					StartTime:   window.Start,
+					EndTime:     first.timestamp,
+					Source:      first.source,
+					LineNum:     first.lineNum,
 					ActualGap:   gap,
 					ExpectedGap: e.maxGap,
 				},
+			})
+		}
+	}
+
+	// Gaps between consecutive matches were found while processing
+	result.Issues = append(result.Issues, e.issues...)
+	e.issues = nil
+
+	// Check for silence from the last match to the window end, which for
+	// grouped rules catches a group that went quiet
+	if hasWindow {
+		for _, key := range keys {
+			last := e.groups[key].last
+			if gap := e.calendar.Elapsed(last.timestamp, window.End); gap > e.maxGap {
+				result.Issues = append(result.Issues, Issue{
+					Type: IssueTypeTrailingSilence,
+					Description: fmt.Sprintf("No occurrence for %s before the end of the window (max allowed: %s)",
+						gap.Round(time.Second), e.maxGap),
+					Context: IssueContext{
+						CorrelationID: key,
+						StartTime:     last.timestamp,
+						EndTime:       window.End,
+						Source:        last.source,
+						LineNum:       last.lineNum,
+						ActualGap:     gap,
+						ExpectedGap:   e.maxGap,
+					},
+				})
 			}
-			result.Issues = append(result.Issues, issue)
 		}
 	}
 
-	// Check min_occurrences if specified
-	if e.minOccurrences > 0 && len(e.matches) < e.minOccurrences {
+	e.appendMinOccurrences(result)
+
+	return result, nil
+}
+
+// absentMembers reports roster members with no match in the window.
+func (e *PeriodicEngine) absentMembers(window TimeRange) []Issue {
+	var issues []Issue
+	for _, member := range e.roster {
+		if g, ok := e.groups[member]; ok && !g.last.timestamp.Before(window.Start) {
+			continue
+		}
+		issues = append(issues, Issue{
+			Type:        IssueTypeAbsentMember,
+			Description: fmt.Sprintf("Expected member %q produced no occurrence in the window", member),
+			Context: IssueContext{
+				CorrelationID: member,
+				StartTime:     window.Start,
+				EndTime:       window.End,
+			},
+		})
+	}
+	return issues
+}
+
+// appendMinOccurrences adds an issue if min_occurrences is set and not met.
+func (e *PeriodicEngine) appendMinOccurrences(result *RuleResult) {
+	count := e.stats.LinesMatched
+	if e.minOccurrences > 0 && count < e.minOccurrences {
 		issue := Issue{
 			Type: IssueTypeBelowMinOccurrences,
 			Description: fmt.Sprintf("Only %d occurrences found (minimum required: %d)",
-				len(e.matches), e.minOccurrences),
+				count, e.minOccurrences),
 			Context: IssueContext{
-				Occurrences: len(e.matches),
+				Occurrences: count,
 				MinRequired: e.minOccurrences,
 			},
 		}
 		result.Issues = append(result.Issues, issue)
 	}
+}
 
-	return result, nil
+// remainingSlots judges the scheduled slots in the window that no occurrence
+// was matched against: those before the group's first occurrence and those
+// after its last. Slots whose tolerance extends past the window end are
+// counted as pending rather than missed. g is nil for a group that never
+// matched.
+func (e *PeriodicEngine) remainingSlots(window TimeRange, key string, g *periodicGroup) []Issue {
+	var issues []Issue
+
+	judge := func(slot time.Time) {
+		switch {
+		case slot.Before(window.Start):
+			// Belongs to an earlier window
+		case slot.Add(e.tolerance).After(window.End):
+			e.stats.Pending++
+		default:
+			issues = append(issues, e.missedSlot(key, slot))
+		}
+	}
+	inWindow := func(slot time.Time) bool {
+		return !slot.IsZero() && !slot.Add(-e.tolerance).After(window.End)
+	}
+
+	var firstSlot time.Time
+	if g != nil {
+		firstSlot = g.firstSlot
+	}
+
+	slot := e.schedule.Next(window.Start.Add(-e.tolerance).Add(-time.Nanosecond).In(e.location))
+	for ; inWindow(slot) && (firstSlot.IsZero() || slot.Before(firstSlot)); slot = e.schedule.Next(slot) {
+		judge(slot)
+	}
+
+	if firstSlot.IsZero() {
+		return issues
+	}
+	for slot := g.slot; inWindow(slot); slot = e.schedule.Next(slot) {
+		judge(slot)
+	}
+
+	return issues
 }
 
 // Reset clears internal state for reuse.
@@ -145,7 +464,11 @@ func (e *PeriodicEngine) Reset() {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	e.matches = make([]periodicMatch, 0)
+	e.groups = make(map[string]*periodicGroup)
+	e.first = nil
+	e.issues = nil
+	e.seen = TimeRange{}
+	e.window = TimeRange{}
 	e.stats = RuleStats{}
 }
 
@@ -155,6 +478,9 @@ type PeriodicState struct {
 	LastMatch *time.Time `json:"last_match,omitempty"`
 	Source    string     `json:"source,omitempty"`
 	LineNum   int        `json:"line_num,omitempty"`
+
+	// Groups holds the last match of each group for rules with group_by.
+	Groups map[string]PeriodicState `json:"groups,omitempty"`
 }
 
 // ExportState returns the last match time for serialization.
@@ -162,31 +488,72 @@ func (e *PeriodicEngine) ExportState() *PeriodicState {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	if len(e.matches) == 0 {
+	if len(e.groups) == 0 {
 		return nil
 	}
 
-	last := e.matches[len(e.matches)-1]
-	return &PeriodicState{
-		LastMatch: &last.timestamp,
-		Source:    last.source,
-		LineNum:   last.lineNum,
+	lastState := func(g *periodicGroup) PeriodicState {
+		last := g.last
+		return PeriodicState{
+			LastMatch: &last.timestamp,
+			Source:    last.source,
+			LineNum:   last.lineNum,
+		}
 	}
+
+	if e.groupField == 0 {
+		g, ok := e.groups[""]
+		if !ok {
+			return nil
+		}
+		state := lastState(g)
+		return &state
+	}
+
+	state := &PeriodicState{Groups: make(map[string]PeriodicState, len(e.groups))}
+	for key, g := range e.groups {
+		state.Groups[key] = lastState(g)
+	}
+	return state
 }
 
 // ImportState restores the last match time from serialized state.
 func (e *PeriodicEngine) ImportState(state *PeriodicState) {
-	if state == nil || state.LastMatch == nil {
+	if state == nil {
 		return
 	}
 
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	// Insert the last match as the first match so gap detection works
-	e.matches = append([]periodicMatch{{
+	// State saved before the rule gained or lost group_by doesn't apply
+	if e.groupField == 0 {
+		if state.LastMatch != nil {
+			e.importLast("", state)
+		}
+		return
+	}
+	for key, group := range state.Groups {
+		if group.LastMatch != nil {
+			e.importLast(key, &group)
+		}
+	}
+}
+
+// importLast restores a group's last match so gap detection works across
+// analysis windows. Groups that already have a match keep it.
+func (e *PeriodicEngine) importLast(key string, state *PeriodicState) {
+	if _, ok := e.groups[key]; ok {
+		return
+	}
+
+	m := periodicMatch{
 		timestamp: *state.LastMatch,
 		source:    state.Source,
 		lineNum:   state.LineNum,
-	}}, e.matches...)
+	}
+	e.groups[key] = &periodicGroup{last: m}
+	if e.first == nil || m.timestamp.Before(e.first.timestamp) {
+		e.first = &m
+	}
 }
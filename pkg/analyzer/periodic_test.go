@@ -213,6 +213,166 @@ func TestPeriodicEngine_SingleMatch(t *testing.T) {
 	}
 }
 
+func TestPeriodicEngine_TrailingSilence(t *testing.T) {
+	engine := createPeriodicEngine(t, 5*time.Minute, 0)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 19, 17, 50, 0, 0, time.UTC)
+
+	// Job logs regularly until 18:00, then stops while other lines continue
+	lines := []*parser.ParsedLine{
+		{Raw: "HEARTBEAT", Timestamp: baseTime, Source: "job.log", LineNum: 1},
+		{Raw: "HEARTBEAT", Timestamp: baseTime.Add(5 * time.Minute), Source: "job.log", LineNum: 2},
+		{Raw: "HEARTBEAT", Timestamp: baseTime.Add(10 * time.Minute), Source: "job.log", LineNum: 3},
+		{Raw: "Something else", Timestamp: baseTime.Add(2 * time.Hour), Source: "job.log", LineNum: 4},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %d, want 1", len(result.Issues))
+	}
+	issue := result.Issues[0]
+	if issue.Type != IssueTypeTrailingSilence {
+		t.Errorf("Type = %v, want %v", issue.Type, IssueTypeTrailingSilence)
+	}
+	if issue.Context.ActualGap != 110*time.Minute {
+		t.Errorf("ActualGap = %v, want 1h50m", issue.Context.ActualGap)
+	}
+	if issue.Context.LineNum != 3 {
+		t.Errorf("LineNum = %d, want 3 (last occurrence)", issue.Context.LineNum)
+	}
+}
+
+func TestPeriodicEngine_TrailingSilence_Window(t *testing.T) {
+	engine := createPeriodicEngine(t, 5*time.Minute, 0)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	if err := engine.Process(ctx, &parser.ParsedLine{
+		Raw:       "HEARTBEAT",
+		Timestamp: baseTime,
+	}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	// Within max_gap of the last line, but not of the evaluation clock
+	engine.SetWindow(TimeRange{Start: baseTime, End: baseTime.Add(time.Hour)})
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 || result.Issues[0].Type != IssueTypeTrailingSilence {
+		t.Fatalf("Issues = %v, want one trailing_silence", result.Issues)
+	}
+}
+
+func TestPeriodicEngine_LeadingSilence(t *testing.T) {
+	engine := createPeriodicEngine(t, 5*time.Minute, 0)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	lines := []*parser.ParsedLine{
+		{Raw: "Something else", Timestamp: baseTime},
+		{Raw: "HEARTBEAT", Timestamp: baseTime.Add(20 * time.Minute)},
+		{Raw: "HEARTBEAT", Timestamp: baseTime.Add(25 * time.Minute)},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %d, want 1", len(result.Issues))
+	}
+	issue := result.Issues[0]
+	if issue.Type != IssueTypeLeadingSilence {
+		t.Errorf("Type = %v, want %v", issue.Type, IssueTypeLeadingSilence)
+	}
+	if issue.Context.ActualGap != 20*time.Minute {
+		t.Errorf("ActualGap = %v, want 20m", issue.Context.ActualGap)
+	}
+}
+
+func TestPeriodicEngine_SilentWholeWindow(t *testing.T) {
+	engine := createPeriodicEngine(t, 5*time.Minute, 0)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		if err := engine.Process(ctx, &parser.ParsedLine{
+			Raw:       "Something else",
+			Timestamp: baseTime.Add(time.Duration(i) * 30 * time.Minute),
+		}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	// A single issue spanning the whole window, not a leading and a trailing one
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %d, want 1", len(result.Issues))
+	}
+	issue := result.Issues[0]
+	if issue.Type != IssueTypeLeadingSilence {
+		t.Errorf("Type = %v, want %v", issue.Type, IssueTypeLeadingSilence)
+	}
+	if issue.Context.ActualGap != time.Hour {
+		t.Errorf("ActualGap = %v, want 1h", issue.Context.ActualGap)
+	}
+}
+
+func TestPeriodicEngine_ImportedStateNoLeadingSilence(t *testing.T) {
+	engine := createPeriodicEngine(t, 5*time.Minute, 0)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	last := baseTime.Add(-2 * time.Minute)
+	engine.ImportState(&PeriodicState{LastMatch: &last})
+
+	for _, offset := range []time.Duration{0, 3 * time.Minute} {
+		if err := engine.Process(ctx, &parser.ParsedLine{
+			Raw:       "HEARTBEAT",
+			Timestamp: baseTime.Add(offset),
+		}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 0 {
+		t.Errorf("Issues = %v, want none", result.Issues)
+	}
+}
+
 func TestPeriodicEngine_Reset(t *testing.T) {
 	engine := createPeriodicEngine(t, 1*time.Minute, 0)
 
@@ -247,7 +407,438 @@ func TestPeriodicEngine_Reset(t *testing.T) {
 	}
 }
 
-func createPeriodicEngine(t *testing.T, maxGap time.Duration, minOccurrences int) *PeriodicEngine {
+func TestPeriodicEngine_Schedule(t *testing.T) {
+	engine := createScheduleEngine(t, "0 2 * * *", 15*time.Minute, "")
+
+	ctx := context.Background()
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	lines := []*parser.ParsedLine{
+		{Raw: "Window start", Timestamp: day},
+		{Raw: "SYNC done", Timestamp: day.Add(2*time.Hour + 5*time.Minute)},                 // 15th, on time
+		{Raw: "SYNC done", Timestamp: day.Add(24*time.Hour + 14*time.Hour)},                 // 16th, unexpected
+		{Raw: "SYNC done", Timestamp: day.Add(48*time.Hour + 2*time.Hour - 10*time.Minute)}, // 17th, early but within tolerance
+		{Raw: "SYNC done", Timestamp: day.Add(48*time.Hour + 2*time.Hour + 1*time.Minute)},  // 17th, ran twice
+		{Raw: "Window end", Timestamp: day.Add(72*time.Hour + 2*time.Hour + 5*time.Minute)}, // 18th slot not yet judged
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	var missed []time.Time
+	unexpected := 0
+	for _, issue := range result.Issues {
+		switch issue.Type {
+		case IssueTypeMissedSlot:
+			missed = append(missed, issue.Context.ScheduledTime)
+		case IssueTypeUnexpectedRun:
+			unexpected++
+		default:
+			t.Errorf("unexpected issue type %v", issue.Type)
+		}
+	}
+
+	if len(missed) != 1 || !missed[0].Equal(day.Add(26*time.Hour)) {
+		t.Errorf("missed slots = %v, want [2024-01-16 02:00]", missed)
+	}
+	if unexpected != 2 {
+		t.Errorf("unexpected runs = %d, want 2", unexpected)
+	}
+	if result.Stats.Pending != 1 {
+		t.Errorf("Pending = %d, want 1", result.Stats.Pending)
+	}
+}
+
+func TestPeriodicEngine_Schedule_Timezone(t *testing.T) {
+	engine := createScheduleEngine(t, "0 2 * * *", 15*time.Minute, "America/New_York")
+
+	ctx := context.Background()
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	// 02:00 in New York is 07:00 UTC in January
+	lines := []*parser.ParsedLine{
+		{Raw: "Window start", Timestamp: day},
+		{Raw: "SYNC done", Timestamp: day.Add(7 * time.Hour)},
+		{Raw: "Window end", Timestamp: day.Add(12 * time.Hour)},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 0 {
+		t.Errorf("Issues = %v, want none", result.Issues)
+	}
+}
+
+func TestPeriodicEngine_Schedule_NoOccurrences(t *testing.T) {
+	engine := createScheduleEngine(t, "0 2 * * *", 15*time.Minute, "")
+
+	ctx := context.Background()
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	for _, line := range []*parser.ParsedLine{
+		{Raw: "Window start", Timestamp: day},
+		{Raw: "Window end", Timestamp: day.Add(48 * time.Hour)},
+	} {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 2 {
+		t.Fatalf("Issues = %v, want 2 missed slots", result.Issues)
+	}
+	for _, issue := range result.Issues {
+		if issue.Type != IssueTypeMissedSlot {
+			t.Errorf("Type = %v, want %v", issue.Type, IssueTypeMissedSlot)
+		}
+	}
+}
+
+func TestPeriodicEngine_IssuesReportedOnce(t *testing.T) {
+	engine := createPeriodicEngine(t, 5*time.Minute, 0)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	for _, offset := range []time.Duration{0, 10 * time.Minute} {
+		if err := engine.Process(ctx, &parser.ParsedLine{
+			Raw:       "HEARTBEAT",
+			Timestamp: baseTime.Add(offset),
+		}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %d, want 1", len(result.Issues))
+	}
+
+	// The gap stays reported; a continuing stream only reports new ones
+	if err := engine.Process(ctx, &parser.ParsedLine{
+		Raw:       "HEARTBEAT",
+		Timestamp: baseTime.Add(12 * time.Minute),
+	}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	result, err = engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	if len(result.Issues) != 0 {
+		t.Errorf("Issues = %v, want none", result.Issues)
+	}
+}
+
+func TestPeriodicEngine_ProcessConstantMemory(t *testing.T) {
+	tests := []struct {
+		name   string
+		engine *PeriodicEngine
+		raw    string
+	}{
+		{"max_gap", createPeriodicEngine(t, 5*time.Minute, 0), "HEARTBEAT"},
+		{"schedule", createScheduleEngine(t, "* * * * *", 30*time.Second, ""), "SYNC done"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			line := &parser.ParsedLine{
+				Raw:       tt.raw,
+				Timestamp: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+			}
+
+			allocs := testing.AllocsPerRun(10000, func() {
+				line.Timestamp = line.Timestamp.Add(time.Minute)
+				if err := tt.engine.Process(ctx, line); err != nil {
+					t.Fatalf("Process() error = %v", err)
+				}
+			})
+			if allocs != 0 {
+				t.Errorf("Process() allocates %v per line, want 0", allocs)
+			}
+		})
+	}
+}
+
+func BenchmarkPeriodicEngine_Process(b *testing.B) {
+	benchmarks := []struct {
+		name   string
+		engine *PeriodicEngine
+		raw    func(i int) string
+	}{
+		{"max_gap", createPeriodicEngine(b, 5*time.Minute, 0), func(int) string { return "HEARTBEAT" }},
+		{"group_by", createGroupedEngine(b, "host"), func(i int) string { return hostLines[i%len(hostLines)] }},
+		{"schedule", createScheduleEngine(b, "* * * * *", 30*time.Second, ""), func(int) string { return "SYNC done" }},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			ctx := context.Background()
+			line := &parser.ParsedLine{Timestamp: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)}
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				line.Raw = bm.raw(i)
+				line.Timestamp = line.Timestamp.Add(time.Minute)
+				if err := bm.engine.Process(ctx, line); err != nil {
+					b.Fatalf("Process() error = %v", err)
+				}
+			}
+			b.StopTimer()
+
+			// Memory held depends on the number of groups, not lines
+			if got := len(bm.engine.groups); got > len(hostLines) {
+				b.Errorf("groups = %d after %d lines", got, b.N)
+			}
+		})
+	}
+}
+
+var hostLines = []string{"HEARTBEAT host=web-1", "HEARTBEAT host=web-2", "HEARTBEAT host=web-3"}
+
+func createScheduleEngine(t testing.TB, schedule string, tolerance time.Duration, timezone string) *PeriodicEngine {
+	t.Helper()
+
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:      "test",
+			Type:      "periodic",
+			Pattern:   `SYNC done`,
+			Schedule:  schedule,
+			Tolerance: tolerance,
+			Timezone:  timezone,
+		}},
+	}
+
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	engine, err := NewPeriodicEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewPeriodicEngine() error = %v", err)
+	}
+
+	return engine
+}
+
+func TestPeriodicEngine_GroupBy(t *testing.T) {
+	engine := createGroupedEngine(t, "host")
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// web-1 beats every minute; web-2 has a gap; web-3 stops after 10:02
+	var lines []*parser.ParsedLine
+	for i := 0; i <= 10; i++ {
+		ts := baseTime.Add(time.Duration(i) * time.Minute)
+		lines = append(lines, &parser.ParsedLine{Raw: "HEARTBEAT host=web-1", Timestamp: ts})
+		if i < 3 || i > 8 {
+			lines = append(lines, &parser.ParsedLine{Raw: "HEARTBEAT host=web-2", Timestamp: ts})
+		}
+		if i <= 2 {
+			lines = append(lines, &parser.ParsedLine{Raw: "HEARTBEAT host=web-3", Timestamp: ts})
+		}
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	got := make(map[string]IssueType)
+	for _, issue := range result.Issues {
+		got[issue.Context.CorrelationID] = issue.Type
+	}
+	want := map[string]IssueType{
+		"web-2": IssueTypeGapExceeded,
+		"web-3": IssueTypeTrailingSilence,
+	}
+	if len(result.Issues) != len(want) {
+		t.Fatalf("Issues = %v, want %d", result.Issues, len(want))
+	}
+	for id, typ := range want {
+		if got[id] != typ {
+			t.Errorf("group %s: issue = %q, want %q", id, got[id], typ)
+		}
+	}
+}
+
+func TestPeriodicEngine_GroupBy_State(t *testing.T) {
+	engine := createGroupedEngine(t, "1")
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	for i, raw := range []string{"HEARTBEAT host=web-1", "HEARTBEAT host=web-2"} {
+		if err := engine.Process(ctx, &parser.ParsedLine{
+			Raw:       raw,
+			Timestamp: baseTime.Add(time.Duration(i) * time.Minute),
+		}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	state := engine.ExportState()
+	if state == nil || len(state.Groups) != 2 {
+		t.Fatalf("ExportState() = %+v, want 2 groups", state)
+	}
+
+	// web-2 resumes on time in the next run, web-1 after a long gap
+	restored := createGroupedEngine(t, "host")
+	restored.ImportState(state)
+	for _, raw := range []string{"HEARTBEAT host=web-1", "HEARTBEAT host=web-2"} {
+		if err := restored.Process(ctx, &parser.ParsedLine{
+			Raw:       raw,
+			Timestamp: baseTime.Add(4 * time.Minute),
+		}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := restored.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 || result.Issues[0].Context.CorrelationID != "web-1" {
+		t.Errorf("Issues = %v, want one gap for web-1", result.Issues)
+	}
+}
+
+func TestPeriodicEngine_GroupBy_StateRuleChanged(t *testing.T) {
+	last := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// Saved while the rule used group_by, restored after it dropped it
+	ungrouped := createPeriodicEngine(t, 5*time.Minute, 0)
+	ungrouped.ImportState(&PeriodicState{Groups: map[string]PeriodicState{"web-1": {LastMatch: &last}}})
+	if state := ungrouped.ExportState(); state != nil {
+		t.Errorf("ungrouped ExportState() = %+v, want nil", state)
+	}
+
+	// And the other way around
+	grouped := createGroupedEngine(t, "host")
+	grouped.ImportState(&PeriodicState{LastMatch: &last})
+	if state := grouped.ExportState(); state != nil {
+		t.Errorf("grouped ExportState() = %+v, want nil", state)
+	}
+}
+
+func TestPeriodicEngine_Roster(t *testing.T) {
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:    "test",
+			Type:    "periodic",
+			Pattern: `HEARTBEAT host=(?P<host>\S+)`,
+			MaxGap:  3 * time.Minute,
+			GroupBy: "host",
+			Roster:  &config.RosterConfig{Members: []string{"web-1", "web-2", "web-3"}},
+		}},
+	}
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	engine, err := NewPeriodicEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewPeriodicEngine() error = %v", err)
+	}
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// web-3 was up in an earlier run, but not in this one
+	earlier := baseTime.Add(-time.Hour)
+	engine.ImportState(&PeriodicState{Groups: map[string]PeriodicState{"web-3": {LastMatch: &earlier}}})
+
+	for i := 0; i < 3; i++ {
+		ts := baseTime.Add(time.Duration(i) * time.Minute)
+		for _, host := range []string{"web-1", "web-2"} {
+			if err := engine.Process(ctx, &parser.ParsedLine{
+				Raw:       "HEARTBEAT host=" + host,
+				Timestamp: ts,
+			}); err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	var absent []string
+	for _, issue := range result.Issues {
+		if issue.Type == IssueTypeAbsentMember {
+			absent = append(absent, issue.Context.CorrelationID)
+		}
+	}
+	if len(absent) != 1 || absent[0] != "web-3" {
+		t.Errorf("absent members = %v, want [web-3]", absent)
+	}
+}
+
+func createGroupedEngine(t testing.TB, groupBy string) *PeriodicEngine {
+	t.Helper()
+
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:    "test",
+			Type:    "periodic",
+			Pattern: `HEARTBEAT host=(?P<host>\S+)`,
+			MaxGap:  3 * time.Minute,
+			GroupBy: groupBy,
+		}},
+	}
+
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	engine, err := NewPeriodicEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewPeriodicEngine() error = %v", err)
+	}
+
+	return engine
+}
+
+func createPeriodicEngine(t testing.TB, maxGap time.Duration, minOccurrences int) *PeriodicEngine {
 	t.Helper()
 
 	cfg := &config.Config{
@@ -273,3 +864,57 @@ func createPeriodicEngine(t *testing.T, maxGap time.Duration, minOccurrences int
 
 	return engine
 }
+
+func TestPeriodicEngine_Calendar(t *testing.T) {
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Calendars:       businessHours(),
+		Rules: []config.RuleConfig{{
+			Name:     "test",
+			Type:     "periodic",
+			Pattern:  `BATCH`,
+			MaxGap:   time.Hour,
+			Calendar: "business-hours",
+		}},
+	}
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	engine, err := NewPeriodicEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewPeriodicEngine() error = %v", err)
+	}
+
+	ctx := context.Background()
+	friday := time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)
+	for i, ts := range []time.Time{
+		friday.Add(16*time.Hour + 30*time.Minute),
+		friday.Add(3*24*time.Hour + 9*time.Hour + 20*time.Minute),  // Monday: 50m of business hours later
+		friday.Add(3*24*time.Hour + 11*time.Hour + 20*time.Minute), // 2h later
+	} {
+		if err := engine.Process(ctx, &parser.ParsedLine{Raw: "BATCH", Timestamp: ts, Source: "test.log", LineNum: i + 1}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %d, want 1 (weekend gap is outside business hours)", len(result.Issues))
+	}
+	if result.Issues[0].Context.ActualGap != 2*time.Hour {
+		t.Errorf("ActualGap = %v, want 2h", result.Issues[0].Context.ActualGap)
+	}
+}
+
+// businessHours returns a calendar active 09:00-17:00 UTC on weekdays.
+func businessHours() []config.CalendarConfig {
+	return []config.CalendarConfig{{
+		Name:  "business-hours",
+		Hours: []config.HoursConfig{{Days: []string{"mon-fri"}, Start: "09:00", End: "17:00"}},
+	}}
+}
@@ -0,0 +1,219 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/ccollicutt/negalog/pkg/calendar"
+	"github.com/ccollicutt/negalog/pkg/config"
+	"github.com/ccollicutt/negalog/pkg/parser"
+)
+
+// RateEngine implements RuleEngine for minimum rate detection.
+// It counts pattern matches in tumbling or sliding windows and reports
+// windows with fewer matches than required.
+type RateEngine struct {
+	name        string
+	description string
+	size        time.Duration // window length
+	slide       time.Duration // distance between window starts
+	minCount    int
+	calendar    *calendar.Calendar // only windows entirely in active time are judged; nil means always active
+
+	pattern *regexp.Regexp
+
+	// State
+	mu      sync.Mutex
+	buckets map[time.Time]int // match counts per slide-aligned bucket, keyed in UTC
+	seen    TimeRange         // span of line timestamps processed
+	window  TimeRange         // analysis window set by the analyzer
+	stats   RuleStats
+}
+
+// NewRateEngine creates a new rate detection engine from a rule config.
+func NewRateEngine(rule *config.RuleConfig) (*RateEngine, error) {
+	if rule.RuleTypeEnum() != config.RuleTypeRate {
+		return nil, fmt.Errorf("rule %q is not a rate rule", rule.Name)
+	}
+
+	pattern := rule.CompiledPattern()
+	if pattern == nil {
+		return nil, fmt.Errorf("rule %q has uncompiled pattern", rule.Name)
+	}
+
+	return &RateEngine{
+		name:        rule.Name,
+		description: rule.Description,
+		size:        rule.Window,
+		slide:       rule.Slide,
+		minCount:    rule.MinCount,
+		calendar:    rule.ActiveCalendar(),
+		pattern:     pattern,
+		buckets:     make(map[time.Time]int),
+	}, nil
+}
+
+// Name returns the rule name.
+func (e *RateEngine) Name() string {
+	return e.name
+}
+
+// Type returns the rule type.
+func (e *RateEngine) Type() RuleType {
+	return RuleTypeRate
+}
+
+// Process handles a single log line.
+func (e *RateEngine) Process(ctx context.Context, line *parser.ParsedLine) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stats.LinesProcessed++
+	if e.stats.LinesProcessed == 1 || line.Timestamp.Before(e.seen.Start) {
+		e.seen.Start = line.Timestamp
+	}
+	if e.stats.LinesProcessed == 1 || line.Timestamp.After(e.seen.End) {
+		e.seen.End = line.Timestamp
+	}
+
+	if !e.pattern.MatchString(line.Raw) {
+		return nil
+	}
+
+	e.buckets[e.bucket(line.Timestamp)]++
+	e.stats.LinesMatched++
+
+	return nil
+}
+
+// bucket returns the start of the slide-aligned bucket containing t.
+func (e *RateEngine) bucket(t time.Time) time.Time {
+	return t.Truncate(e.slide).UTC()
+}
+
+// SetWindow sets the analysis window. Only counting windows that lie
+// entirely within it are judged.
+func (e *RateEngine) SetWindow(window TimeRange) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.window = window
+}
+
+// evalWindow returns the analysis window, falling back to the span of
+// lines this engine has processed when no window was set.
+func (e *RateEngine) evalWindow() TimeRange {
+	window := e.window
+	if window.Start.IsZero() {
+		window.Start = e.seen.Start
+	}
+	if window.End.IsZero() {
+		window.End = e.seen.End
+	}
+	return window
+}
+
+// Finalize completes analysis and returns detected issues.
+func (e *RateEngine) Finalize(ctx context.Context) (*RuleResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stats.EndTime = time.Now()
+
+	result := &RuleResult{
+		RuleName:    e.name,
+		RuleType:    RuleTypeRate,
+		Description: e.description,
+		Issues:      make([]Issue, 0),
+		Stats:       e.stats,
+	}
+
+	window := e.evalWindow()
+	if window.Start.IsZero() || window.End.IsZero() {
+		return result, nil
+	}
+
+	// Windows start on slide boundaries; a window cut off by either end of
+	// the analysis window would undercount, so it is not judged
+	start := e.bucket(window.Start)
+	if start.Before(window.Start) {
+		start = start.Add(e.slide)
+	}
+
+	// Counts are kept as a running sum over the buckets of each window
+	count := 0
+	for b := start; b.Before(start.Add(e.size)); b = b.Add(e.slide) {
+		count += e.buckets[b]
+	}
+
+	// Overlapping low windows are one low period, reported once with the
+	// fewest occurrences of any of its windows
+	var lowStart, lowEnd time.Time
+	fewest := 0
+	for ws := start; !ws.Add(e.size).After(window.End); ws = ws.Add(e.slide) {
+		if ws.After(start) {
+			count += e.buckets[ws.Add(e.size-e.slide)] - e.buckets[ws.Add(-e.slide)]
+		}
+		we := ws.Add(e.size)
+
+		// Windows reaching outside active time, such as overnight, are not judged
+		if e.calendar.Elapsed(ws, we) < e.size || count >= e.minCount {
+			if !lowEnd.IsZero() {
+				result.Issues = append(result.Issues, e.lowIssue(lowStart, lowEnd, fewest))
+				lowEnd = time.Time{}
+			}
+			continue
+		}
+
+		if !lowEnd.IsZero() && ws.Before(lowEnd) {
+			lowEnd = we
+			fewest = min(fewest, count)
+			continue
+		}
+		if !lowEnd.IsZero() {
+			result.Issues = append(result.Issues, e.lowIssue(lowStart, lowEnd, fewest))
+		}
+		lowStart, lowEnd, fewest = ws, we, count
+	}
+	if !lowEnd.IsZero() {
+		result.Issues = append(result.Issues, e.lowIssue(lowStart, lowEnd, fewest))
+	}
+
+	return result, nil
+}
+
+// lowIssue returns the issue for a low period from start to end, made of one
+// or more overlapping windows with at most fewest occurrences each.
+func (e *RateEngine) lowIssue(start, end time.Time, fewest int) Issue {
+	desc := fmt.Sprintf("Only %d occurrences between %s and %s (minimum required: %d per %s)",
+		fewest, start.Format(time.RFC3339), end.Format(time.RFC3339), e.minCount, e.size)
+	if end.Sub(start) > e.size {
+		desc = fmt.Sprintf("As few as %d occurrences per %s between %s and %s (minimum required: %d per %s)",
+			fewest, e.size, start.Format(time.RFC3339), end.Format(time.RFC3339), e.minCount, e.size)
+	}
+
+	return Issue{
+		Type:        IssueTypeBelowMinRate,
+		Description: desc,
+		Context: IssueContext{
+			StartTime:   start,
+			EndTime:     end,
+			Occurrences: fewest,
+			MinRequired: e.minCount,
+		},
+	}
+}
+
+// Reset clears internal state for reuse.
+func (e *RateEngine) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.buckets = make(map[time.Time]int)
+	e.seen = TimeRange{}
+	e.window = TimeRange{}
+	e.stats = RuleStats{}
+}
@@ -0,0 +1,274 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ccollicutt/negalog/pkg/config"
+	"github.com/ccollicutt/negalog/pkg/parser"
+)
+
+func TestNewRateEngine_WrongType(t *testing.T) {
+	rule := &config.RuleConfig{
+		Name: "test",
+		Type: "periodic",
+	}
+	if _, err := NewRateEngine(rule); err == nil {
+		t.Error("NewRateEngine() expected error for wrong type")
+	}
+}
+
+func TestRateEngine_Tumbling(t *testing.T) {
+	engine := createRateEngine(t, 5*time.Minute, 0, 3)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// 10:00-10:05 has 3 orders, 10:05-10:10 only 1, 10:10-10:15 has 4
+	offsets := []time.Duration{
+		0, time.Minute, 2 * time.Minute,
+		7 * time.Minute,
+		10 * time.Minute, 11 * time.Minute, 12 * time.Minute, 13 * time.Minute,
+	}
+	for _, offset := range offsets {
+		processRate(t, engine, "ORDER_ACCEPTED", baseTime.Add(offset))
+	}
+	processRate(t, engine, "Window end", baseTime.Add(15*time.Minute))
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %v, want 1", result.Issues)
+	}
+	issue := result.Issues[0]
+	if issue.Type != IssueTypeBelowMinRate {
+		t.Errorf("Type = %v, want %v", issue.Type, IssueTypeBelowMinRate)
+	}
+	if !issue.Context.StartTime.Equal(baseTime.Add(5*time.Minute)) ||
+		!issue.Context.EndTime.Equal(baseTime.Add(10*time.Minute)) {
+		t.Errorf("window = %v - %v, want 10:05 - 10:10", issue.Context.StartTime, issue.Context.EndTime)
+	}
+	if issue.Context.Occurrences != 1 || issue.Context.MinRequired != 3 {
+		t.Errorf("Occurrences = %d, MinRequired = %d, want 1, 3",
+			issue.Context.Occurrences, issue.Context.MinRequired)
+	}
+	if result.Stats.LinesMatched != len(offsets) {
+		t.Errorf("LinesMatched = %d, want %d", result.Stats.LinesMatched, len(offsets))
+	}
+}
+
+func TestRateEngine_Sliding(t *testing.T) {
+	engine := createRateEngine(t, 2*time.Minute, time.Minute, 2)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// One order per minute, except none in 10:02-10:03
+	for _, offset := range []time.Duration{0, time.Minute, 3 * time.Minute, 4 * time.Minute} {
+		processRate(t, engine, "ORDER_ACCEPTED", baseTime.Add(offset))
+	}
+	processRate(t, engine, "Window end", baseTime.Add(5*time.Minute))
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	// Windows starting 10:01 and 10:02 each hold one order, and overlap, so
+	// they are one low period
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %v, want 1", result.Issues)
+	}
+	issue := result.Issues[0]
+	if !issue.Context.StartTime.Equal(baseTime.Add(time.Minute)) || !issue.Context.EndTime.Equal(baseTime.Add(4*time.Minute)) {
+		t.Errorf("low period = %v - %v, want 10:01 - 10:04", issue.Context.StartTime, issue.Context.EndTime)
+	}
+	if issue.Context.Occurrences != 1 {
+		t.Errorf("Occurrences = %d, want 1", issue.Context.Occurrences)
+	}
+}
+
+func TestRateEngine_SlidingOutage(t *testing.T) {
+	engine := createRateEngine(t, 5*time.Minute, time.Minute, 3)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// One order per minute from 10:00 to 12:00, with an outage 10:30-11:30
+	for m := 0; m < 120; m++ {
+		if m < 30 || m >= 90 {
+			processRate(t, engine, "ORDER_ACCEPTED", baseTime.Add(time.Duration(m)*time.Minute))
+		}
+	}
+	processRate(t, engine, "Window end", baseTime.Add(2*time.Hour))
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %d, want one for the outage", len(result.Issues))
+	}
+	// The first window below 3 starts at 10:28 and the last ends at 11:32
+	issue := result.Issues[0]
+	if !issue.Context.StartTime.Equal(baseTime.Add(28*time.Minute)) ||
+		!issue.Context.EndTime.Equal(baseTime.Add(92*time.Minute)) {
+		t.Errorf("low period = %v - %v, want 10:28 - 11:32", issue.Context.StartTime, issue.Context.EndTime)
+	}
+	if issue.Context.Occurrences != 0 {
+		t.Errorf("Occurrences = %d, want 0", issue.Context.Occurrences)
+	}
+}
+
+func TestRateEngine_PartialWindowsNotJudged(t *testing.T) {
+	engine := createRateEngine(t, 5*time.Minute, 0, 3)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// The window runs 10:03-10:12, so only 10:05-10:10 is complete
+	processRate(t, engine, "Window start", baseTime.Add(3*time.Minute))
+	for i := 0; i < 3; i++ {
+		processRate(t, engine, "ORDER_ACCEPTED", baseTime.Add(6*time.Minute))
+	}
+	processRate(t, engine, "Window end", baseTime.Add(12*time.Minute))
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 0 {
+		t.Errorf("Issues = %v, want none", result.Issues)
+	}
+}
+
+func TestRateEngine_Window(t *testing.T) {
+	engine := createRateEngine(t, 5*time.Minute, 0, 1)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	processRate(t, engine, "ORDER_ACCEPTED", baseTime.Add(time.Minute))
+	engine.SetWindow(TimeRange{Start: baseTime, End: baseTime.Add(15 * time.Minute)})
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	// Nothing logged after 10:01, so the last two windows are empty
+	if len(result.Issues) != 2 {
+		t.Fatalf("Issues = %v, want 2", result.Issues)
+	}
+	for _, issue := range result.Issues {
+		if issue.Context.Occurrences != 0 {
+			t.Errorf("Occurrences = %d, want 0", issue.Context.Occurrences)
+		}
+	}
+}
+
+func TestRateEngine_Reset(t *testing.T) {
+	engine := createRateEngine(t, 5*time.Minute, 0, 1)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	processRate(t, engine, "Window start", baseTime)
+	processRate(t, engine, "Window end", baseTime.Add(10*time.Minute))
+	engine.Reset()
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 0 {
+		t.Errorf("Issues = %d, want 0 after reset", len(result.Issues))
+	}
+}
+
+func processRate(t *testing.T, engine *RateEngine, raw string, ts time.Time) {
+	t.Helper()
+
+	if err := engine.Process(context.Background(), &parser.ParsedLine{Raw: raw, Timestamp: ts}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+}
+
+func TestRateEngine_Calendar(t *testing.T) {
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Calendars:       businessHours(),
+		Rules: []config.RuleConfig{{
+			Name:     "test",
+			Type:     "rate",
+			Pattern:  `ORDER_ACCEPTED`,
+			Window:   time.Hour,
+			MinCount: 1,
+			Calendar: "business-hours",
+		}},
+	}
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	engine, err := NewRateEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewRateEngine() error = %v", err)
+	}
+
+	// Orders every business hour on Monday except 13:00-14:00, none overnight
+	monday := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	for hour := 9; hour < 17; hour++ {
+		if hour != 13 {
+			processRate(t, engine, "ORDER_ACCEPTED", monday.Add(time.Duration(hour)*time.Hour+30*time.Minute))
+		}
+	}
+
+	engine.SetWindow(TimeRange{Start: monday, End: monday.Add(24 * time.Hour)})
+	result, err := engine.Finalize(context.Background())
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %v, want 1 (overnight windows are not judged)", result.Issues)
+	}
+	if start := result.Issues[0].Context.StartTime; !start.Equal(monday.Add(13 * time.Hour)) {
+		t.Errorf("StartTime = %v, want 13:00", start)
+	}
+}
+
+func createRateEngine(t *testing.T, window, slide time.Duration, minCount int) *RateEngine {
+	t.Helper()
+
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:     "test",
+			Type:     "rate",
+			Pattern:  `ORDER_ACCEPTED`,
+			Window:   window,
+			Slide:    slide,
+			MinCount: minCount,
+		}},
+	}
+
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	engine, err := NewRateEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewRateEngine() error = %v", err)
+	}
+
+	return engine
+}
@@ -0,0 +1,252 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ccollicutt/negalog/pkg/config"
+	"github.com/ccollicutt/negalog/pkg/parser"
+)
+
+// reconciliationBucket identifies one counting window of one group.
+type reconciliationBucket struct {
+	group string    // empty if the rule is not grouped
+	start time.Time // window start, in UTC
+}
+
+// ReconciliationEngine implements RuleEngine for count reconciliation.
+// It counts two patterns in tumbling windows, optionally per group, and
+// reports windows where the counts differ by more than the rule allows.
+// Individual events are not correlated, only their totals.
+type ReconciliationEngine struct {
+	name          string
+	description   string
+	size          time.Duration // window length
+	maxDifference int
+	maxRatio      float64 // zero if not set
+
+	left       *regexp.Regexp
+	right      *regexp.Regexp
+	leftGroup  int // capture group index in left, 0 if not grouped
+	rightGroup int
+
+	// State
+	mu      sync.Mutex
+	buckets map[reconciliationBucket][2]int // left and right counts
+	seen    TimeRange                       // span of line timestamps processed
+	window  TimeRange                       // analysis window set by the analyzer
+	stats   RuleStats
+}
+
+// NewReconciliationEngine creates a new reconciliation engine from a rule config.
+func NewReconciliationEngine(rule *config.RuleConfig) (*ReconciliationEngine, error) {
+	if rule.RuleTypeEnum() != config.RuleTypeReconciliation {
+		return nil, fmt.Errorf("rule %q is not a reconciliation rule", rule.Name)
+	}
+
+	left := rule.CompiledLeftPattern()
+	right := rule.CompiledRightPattern()
+	if left == nil || right == nil {
+		return nil, fmt.Errorf("rule %q has uncompiled patterns", rule.Name)
+	}
+
+	return &ReconciliationEngine{
+		name:          rule.Name,
+		description:   rule.Description,
+		size:          rule.Window,
+		maxDifference: rule.MaxDifference,
+		maxRatio:      rule.MaxRatio,
+		left:          left,
+		right:         right,
+		leftGroup:     rule.GroupIndex(),
+		rightGroup:    rule.RightGroupIndex(),
+		buckets:       make(map[reconciliationBucket][2]int),
+	}, nil
+}
+
+// Name returns the rule name.
+func (e *ReconciliationEngine) Name() string {
+	return e.name
+}
+
+// Type returns the rule type.
+func (e *ReconciliationEngine) Type() RuleType {
+	return RuleTypeReconciliation
+}
+
+// Process handles a single log line. A line matching both patterns counts
+// on both sides.
+func (e *ReconciliationEngine) Process(ctx context.Context, line *parser.ParsedLine) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stats.LinesProcessed++
+	if e.stats.LinesProcessed == 1 || line.Timestamp.Before(e.seen.Start) {
+		e.seen.Start = line.Timestamp
+	}
+	if e.stats.LinesProcessed == 1 || line.Timestamp.After(e.seen.End) {
+		e.seen.End = line.Timestamp
+	}
+
+	matched := false
+	for side, m := range []struct {
+		pattern *regexp.Regexp
+		group   int
+	}{{e.left, e.leftGroup}, {e.right, e.rightGroup}} {
+		matches := m.pattern.FindStringSubmatch(line.Raw)
+		if matches == nil {
+			continue
+		}
+
+		b := reconciliationBucket{start: line.Timestamp.Truncate(e.size).UTC()}
+		if m.group > 0 {
+			b.group = matches[m.group]
+		}
+		counts := e.buckets[b]
+		counts[side]++
+		e.buckets[b] = counts
+		matched = true
+	}
+	if matched {
+		e.stats.LinesMatched++
+	}
+
+	return nil
+}
+
+// SetWindow sets the analysis window. Only counting windows that lie
+// entirely within it are judged.
+func (e *ReconciliationEngine) SetWindow(window TimeRange) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.window = window
+}
+
+// evalWindow returns the analysis window, falling back to the span of
+// lines this engine has processed when no window was set.
+func (e *ReconciliationEngine) evalWindow() TimeRange {
+	window := e.window
+	if window.Start.IsZero() {
+		window.Start = e.seen.Start
+	}
+	if window.End.IsZero() {
+		window.End = e.seen.End
+	}
+	return window
+}
+
+// exceeds returns true if two counts differ by more than every threshold set.
+func (e *ReconciliationEngine) exceeds(left, right int) bool {
+	diff := left - right
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff == 0 {
+		return false
+	}
+	if e.maxDifference == 0 && e.maxRatio == 0 {
+		return true
+	}
+	if e.maxDifference > 0 && diff <= e.maxDifference {
+		return false
+	}
+	if e.maxRatio > 0 && ratio(left, right) <= e.maxRatio {
+		return false
+	}
+	return true
+}
+
+// ratio returns the larger count divided by the smaller, +Inf if the smaller is zero.
+func ratio(a, b int) float64 {
+	lo, hi := min(a, b), max(a, b)
+	if lo == 0 {
+		return math.Inf(1)
+	}
+	return float64(hi) / float64(lo)
+}
+
+// Finalize completes analysis and returns detected issues.
+func (e *ReconciliationEngine) Finalize(ctx context.Context) (*RuleResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stats.EndTime = time.Now()
+
+	result := &RuleResult{
+		RuleName:    e.name,
+		RuleType:    RuleTypeReconciliation,
+		Description: e.description,
+		Issues:      make([]Issue, 0),
+		Stats:       e.stats,
+	}
+
+	window := e.evalWindow()
+	if window.Start.IsZero() || window.End.IsZero() {
+		return result, nil
+	}
+
+	// A group counts as zero on both sides in windows it was absent from
+	groupSet := make(map[string]bool)
+	for b := range e.buckets {
+		groupSet[b.group] = true
+	}
+	groups := make([]string, 0, len(groupSet))
+	for g := range groupSet {
+		groups = append(groups, g)
+	}
+	sort.Strings(groups)
+
+	// Windows cut off by either end of the analysis window are not judged
+	start := window.Start.Truncate(e.size).UTC()
+	if start.Before(window.Start) {
+		start = start.Add(e.size)
+	}
+
+	for ws := start; !ws.Add(e.size).After(window.End); ws = ws.Add(e.size) {
+		for _, group := range groups {
+			counts := e.buckets[reconciliationBucket{group: group, start: ws}]
+			if !e.exceeds(counts[0], counts[1]) {
+				continue
+			}
+
+			we := ws.Add(e.size)
+			desc := fmt.Sprintf("Counts differ between %s and %s: left %d, right %d",
+				ws.Format(time.RFC3339), we.Format(time.RFC3339), counts[0], counts[1])
+			if group != "" {
+				desc = fmt.Sprintf("Counts for %s differ between %s and %s: left %d, right %d",
+					group, ws.Format(time.RFC3339), we.Format(time.RFC3339), counts[0], counts[1])
+			}
+
+			result.Issues = append(result.Issues, Issue{
+				Type:        IssueTypeCountMismatch,
+				Description: desc,
+				Context: IssueContext{
+					CorrelationID: group,
+					StartTime:     ws,
+					EndTime:       we,
+					LeftCount:     counts[0],
+					RightCount:    counts[1],
+				},
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// Reset clears internal state for reuse.
+func (e *ReconciliationEngine) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.buckets = make(map[reconciliationBucket][2]int)
+	e.seen = TimeRange{}
+	e.window = TimeRange{}
+	e.stats = RuleStats{}
+}
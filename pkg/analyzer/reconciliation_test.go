@@ -0,0 +1,197 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ccollicutt/negalog/pkg/config"
+	"github.com/ccollicutt/negalog/pkg/parser"
+)
+
+func TestNewReconciliationEngine_WrongType(t *testing.T) {
+	rule := &config.RuleConfig{
+		Name: "test",
+		Type: "rate",
+	}
+	if _, err := NewReconciliationEngine(rule); err == nil {
+		t.Error("NewReconciliationEngine() expected error for wrong type")
+	}
+}
+
+func TestReconciliationEngine_Thresholds(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxDifference int
+		maxRatio      float64
+		want          []int // indexes of reported windows
+	}{
+		{name: "exact", want: []int{1, 2, 3}},
+		{name: "difference", maxDifference: 2, want: []int{2, 3}},
+		{name: "ratio", maxRatio: 1.25, want: []int{1, 3}},
+		{name: "both", maxDifference: 2, maxRatio: 1.25, want: []int{3}},
+	}
+
+	// Produced/consumed per hour: equal, 2 short of 5, 3 short of 30, none consumed
+	counts := [][2]int{{10, 10}, {5, 3}, {30, 27}, {4, 0}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := createReconciliationEngine(t, "", tt.maxDifference, tt.maxRatio)
+
+			ctx := context.Background()
+			baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+			for i, c := range counts {
+				hour := baseTime.Add(time.Duration(i) * time.Hour)
+				for j := 0; j < c[0]; j++ {
+					processReconciliation(t, engine, "PRODUCED queue=orders", hour.Add(time.Minute))
+				}
+				for j := 0; j < c[1]; j++ {
+					processReconciliation(t, engine, "CONSUMED queue=orders", hour.Add(2*time.Minute))
+				}
+			}
+			processReconciliation(t, engine, "Window end", baseTime.Add(4*time.Hour))
+
+			result, err := engine.Finalize(ctx)
+			if err != nil {
+				t.Fatalf("Finalize() error = %v", err)
+			}
+
+			if len(result.Issues) != len(tt.want) {
+				t.Fatalf("Issues = %v, want windows %v", result.Issues, tt.want)
+			}
+			for i, idx := range tt.want {
+				issue := result.Issues[i]
+				if issue.Type != IssueTypeCountMismatch {
+					t.Errorf("Type = %v, want %v", issue.Type, IssueTypeCountMismatch)
+				}
+				if !issue.Context.StartTime.Equal(baseTime.Add(time.Duration(idx) * time.Hour)) {
+					t.Errorf("StartTime = %v, want window %d", issue.Context.StartTime, idx)
+				}
+				if issue.Context.LeftCount != counts[idx][0] || issue.Context.RightCount != counts[idx][1] {
+					t.Errorf("counts = %d/%d, want %v",
+						issue.Context.LeftCount, issue.Context.RightCount, counts[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestReconciliationEngine_GroupBy(t *testing.T) {
+	engine := createReconciliationEngine(t, "queue", 0, 0)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	lines := []string{
+		"PRODUCED queue=orders",
+		"PRODUCED queue=orders",
+		"PRODUCED queue=emails",
+		"CONSUMED queue=orders",
+		"CONSUMED queue=emails",
+		// Consumed with nothing produced
+		"CONSUMED queue=audit",
+	}
+	for i, raw := range lines {
+		processReconciliation(t, engine, raw, baseTime.Add(time.Duration(i)*time.Minute))
+	}
+	engine.SetWindow(TimeRange{Start: baseTime, End: baseTime.Add(time.Hour)})
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 2 {
+		t.Fatalf("Issues = %v, want 2", result.Issues)
+	}
+	got := result.Issues
+	if got[0].Context.CorrelationID != "audit" || got[0].Context.LeftCount != 0 || got[0].Context.RightCount != 1 {
+		t.Errorf("Issues[0] = %+v, want audit 0/1", got[0].Context)
+	}
+	if got[1].Context.CorrelationID != "orders" || got[1].Context.LeftCount != 2 || got[1].Context.RightCount != 1 {
+		t.Errorf("Issues[1] = %+v, want orders 2/1", got[1].Context)
+	}
+	if result.Stats.LinesMatched != len(lines) {
+		t.Errorf("LinesMatched = %d, want %d", result.Stats.LinesMatched, len(lines))
+	}
+}
+
+func TestReconciliationEngine_PartialWindowsNotJudged(t *testing.T) {
+	engine := createReconciliationEngine(t, "", 0, 0)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// Both hours are cut off by the window
+	processReconciliation(t, engine, "PRODUCED queue=orders", baseTime.Add(30*time.Minute))
+	processReconciliation(t, engine, "PRODUCED queue=orders", baseTime.Add(90*time.Minute))
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 0 {
+		t.Errorf("Issues = %v, want none", result.Issues)
+	}
+}
+
+func TestReconciliationEngine_Reset(t *testing.T) {
+	engine := createReconciliationEngine(t, "", 0, 0)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	processReconciliation(t, engine, "PRODUCED queue=orders", baseTime)
+	processReconciliation(t, engine, "Window end", baseTime.Add(time.Hour))
+	engine.Reset()
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 0 {
+		t.Errorf("Issues = %d, want 0 after reset", len(result.Issues))
+	}
+}
+
+func processReconciliation(t *testing.T, engine *ReconciliationEngine, raw string, ts time.Time) {
+	t.Helper()
+
+	if err := engine.Process(context.Background(), &parser.ParsedLine{Raw: raw, Timestamp: ts}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+}
+
+func createReconciliationEngine(t *testing.T, groupBy string, maxDifference int, maxRatio float64) *ReconciliationEngine {
+	t.Helper()
+
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:          "test",
+			Type:          "reconciliation",
+			LeftPattern:   `PRODUCED queue=(?P<queue>\w+)`,
+			RightPattern:  `CONSUMED queue=(?P<queue>\w+)`,
+			Window:        time.Hour,
+			GroupBy:       groupBy,
+			MaxDifference: maxDifference,
+			MaxRatio:      maxRatio,
+		}},
+	}
+
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	engine, err := NewReconciliationEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewReconciliationEngine() error = %v", err)
+	}
+
+	return engine
+}
@@ -4,35 +4,77 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/ccollicutt/negalog/pkg/calendar"
 	"github.com/ccollicutt/negalog/pkg/config"
 	"github.com/ccollicutt/negalog/pkg/parser"
 )
 
+// sequenceStep is a compiled step of a sequence rule.
+// Start/end rules are represented as two steps named "start" and "end".
+type sequenceStep struct {
+	name    string
+	pattern *regexp.Regexp
+	key     config.CorrelationKey
+	timeout time.Duration // max time since previous step, 0 means unbounded
+}
+
 // sequenceTracker tracks an open sequence awaiting completion.
 type sequenceTracker struct {
-	correlationID string
-	startTime     time.Time
-	source        string
-	lineNum       int
+	correlationID  string
+	startTime      time.Time
+	source         string
+	lineNum        int
+	stepTimes      []time.Time // when each step was reached, zero if not yet
+	attempts       int         // number of starts seen for this correlation ID
+	retriesFlagged bool        // excessive retries already reported
+}
+
+// lastStep returns the index of the last step reached without skipping any earlier step.
+func (t *sequenceTracker) lastStep() int {
+	last := 0
+	for i := 1; i < len(t.stepTimes) && !t.stepTimes[i].IsZero(); i++ {
+		last = i
+	}
+	return last
+}
+
+// complete returns true if every step has been reached.
+func (t *sequenceTracker) complete() bool {
+	for _, ts := range t.stepTimes {
+		if ts.IsZero() {
+			return false
+		}
+	}
+	return true
 }
 
 // SequenceEngine implements RuleEngine for sequence gap detection.
 // It tracks start events and looks for matching end events within a timeout.
+// Rules with a steps list are tracked through each intermediate step in order.
 type SequenceEngine struct {
-	name        string
-	description string
-	timeout     time.Duration
-	corrField   int // 1-based capture group index
+	name          string
+	description   string
+	timeout       time.Duration
+	calendar      *calendar.Calendar // timeouts pause outside active time; nil means always active
+	reportOrphans bool
+	distinct      bool // ends must come from a different source than their start
+	duplicates    config.DuplicateStartPolicy
+	maxRetries    int
 
-	startPattern *regexp.Regexp
-	endPattern   *regexp.Regexp
+	steps []sequenceStep
 
 	// State
 	mu            sync.Mutex
-	openSequences map[string]*sequenceTracker // key: correlation ID
+	openSequences map[string][]*sequenceTracker // key: correlation ID, oldest first
+	issues        []Issue                       // issues detected while processing
+	orphans       []Issue                       // end events without an open start
+	seen          TimeRange                     // span of line timestamps processed
+	window        TimeRange                     // analysis window set by the analyzer
+	latency       *latencySketch                // durations of completed sequences
 	stats         RuleStats
 }
 
@@ -42,21 +84,46 @@ func NewSequenceEngine(rule *config.RuleConfig) (*SequenceEngine, error) {
 		return nil, fmt.Errorf("rule %q is not a sequence rule", rule.Name)
 	}
 
-	startPattern := rule.CompiledStartPattern()
-	endPattern := rule.CompiledEndPattern()
+	var steps []sequenceStep
+	if len(rule.Steps) > 0 {
+		for i := range rule.Steps {
+			step := &rule.Steps[i]
+			if step.CompiledPattern() == nil {
+				return nil, fmt.Errorf("rule %q has uncompiled patterns", rule.Name)
+			}
+			steps = append(steps, sequenceStep{
+				name:    step.Name,
+				pattern: step.CompiledPattern(),
+				key:     step.CorrelationKey(),
+				timeout: step.Timeout,
+			})
+		}
+	} else {
+		startPattern := rule.CompiledStartPattern()
+		endPattern := rule.CompiledEndPattern()
+
+		if startPattern == nil || endPattern == nil {
+			return nil, fmt.Errorf("rule %q has uncompiled patterns", rule.Name)
+		}
 
-	if startPattern == nil || endPattern == nil {
-		return nil, fmt.Errorf("rule %q has uncompiled patterns", rule.Name)
+		steps = []sequenceStep{
+			{name: "start", pattern: startPattern, key: rule.StartCorrelationKey()},
+			{name: "end", pattern: endPattern, key: rule.EndCorrelationKey()},
+		}
 	}
 
 	return &SequenceEngine{
 		name:          rule.Name,
 		description:   rule.Description,
 		timeout:       rule.Timeout,
-		corrField:     rule.CorrelationField,
-		startPattern:  startPattern,
-		endPattern:    endPattern,
-		openSequences: make(map[string]*sequenceTracker),
+		calendar:      rule.ActiveCalendar(),
+		reportOrphans: rule.ReportOrphanEnds,
+		distinct:      rule.DistinctSources,
+		duplicates:    rule.DuplicateStarts,
+		maxRetries:    rule.MaxRetries,
+		steps:         steps,
+		openSequences: make(map[string][]*sequenceTracker),
+		latency:       newLatencySketch(),
 	}, nil
 }
 
@@ -76,90 +143,405 @@ func (e *SequenceEngine) Process(ctx context.Context, line *parser.ParsedLine) e
 	defer e.mu.Unlock()
 
 	e.stats.LinesProcessed++
+	if e.stats.LinesProcessed == 1 || line.Timestamp.Before(e.seen.Start) {
+		e.seen.Start = line.Timestamp
+	}
+	if e.stats.LinesProcessed == 1 || line.Timestamp.After(e.seen.End) {
+		e.seen.End = line.Timestamp
+	}
 
-	// Check for start pattern match
-	if matches := e.startPattern.FindStringSubmatch(line.Raw); matches != nil {
-		if e.corrField <= len(matches)-1 {
-			corrID := matches[e.corrField]
-			e.openSequences[corrID] = &sequenceTracker{
-				correlationID: corrID,
-				startTime:     line.Timestamp,
-				source:        line.Source,
-				lineNum:       line.LineNum,
-			}
-			e.stats.LinesMatched++
+	for i, step := range e.steps {
+		matches := step.pattern.FindStringSubmatch(line.Raw)
+		if matches == nil {
+			continue
+		}
+		corrID, ok := step.key.Extract(matches)
+		if !ok {
+			continue
+		}
+
+		if i == 0 {
+			e.startSequence(corrID, line)
+			continue
+		}
+
+		if tracker := e.awaitingStep(corrID, i, line.Source); tracker != nil {
+			e.advanceSequence(tracker, i, line)
+		} else if e.reportOrphans && i == len(e.steps)-1 && len(e.openSequences[corrID]) == 0 {
+			e.recordOrphan(corrID, line)
 		}
 	}
 
-	// Check for end pattern match
-	if matches := e.endPattern.FindStringSubmatch(line.Raw); matches != nil {
-		if e.corrField <= len(matches)-1 {
-			corrID := matches[e.corrField]
-			if tracker, exists := e.openSequences[corrID]; exists {
-				// Check if within timeout
-				elapsed := line.Timestamp.Sub(tracker.startTime)
-				if elapsed <= e.timeout {
-					// Successfully completed within timeout
-					delete(e.openSequences, corrID)
-				}
-				// If elapsed > timeout, leave it open so it's reported as missing
+	return nil
+}
+
+// awaitingStep returns the oldest open sequence for corrID that has not reached step idx.
+// With distinct sources, an end from source cannot complete a sequence started there.
+func (e *SequenceEngine) awaitingStep(corrID string, idx int, source string) *sequenceTracker {
+	for _, tracker := range e.openSequences[corrID] {
+		if e.distinct && idx == len(e.steps)-1 && tracker.source == source {
+			continue
+		}
+		if tracker.stepTimes[idx].IsZero() {
+			return tracker
+		}
+	}
+	return nil
+}
+
+// startSequence opens a tracker for a first-step match, applying the
+// duplicate start policy when the correlation ID is already open.
+func (e *SequenceEngine) startSequence(corrID string, line *parser.ParsedLine) {
+	e.stats.LinesMatched++
+
+	tracker := &sequenceTracker{
+		correlationID: corrID,
+		startTime:     line.Timestamp,
+		source:        line.Source,
+		lineNum:       line.LineNum,
+		stepTimes:     make([]time.Time, len(e.steps)),
+		attempts:      1,
+	}
+	tracker.stepTimes[0] = line.Timestamp
+
+	open := e.openSequences[corrID]
+	if len(open) == 0 {
+		e.openSequences[corrID] = []*sequenceTracker{tracker}
+		return
+	}
+
+	existing := open[len(open)-1]
+	switch e.duplicates {
+	case config.DuplicateStartsKeepFirst:
+		existing.attempts++
+		tracker = existing
+	case config.DuplicateStartsReport:
+		existing.attempts++
+		e.issues = append(e.issues, Issue{
+			Type: IssueTypeDuplicateStart,
+			Description: fmt.Sprintf("Sequence started again while open since %s",
+				existing.startTime.Format(time.RFC3339)),
+			Context: IssueContext{
+				CorrelationID: corrID,
+				StartTime:     existing.startTime,
+				EndTime:       line.Timestamp,
+				Source:        line.Source,
+				LineNum:       line.LineNum,
+				Timeout:       e.timeout,
+			},
+		})
+		tracker = existing
+	case config.DuplicateStartsMultiset:
+		tracker.attempts = existing.attempts + 1
+		tracker.retriesFlagged = existing.retriesFlagged
+		e.openSequences[corrID] = append(open, tracker)
+	default:
+		tracker.attempts = existing.attempts + 1
+		tracker.retriesFlagged = existing.retriesFlagged
+		e.openSequences[corrID] = []*sequenceTracker{tracker}
+	}
+
+	e.checkRetries(tracker, line)
+}
+
+// checkRetries reports a sequence that has been restarted more than max_retries times.
+func (e *SequenceEngine) checkRetries(tracker *sequenceTracker, line *parser.ParsedLine) {
+	retries := tracker.attempts - 1
+	if e.maxRetries <= 0 || retries <= e.maxRetries || tracker.retriesFlagged {
+		return
+	}
+
+	tracker.retriesFlagged = true
+	e.issues = append(e.issues, Issue{
+		Type: IssueTypeExcessiveRetries,
+		Description: fmt.Sprintf("Sequence retried %d times before completing (max allowed: %d)",
+			retries, e.maxRetries),
+		Context: IssueContext{
+			CorrelationID: tracker.correlationID,
+			StartTime:     tracker.startTime,
+			EndTime:       line.Timestamp,
+			Source:        line.Source,
+			LineNum:       line.LineNum,
+			Timeout:       e.timeout,
+			Occurrences:   retries,
+			MaxAllowed:    e.maxRetries,
+		},
+	})
+}
+
+// recordOrphan records an end event whose correlation ID has no open start.
+func (e *SequenceEngine) recordOrphan(corrID string, line *parser.ParsedLine) {
+	e.stats.LinesMatched++
+	e.orphans = append(e.orphans, Issue{
+		Type:        IssueTypeOrphanEnd,
+		Description: "Sequence ended without a matching start",
+		Context: IssueContext{
+			CorrelationID: corrID,
+			EndTime:       line.Timestamp,
+			Source:        line.Source,
+			LineNum:       line.LineNum,
+			LastStep:      e.steps[len(e.steps)-1].name,
+			MissingStep:   e.steps[0].name,
+			Timeout:       e.timeout,
+		},
+	})
+}
+
+// advanceSequence records a later step for an open sequence.
+func (e *SequenceEngine) advanceSequence(tracker *sequenceTracker, idx int, line *parser.ParsedLine) {
+	if !tracker.stepTimes[idx].IsZero() {
+		// Repeated step, already counted
+		return
+	}
+
+	step := e.steps[idx]
+
+	// Report the first earlier step that has not been seen yet
+	for j := 1; j < idx; j++ {
+		if tracker.stepTimes[j].IsZero() {
+			e.issues = append(e.issues, Issue{
+				Type: IssueTypeStepOutOfOrder,
+				Description: fmt.Sprintf("Step %q seen before step %q",
+					step.name, e.steps[j].name),
+				Context: IssueContext{
+					CorrelationID: tracker.correlationID,
+					StartTime:     tracker.startTime,
+					EndTime:       line.Timestamp,
+					Source:        line.Source,
+					LineNum:       line.LineNum,
+					LastStep:      e.steps[tracker.lastStep()].name,
+					MissingStep:   e.steps[j].name,
+					Timeout:       e.timeout,
+				},
+			})
+			break
+		}
+	}
+
+	tracker.stepTimes[idx] = line.Timestamp
+	if tracker.complete() {
+		e.stats.Completed++
+		e.latency.Add(line.Timestamp.Sub(tracker.startTime))
+		if issue, late := e.lateIssue(tracker, line); late {
+			e.issues = append(e.issues, issue)
+		}
+		e.closeSequence(tracker)
+	}
+}
+
+// lateIssue checks a completed sequence against the overall and per-step
+// timeouts, returning a late_end issue if either was exceeded.
+func (e *SequenceEngine) lateIssue(tracker *sequenceTracker, end *parser.ParsedLine) (Issue, bool) {
+	duration := end.Timestamp.Sub(tracker.startTime)
+
+	var description string
+	if e.calendar.Elapsed(tracker.startTime, end.Timestamp) > e.timeout {
+		description = fmt.Sprintf("Sequence completed in %s, after timeout of %s", duration, e.timeout)
+	} else {
+		for i := 1; i < len(e.steps); i++ {
+			took := tracker.stepTimes[i].Sub(tracker.stepTimes[i-1])
+			if e.steps[i].timeout > 0 && e.calendar.Elapsed(tracker.stepTimes[i-1], tracker.stepTimes[i]) > e.steps[i].timeout {
+				description = fmt.Sprintf("Step %q reached %s after step %q, after step timeout of %s",
+					e.steps[i].name, took, e.steps[i-1].name, e.steps[i].timeout)
+				break
 			}
 		}
 	}
+	if description == "" {
+		return Issue{}, false
+	}
 
-	return nil
+	return Issue{
+		Type:        IssueTypeLateEnd,
+		Description: description,
+		Context: IssueContext{
+			CorrelationID: tracker.correlationID,
+			StartTime:     tracker.startTime,
+			EndTime:       end.Timestamp,
+			Source:        tracker.source,
+			LineNum:       tracker.lineNum,
+			EndSource:     end.Source,
+			EndLineNum:    end.LineNum,
+			Duration:      duration,
+			Timeout:       e.timeout,
+		},
+	}, true
+}
+
+// closeSequence removes a completed tracker from the open sequences.
+func (e *SequenceEngine) closeSequence(tracker *sequenceTracker) {
+	open := e.openSequences[tracker.correlationID]
+	for i, t := range open {
+		if t == tracker {
+			open = append(open[:i], open[i+1:]...)
+			break
+		}
+	}
+	if len(open) == 0 {
+		delete(e.openSequences, tracker.correlationID)
+	} else {
+		e.openSequences[tracker.correlationID] = open
+	}
+}
+
+// SetWindow sets the analysis window. Its end is the evaluation clock that
+// decides whether an open sequence has timed out or is still pending.
+func (e *SequenceEngine) SetWindow(window TimeRange) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.window = window
+}
+
+// evalWindow returns the analysis window, falling back to the span of
+// lines this engine has processed when no window was set.
+func (e *SequenceEngine) evalWindow() TimeRange {
+	window := e.window
+	if window.Start.IsZero() {
+		window.Start = e.seen.Start
+	}
+	if window.End.IsZero() {
+		window.End = e.seen.End
+	}
+	return window
+}
+
+// deadline returns when an open sequence times out: the rule timeout from its
+// start, or the next step's own timeout from the last step reached, if sooner.
+func (e *SequenceEngine) deadline(tracker *sequenceTracker) time.Time {
+	deadline := e.calendar.Add(tracker.startTime, e.timeout)
+
+	last := tracker.lastStep()
+	if next := last + 1; next < len(e.steps) && e.steps[next].timeout > 0 {
+		if d := e.calendar.Add(tracker.stepTimes[last], e.steps[next].timeout); d.Before(deadline) {
+			deadline = d
+		}
+	}
+
+	return deadline
 }
 
 // Finalize completes analysis and returns detected issues.
+// Open sequences are only reported once the evaluation clock is past their
+// deadline; younger ones are counted as pending.
 func (e *SequenceEngine) Finalize(ctx context.Context) (*RuleResult, error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
 	e.stats.EndTime = time.Now()
 
+	window := e.evalWindow()
+
 	result := &RuleResult{
 		RuleName:    e.name,
 		RuleType:    RuleTypeSequence,
 		Description: e.description,
-		Issues:      make([]Issue, 0, len(e.openSequences)),
-		Stats:       e.stats,
+		Issues:      make([]Issue, 0, len(e.issues)+len(e.orphans)+len(e.openSequences)),
 	}
 
-	// All remaining open sequences are missing their end events
-	for _, tracker := range e.openSequences {
-		issue := Issue{
-			Type: IssueTypeMissingEnd,
-			Description: fmt.Sprintf("Sequence started but not completed within %s",
-				e.timeout),
-			Context: IssueContext{
-				CorrelationID: tracker.correlationID,
-				StartTime:     tracker.startTime,
-				Source:        tracker.source,
-				LineNum:       tracker.lineNum,
-				Timeout:       e.timeout,
-			},
+	result.Issues = append(result.Issues, e.issues...)
+
+	// Orphans within one timeout of the window start may have lost their start
+	// to truncated input rather than to a real problem
+	for _, orphan := range e.orphans {
+		if orphan.Context.EndTime.Sub(window.Start) <= e.timeout {
+			orphan.Context.PossiblyTruncated = true
+			orphan.Description = fmt.Sprintf("Sequence ended without a matching start "+
+				"(possibly truncated input: within %s of the window start)", e.timeout)
 		}
-		result.Issues = append(result.Issues, issue)
+		result.Issues = append(result.Issues, orphan)
 	}
 
+	// Issues found while processing are reported once, even when state is kept
+	e.issues = nil
+	e.orphans = nil
+
+	// Open sequences past their deadline are missing a step
+	e.stats.Pending = 0
+	for _, tracker := range e.openTrackers() {
+		if !window.End.After(e.deadline(tracker)) {
+			e.stats.Pending++
+			continue
+		}
+		result.Issues = append(result.Issues, e.incompleteIssue(tracker))
+	}
+
+	e.stats.Latency = e.latency.Summary()
+	result.Stats = e.stats
+
 	return result, nil
 }
 
+// incompleteIssue builds the issue for a sequence that never reached all its steps.
+func (e *SequenceEngine) incompleteIssue(tracker *sequenceTracker) Issue {
+	last := tracker.lastStep()
+	missing := last + 1
+
+	issue := Issue{
+		Type: IssueTypeMissingEnd,
+		Description: fmt.Sprintf("Sequence started but not completed within %s",
+			e.timeout),
+		Context: IssueContext{
+			CorrelationID: tracker.correlationID,
+			StartTime:     tracker.startTime,
+			Source:        tracker.source,
+			LineNum:       tracker.lineNum,
+			LastStep:      e.steps[last].name,
+			MissingStep:   e.steps[missing].name,
+			Timeout:       e.timeout,
+		},
+	}
+
+	if missing < len(e.steps)-1 {
+		issue.Type = IssueTypeMissingStep
+		issue.Description = fmt.Sprintf("Sequence reached step %q but step %q was not seen within %s",
+			e.steps[last].name, e.steps[missing].name, e.timeout)
+	}
+
+	return issue
+}
+
 // Reset clears internal state for reuse.
 func (e *SequenceEngine) Reset() {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	e.openSequences = make(map[string]*sequenceTracker)
+	e.openSequences = make(map[string][]*sequenceTracker)
+	e.issues = nil
+	e.orphans = nil
+	e.seen = TimeRange{}
+	e.window = TimeRange{}
+	e.latency = newLatencySketch()
 	e.stats = RuleStats{}
 }
 
+// openTrackers returns all open trackers ordered by start time.
+func (e *SequenceEngine) openTrackers() []*sequenceTracker {
+	trackers := make([]*sequenceTracker, 0, len(e.openSequences))
+	for _, open := range e.openSequences {
+		trackers = append(trackers, open...)
+	}
+	sort.Slice(trackers, func(i, j int) bool {
+		return trackers[i].startTime.Before(trackers[j].startTime)
+	})
+	return trackers
+}
+
 // SequenceState holds serializable state for a pending sequence.
 type SequenceState struct {
 	CorrelationID string    `json:"correlation_id"`
 	StartTime     time.Time `json:"start_time"`
 	Source        string    `json:"source"`
 	LineNum       int       `json:"line_num"`
+
+	// Steps records when each step of a multi-step sequence was reached, by step name.
+	Steps map[string]time.Time `json:"steps,omitempty"`
+
+	// Attempts is the number of starts seen for the correlation ID, if more than one.
+	Attempts int `json:"attempts,omitempty"`
+
+	// Pending is true if the sequence had not yet timed out at the evaluation clock.
+	Pending bool `json:"pending,omitempty"`
 }
 
 // ExportState returns all pending sequences for serialization.
@@ -167,14 +549,29 @@ func (e *SequenceEngine) ExportState() []SequenceState {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	clock := e.evalWindow().End
+
 	states := make([]SequenceState, 0, len(e.openSequences))
-	for _, tracker := range e.openSequences {
-		states = append(states, SequenceState{
+	for _, tracker := range e.openTrackers() {
+		state := SequenceState{
 			CorrelationID: tracker.correlationID,
 			StartTime:     tracker.startTime,
 			Source:        tracker.source,
 			LineNum:       tracker.lineNum,
-		})
+			Pending:       !clock.After(e.deadline(tracker)),
+		}
+		if tracker.attempts > 1 {
+			state.Attempts = tracker.attempts
+		}
+		if len(e.steps) > 2 {
+			state.Steps = make(map[string]time.Time)
+			for i, ts := range tracker.stepTimes {
+				if !ts.IsZero() {
+					state.Steps[e.steps[i].name] = ts
+				}
+			}
+		}
+		states = append(states, state)
 	}
 	return states
 }
@@ -185,11 +582,20 @@ func (e *SequenceEngine) ImportState(states []SequenceState) {
 	defer e.mu.Unlock()
 
 	for _, s := range states {
-		e.openSequences[s.CorrelationID] = &sequenceTracker{
+		tracker := &sequenceTracker{
 			correlationID: s.CorrelationID,
 			startTime:     s.StartTime,
 			source:        s.Source,
 			lineNum:       s.LineNum,
+			stepTimes:     make([]time.Time, len(e.steps)),
+			attempts:      max(s.Attempts, 1),
+		}
+		tracker.stepTimes[0] = s.StartTime
+		for i, step := range e.steps {
+			if ts, ok := s.Steps[step.name]; ok {
+				tracker.stepTimes[i] = ts
+			}
 		}
+		e.openSequences[s.CorrelationID] = append(e.openSequences[s.CorrelationID], tracker)
 	}
 }
@@ -2,6 +2,7 @@ package analyzer
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -105,6 +106,9 @@ func TestSequenceEngine_MissingEnd(t *testing.T) {
 		t.Fatalf("Process() error = %v", err)
 	}
 
+	// Evaluate once the timeout has elapsed
+	engine.SetWindow(TimeRange{End: baseTime.Add(2 * time.Minute)})
+
 	result, err := engine.Finalize(ctx)
 	if err != nil {
 		t.Fatalf("Finalize() error = %v", err)
@@ -155,6 +159,9 @@ func TestSequenceEngine_MultipleSequences(t *testing.T) {
 		t.Fatalf("Process() error = %v", err)
 	}
 
+	// Evaluate once the timeout has elapsed
+	engine.SetWindow(TimeRange{End: baseTime.Add(2 * time.Minute)})
+
 	result, err := engine.Finalize(ctx)
 	if err != nil {
 		t.Fatalf("Finalize() error = %v", err)
@@ -193,9 +200,134 @@ func TestSequenceEngine_EndAfterTimeout(t *testing.T) {
 		t.Fatalf("Finalize() error = %v", err)
 	}
 
-	// Should still be missing (end came after timeout)
+	// Completed, but late
 	if len(result.Issues) != 1 {
-		t.Errorf("Issues = %d, want 1 (end after timeout)", len(result.Issues))
+		t.Fatalf("Issues = %d, want 1 (end after timeout)", len(result.Issues))
+	}
+	issue := result.Issues[0]
+	if issue.Type != IssueTypeLateEnd {
+		t.Errorf("Type = %v, want %v", issue.Type, IssueTypeLateEnd)
+	}
+	if issue.Context.Duration != 30*time.Second {
+		t.Errorf("Duration = %v, want 30s", issue.Context.Duration)
+	}
+}
+
+func TestSequenceEngine_Latency(t *testing.T) {
+	engine := createSequenceEngine(t, 60*time.Second)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// Three sequences taking 1s, 2s and 70s (late), plus one never completed
+	lines := []*parser.ParsedLine{
+		{Raw: "START id=a", Timestamp: baseTime},
+		{Raw: "START id=b", Timestamp: baseTime},
+		{Raw: "START id=c", Timestamp: baseTime},
+		{Raw: "START id=d", Timestamp: baseTime},
+		{Raw: "END id=a", Timestamp: baseTime.Add(1 * time.Second)},
+		{Raw: "END id=b", Timestamp: baseTime.Add(2 * time.Second)},
+		{Raw: "END id=c", Timestamp: baseTime.Add(70 * time.Second)},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if result.Stats.Completed != 3 {
+		t.Errorf("Completed = %d, want 3", result.Stats.Completed)
+	}
+	lat := result.Stats.Latency
+	if lat == nil {
+		t.Fatal("Latency should be set")
+	}
+	if lat.Min != 1*time.Second {
+		t.Errorf("Min = %v, want 1s", lat.Min)
+	}
+	if lat.Max != 70*time.Second {
+		t.Errorf("Max = %v, want 70s", lat.Max)
+	}
+	if lat.P50 < 1980*time.Millisecond || lat.P50 > 2020*time.Millisecond {
+		t.Errorf("P50 = %v, want about 2s", lat.P50)
+	}
+}
+
+func TestSequenceEngine_Latency_NoneCompleted(t *testing.T) {
+	engine := createSequenceEngine(t, 60*time.Second)
+
+	ctx := context.Background()
+	if err := engine.Process(ctx, &parser.ParsedLine{
+		Raw:       "START id=a",
+		Timestamp: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+	}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if result.Stats.Completed != 0 || result.Stats.Latency != nil {
+		t.Errorf("Completed = %d, Latency = %v, want 0 and nil",
+			result.Stats.Completed, result.Stats.Latency)
+	}
+}
+
+func TestSequenceEngine_LateEndSource(t *testing.T) {
+	engine := createSequenceEngine(t, 60*time.Second)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	lines := []*parser.ParsedLine{
+		{Raw: "START id=late", Timestamp: baseTime, Source: "a.log", LineNum: 1},
+		{Raw: "START id=never", Timestamp: baseTime, Source: "a.log", LineNum: 2},
+		{Raw: "END id=late", Timestamp: baseTime.Add(61 * time.Second), Source: "b.log", LineNum: 7},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+	engine.SetWindow(TimeRange{End: baseTime.Add(2 * time.Minute)})
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 2 {
+		t.Fatalf("Issues = %d, want 2", len(result.Issues))
+	}
+
+	byID := make(map[string]Issue)
+	for _, issue := range result.Issues {
+		byID[issue.Context.CorrelationID] = issue
+	}
+
+	late := byID["late"]
+	if late.Type != IssueTypeLateEnd {
+		t.Errorf("late: Type = %v, want %v", late.Type, IssueTypeLateEnd)
+	}
+	if late.Context.Duration != 61*time.Second {
+		t.Errorf("late: Duration = %v, want 61s", late.Context.Duration)
+	}
+	if late.Context.EndSource != "b.log" || late.Context.EndLineNum != 7 {
+		t.Errorf("late: end = %s:%d, want b.log:7", late.Context.EndSource, late.Context.EndLineNum)
+	}
+	if late.Context.Source != "a.log" || late.Context.LineNum != 1 {
+		t.Errorf("late: start = %s:%d, want a.log:1", late.Context.Source, late.Context.LineNum)
+	}
+
+	if never := byID["never"]; never.Type != IssueTypeMissingEnd {
+		t.Errorf("never: Type = %v, want %v", never.Type, IssueTypeMissingEnd)
 	}
 }
 
@@ -249,6 +381,616 @@ func TestSequenceEngine_Reset(t *testing.T) {
 	}
 }
 
+func TestSequenceEngine_Steps_Complete(t *testing.T) {
+	engine := createStepsEngine(t, 60*time.Second)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	lines := []string{
+		"ORDER_CREATED id=o1",
+		"PAYMENT_AUTHORIZED id=o1",
+		"INVENTORY_RESERVED id=o1",
+		"SHIPPED id=o1",
+	}
+	for i, raw := range lines {
+		if err := engine.Process(ctx, &parser.ParsedLine{
+			Raw:       raw,
+			Timestamp: baseTime.Add(time.Duration(i) * time.Second),
+		}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 0 {
+		t.Errorf("Issues = %d, want 0 (all steps reached)", len(result.Issues))
+	}
+}
+
+func TestSequenceEngine_Steps_MissingStep(t *testing.T) {
+	engine := createStepsEngine(t, 60*time.Second)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	lines := []string{
+		"ORDER_CREATED id=o1",
+		"PAYMENT_AUTHORIZED id=o1",
+		"ORDER_CREATED id=o2",
+		"PAYMENT_AUTHORIZED id=o2",
+		"INVENTORY_RESERVED id=o2",
+	}
+	for i, raw := range lines {
+		if err := engine.Process(ctx, &parser.ParsedLine{
+			Raw:       raw,
+			Timestamp: baseTime.Add(time.Duration(i) * time.Second),
+		}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	// Evaluate once the timeout has elapsed
+	engine.SetWindow(TimeRange{End: baseTime.Add(2 * time.Minute)})
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 2 {
+		t.Fatalf("Issues = %d, want 2", len(result.Issues))
+	}
+
+	for _, issue := range result.Issues {
+		switch issue.Context.CorrelationID {
+		case "o1":
+			if issue.Type != IssueTypeMissingStep {
+				t.Errorf("o1 Type = %v, want %v", issue.Type, IssueTypeMissingStep)
+			}
+			if issue.Context.LastStep != "payment" || issue.Context.MissingStep != "inventory" {
+				t.Errorf("o1 steps = %q -> %q, want payment -> inventory",
+					issue.Context.LastStep, issue.Context.MissingStep)
+			}
+		case "o2":
+			if issue.Type != IssueTypeMissingEnd {
+				t.Errorf("o2 Type = %v, want %v", issue.Type, IssueTypeMissingEnd)
+			}
+			if issue.Context.MissingStep != "shipped" {
+				t.Errorf("o2 MissingStep = %q, want %q", issue.Context.MissingStep, "shipped")
+			}
+		default:
+			t.Errorf("unexpected CorrelationID %q", issue.Context.CorrelationID)
+		}
+	}
+}
+
+func TestSequenceEngine_Steps_OutOfOrder(t *testing.T) {
+	engine := createStepsEngine(t, 60*time.Second)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// Inventory reserved before payment authorized
+	lines := []string{
+		"ORDER_CREATED id=o1",
+		"INVENTORY_RESERVED id=o1",
+		"PAYMENT_AUTHORIZED id=o1",
+		"SHIPPED id=o1",
+	}
+	for i, raw := range lines {
+		if err := engine.Process(ctx, &parser.ParsedLine{
+			Raw:       raw,
+			Timestamp: baseTime.Add(time.Duration(i) * time.Second),
+			LineNum:   i + 1,
+		}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %d, want 1", len(result.Issues))
+	}
+
+	issue := result.Issues[0]
+	if issue.Type != IssueTypeStepOutOfOrder {
+		t.Errorf("Type = %v, want %v", issue.Type, IssueTypeStepOutOfOrder)
+	}
+	if issue.Context.MissingStep != "payment" {
+		t.Errorf("MissingStep = %q, want %q", issue.Context.MissingStep, "payment")
+	}
+	if issue.Context.LineNum != 2 {
+		t.Errorf("LineNum = %d, want 2", issue.Context.LineNum)
+	}
+}
+
+func TestSequenceEngine_Steps_StepTimeout(t *testing.T) {
+	engine := createStepsEngine(t, 60*time.Second)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// Payment has a 5s step timeout
+	if err := engine.Process(ctx, &parser.ParsedLine{
+		Raw:       "ORDER_CREATED id=o1",
+		Timestamp: baseTime,
+	}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	for i, raw := range []string{"PAYMENT_AUTHORIZED id=o1", "INVENTORY_RESERVED id=o1", "SHIPPED id=o1"} {
+		if err := engine.Process(ctx, &parser.ParsedLine{
+			Raw:       raw,
+			Timestamp: baseTime.Add(time.Duration(10+i) * time.Second),
+		}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	// Completed within the overall timeout, but payment was late
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %d, want 1", len(result.Issues))
+	}
+	issue := result.Issues[0]
+	if issue.Type != IssueTypeLateEnd {
+		t.Errorf("Type = %v, want %v", issue.Type, IssueTypeLateEnd)
+	}
+	if !strings.Contains(issue.Description, `"payment"`) {
+		t.Errorf("Description = %q, want mention of payment", issue.Description)
+	}
+}
+
+func TestSequenceEngine_Steps_State(t *testing.T) {
+	engine := createStepsEngine(t, 60*time.Second)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	for i, raw := range []string{"ORDER_CREATED id=o1", "PAYMENT_AUTHORIZED id=o1"} {
+		if err := engine.Process(ctx, &parser.ParsedLine{
+			Raw:       raw,
+			Timestamp: baseTime.Add(time.Duration(i) * time.Second),
+		}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	states := engine.ExportState()
+	if len(states) != 1 || len(states[0].Steps) != 2 {
+		t.Fatalf("ExportState() = %+v, want 1 sequence with 2 steps", states)
+	}
+
+	restored := createStepsEngine(t, 60*time.Second)
+	restored.ImportState(states)
+
+	for i, raw := range []string{"INVENTORY_RESERVED id=o1", "SHIPPED id=o1"} {
+		if err := restored.Process(ctx, &parser.ParsedLine{
+			Raw:       raw,
+			Timestamp: baseTime.Add(time.Duration(i+2) * time.Second),
+		}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := restored.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	if len(result.Issues) != 0 {
+		t.Errorf("Issues = %d, want 0 after restoring state", len(result.Issues))
+	}
+}
+
+func createStepsEngine(t *testing.T, timeout time.Duration) *SequenceEngine {
+	t.Helper()
+
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name: "order-flow",
+			Type: "sequence",
+			Steps: []config.SequenceStep{
+				{Name: "created", Pattern: `ORDER_CREATED id=(\w+)`},
+				{Name: "payment", Pattern: `PAYMENT_AUTHORIZED id=(\w+)`, Timeout: 5 * time.Second},
+				{Name: "inventory", Pattern: `INVENTORY_RESERVED id=(\w+)`},
+				{Name: "shipped", Pattern: `SHIPPED id=(\w+)`},
+			},
+			CorrelationField: 1,
+			Timeout:          timeout,
+		}},
+	}
+
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	engine, err := NewSequenceEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewSequenceEngine() error = %v", err)
+	}
+
+	return engine
+}
+
+func TestSequenceEngine_OrphanEnds(t *testing.T) {
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:             "test",
+			Type:             "sequence",
+			StartPattern:     `START id=(\w+)`,
+			EndPattern:       `END id=(\w+)`,
+			CorrelationField: 1,
+			Timeout:          10 * time.Second,
+			ReportOrphanEnds: true,
+		}},
+	}
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	engine, err := NewSequenceEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewSequenceEngine() error = %v", err)
+	}
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	lines := []*parser.ParsedLine{
+		// Near the window start: its start may have been cut off
+		{Raw: "END id=early", Timestamp: baseTime, LineNum: 1},
+		{Raw: "START id=ok", Timestamp: baseTime.Add(20 * time.Second), LineNum: 2},
+		{Raw: "END id=ok", Timestamp: baseTime.Add(21 * time.Second), LineNum: 3},
+		// Well into the window: a real orphan
+		{Raw: "END id=late", Timestamp: baseTime.Add(60 * time.Second), LineNum: 4},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 2 {
+		t.Fatalf("Issues = %d, want 2", len(result.Issues))
+	}
+
+	for _, issue := range result.Issues {
+		if issue.Type != IssueTypeOrphanEnd {
+			t.Errorf("Type = %v, want %v", issue.Type, IssueTypeOrphanEnd)
+		}
+		switch issue.Context.CorrelationID {
+		case "early":
+			if !issue.Context.PossiblyTruncated {
+				t.Error("early orphan should be flagged as possibly truncated")
+			}
+		case "late":
+			if issue.Context.PossiblyTruncated {
+				t.Error("late orphan should not be flagged as possibly truncated")
+			}
+			if issue.Context.LineNum != 4 {
+				t.Errorf("LineNum = %d, want 4", issue.Context.LineNum)
+			}
+		default:
+			t.Errorf("unexpected CorrelationID %q", issue.Context.CorrelationID)
+		}
+	}
+}
+
+func TestSequenceEngine_OrphanEnds_Disabled(t *testing.T) {
+	engine := createSequenceEngine(t, 60*time.Second)
+
+	ctx := context.Background()
+	if err := engine.Process(ctx, &parser.ParsedLine{
+		Raw:       "END id=abc",
+		Timestamp: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC),
+	}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	if len(result.Issues) != 0 {
+		t.Errorf("Issues = %d, want 0 (orphan ends not reported by default)", len(result.Issues))
+	}
+}
+
+func TestSequenceEngine_DuplicateStarts(t *testing.T) {
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// Two starts for the same ID, then a single end
+	lines := []*parser.ParsedLine{
+		{Raw: "START id=a", Timestamp: baseTime, LineNum: 1},
+		{Raw: "START id=a", Timestamp: baseTime.Add(5 * time.Second), LineNum: 2},
+		{Raw: "END id=a", Timestamp: baseTime.Add(8 * time.Second), LineNum: 3},
+	}
+
+	tests := []struct {
+		policy    config.DuplicateStartPolicy
+		wantTypes []IssueType
+	}{
+		{policy: config.DuplicateStartsKeepLast, wantTypes: nil},
+		{policy: config.DuplicateStartsKeepFirst, wantTypes: nil},
+		{policy: config.DuplicateStartsReport, wantTypes: []IssueType{IssueTypeDuplicateStart}},
+		{policy: config.DuplicateStartsMultiset, wantTypes: []IssueType{IssueTypeMissingEnd}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			engine := createRetryEngine(t, tt.policy, 0)
+
+			ctx := context.Background()
+			for _, line := range lines {
+				if err := engine.Process(ctx, line); err != nil {
+					t.Fatalf("Process() error = %v", err)
+				}
+			}
+
+			// Evaluate once the timeout has elapsed
+			engine.SetWindow(TimeRange{End: baseTime.Add(2 * time.Minute)})
+
+			result, err := engine.Finalize(ctx)
+			if err != nil {
+				t.Fatalf("Finalize() error = %v", err)
+			}
+
+			if len(result.Issues) != len(tt.wantTypes) {
+				t.Fatalf("Issues = %d, want %d", len(result.Issues), len(tt.wantTypes))
+			}
+			for i, want := range tt.wantTypes {
+				if result.Issues[i].Type != want {
+					t.Errorf("Issues[%d].Type = %v, want %v", i, result.Issues[i].Type, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSequenceEngine_DuplicateStarts_KeepsLineNumber(t *testing.T) {
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		policy   config.DuplicateStartPolicy
+		wantLine int
+	}{
+		{policy: config.DuplicateStartsKeepFirst, wantLine: 1},
+		{policy: config.DuplicateStartsKeepLast, wantLine: 2},
+	} {
+		engine := createRetryEngine(t, tt.policy, 0)
+
+		for i := 0; i < 2; i++ {
+			if err := engine.Process(ctx, &parser.ParsedLine{
+				Raw:       "START id=a",
+				Timestamp: baseTime.Add(time.Duration(i) * time.Second),
+				LineNum:   i + 1,
+			}); err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+		}
+
+		// Evaluate once the timeout has elapsed
+		engine.SetWindow(TimeRange{End: baseTime.Add(2 * time.Minute)})
+
+		result, err := engine.Finalize(ctx)
+		if err != nil {
+			t.Fatalf("Finalize() error = %v", err)
+		}
+		if len(result.Issues) != 1 {
+			t.Fatalf("%s: Issues = %d, want 1", tt.policy, len(result.Issues))
+		}
+		if result.Issues[0].Context.LineNum != tt.wantLine {
+			t.Errorf("%s: LineNum = %d, want %d", tt.policy, result.Issues[0].Context.LineNum, tt.wantLine)
+		}
+	}
+}
+
+func TestSequenceEngine_MaxRetries(t *testing.T) {
+	engine := createRetryEngine(t, config.DuplicateStartsKeepFirst, 2)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// One start plus three retries, then completion
+	for i := 0; i < 4; i++ {
+		if err := engine.Process(ctx, &parser.ParsedLine{
+			Raw:       "START id=a",
+			Timestamp: baseTime.Add(time.Duration(i) * time.Second),
+			LineNum:   i + 1,
+		}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+	if err := engine.Process(ctx, &parser.ParsedLine{
+		Raw:       "END id=a",
+		Timestamp: baseTime.Add(10 * time.Second),
+		LineNum:   5,
+	}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %d, want 1", len(result.Issues))
+	}
+	issue := result.Issues[0]
+	if issue.Type != IssueTypeExcessiveRetries {
+		t.Errorf("Type = %v, want %v", issue.Type, IssueTypeExcessiveRetries)
+	}
+	if issue.Context.Occurrences != 3 || issue.Context.MaxAllowed != 2 {
+		t.Errorf("retries = %d/%d, want 3/2", issue.Context.Occurrences, issue.Context.MaxAllowed)
+	}
+	if issue.Context.LineNum != 4 {
+		t.Errorf("LineNum = %d, want 4", issue.Context.LineNum)
+	}
+}
+
+func createRetryEngine(t *testing.T, policy config.DuplicateStartPolicy, maxRetries int) *SequenceEngine {
+	t.Helper()
+
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:             "test",
+			Type:             "sequence",
+			StartPattern:     `START id=(\w+)`,
+			EndPattern:       `END id=(\w+)`,
+			CorrelationField: 1,
+			Timeout:          60 * time.Second,
+			DuplicateStarts:  policy,
+			MaxRetries:       maxRetries,
+		}},
+	}
+
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	engine, err := NewSequenceEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewSequenceEngine() error = %v", err)
+	}
+
+	return engine
+}
+
+func TestSequenceEngine_Pending(t *testing.T) {
+	engine := createSequenceEngine(t, 60*time.Second)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	for i, raw := range []string{"START id=old", "START id=new"} {
+		if err := engine.Process(ctx, &parser.ParsedLine{
+			Raw:       raw,
+			Timestamp: baseTime.Add(time.Duration(i) * 90 * time.Second),
+		}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	// Without a window the clock is the last line seen (new's start)
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 || result.Issues[0].Context.CorrelationID != "old" {
+		t.Errorf("Issues = %+v, want only old", result.Issues)
+	}
+	if result.Stats.Pending != 1 {
+		t.Errorf("Pending = %d, want 1", result.Stats.Pending)
+	}
+}
+
+func TestSequenceEngine_Pending_StepTimeout(t *testing.T) {
+	engine := createStepsEngine(t, time.Hour)
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	if err := engine.Process(ctx, &parser.ParsedLine{
+		Raw:       "ORDER_CREATED id=o1",
+		Timestamp: baseTime,
+	}); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	// Payment has a 5s step timeout, well before the 1h rule timeout
+	engine.SetWindow(TimeRange{End: baseTime.Add(10 * time.Second)})
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %d, want 1 (step timeout elapsed)", len(result.Issues))
+	}
+	if result.Stats.Pending != 0 {
+		t.Errorf("Pending = %d, want 0", result.Stats.Pending)
+	}
+}
+
+func TestSequenceEngine_CompositeCorrelation(t *testing.T) {
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:              "test",
+			Type:              "sequence",
+			StartPattern:      `START tenant=(?P<tenant>\w+) order=(?P<order>\w+)`,
+			EndPattern:        `END order=(?P<order>\w+) tenant=(?P<tenant>\w+)`,
+			CorrelationGroups: []string{"tenant", "order"},
+			Timeout:           60 * time.Second,
+		}},
+	}
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	engine, err := NewSequenceEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewSequenceEngine() error = %v", err)
+	}
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// Same order ID for two tenants; only acme's completes
+	lines := []string{
+		"START tenant=acme order=o1",
+		"START tenant=globex order=o1",
+		"END order=o1 tenant=acme",
+	}
+	for i, raw := range lines {
+		if err := engine.Process(ctx, &parser.ParsedLine{
+			Raw:       raw,
+			Timestamp: baseTime.Add(time.Duration(i) * time.Second),
+		}); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+	engine.SetWindow(TimeRange{End: baseTime.Add(2 * time.Minute)})
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %d, want 1", len(result.Issues))
+	}
+	if got := result.Issues[0].Context.CorrelationID; got != "globex|o1" {
+		t.Errorf("CorrelationID = %q, want %q", got, "globex|o1")
+	}
+}
+
 func createSequenceEngine(t *testing.T, timeout time.Duration) *SequenceEngine {
 	t.Helper()
 
@@ -276,3 +1018,113 @@ func createSequenceEngine(t *testing.T, timeout time.Duration) *SequenceEngine {
 
 	return engine
 }
+
+func TestSequenceEngine_Calendar(t *testing.T) {
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Calendars:       businessHours(),
+		Rules: []config.RuleConfig{{
+			Name:             "test",
+			Type:             "sequence",
+			StartPattern:     `START id=(\w+)`,
+			EndPattern:       `END id=(\w+)`,
+			CorrelationField: 1,
+			Timeout:          30 * time.Minute,
+			Calendar:         "business-hours",
+		}},
+	}
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	engine, err := NewSequenceEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewSequenceEngine() error = %v", err)
+	}
+
+	ctx := context.Background()
+	monday := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	lines := []*parser.ParsedLine{
+		// Completes 15m of business hours later, overnight
+		{Raw: "START id=a", Timestamp: monday.Add(16*time.Hour + 50*time.Minute)},
+		{Raw: "START id=b", Timestamp: monday.Add(16*time.Hour + 55*time.Minute)},
+		{Raw: "END id=a", Timestamp: monday.Add(33*time.Hour + 5*time.Minute)},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	// b has used 5m + 15m of its 30m by 09:15 Tuesday
+	engine.SetWindow(TimeRange{Start: monday, End: monday.Add(33*time.Hour + 15*time.Minute)})
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 0 {
+		t.Errorf("Issues = %v, want none (timeouts pause overnight)", result.Issues)
+	}
+	if result.Stats.Pending != 1 {
+		t.Errorf("Pending = %d, want 1", result.Stats.Pending)
+	}
+}
+
+func TestSequenceEngine_DistinctSources(t *testing.T) {
+	cfg := &config.Config{
+		LogSources:      []string{"/tmp"},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:             "test",
+			Type:             "sequence",
+			StartPattern:     `REQUEST id=(\w+)`,
+			EndPattern:       `DONE id=(\w+)`,
+			CorrelationField: 1,
+			Timeout:          time.Minute,
+			DistinctSources:  true,
+			ReportOrphanEnds: true,
+		}},
+	}
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	engine, err := NewSequenceEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewSequenceEngine() error = %v", err)
+	}
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	lines := []*parser.ParsedLine{
+		{Raw: "REQUEST id=a", Timestamp: baseTime, Source: "gateway.log", LineNum: 1},
+		{Raw: "REQUEST id=b", Timestamp: baseTime, Source: "gateway.log", LineNum: 2},
+		// The gateway's own DONE doesn't complete a; the backend's does
+		{Raw: "DONE id=a", Timestamp: baseTime.Add(time.Second), Source: "gateway.log", LineNum: 3},
+		{Raw: "DONE id=a", Timestamp: baseTime.Add(2 * time.Second), Source: "backend.log", LineNum: 1},
+		// b only completes at the gateway
+		{Raw: "DONE id=b", Timestamp: baseTime.Add(3 * time.Second), Source: "gateway.log", LineNum: 4},
+	}
+	for _, line := range lines {
+		if err := engine.Process(ctx, line); err != nil {
+			t.Fatalf("Process() error = %v", err)
+		}
+	}
+
+	engine.SetWindow(TimeRange{Start: baseTime, End: baseTime.Add(2 * time.Minute)})
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %v, want 1", result.Issues)
+	}
+	issue := result.Issues[0]
+	if issue.Type != IssueTypeMissingEnd || issue.Context.CorrelationID != "b" {
+		t.Errorf("Issue = %s for %q, want missing_end for b", issue.Type, issue.Context.CorrelationID)
+	}
+	if result.Stats.Completed != 1 {
+		t.Errorf("Completed = %d, want 1", result.Stats.Completed)
+	}
+}
@@ -0,0 +1,254 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ccollicutt/negalog/pkg/config"
+	"github.com/ccollicutt/negalog/pkg/parser"
+)
+
+// lastLine is the most recent line seen from a source.
+type lastLine struct {
+	timestamp time.Time
+	file      string
+	lineNum   int
+}
+
+// SourceSilenceEngine implements RuleEngine for log source liveness.
+// It tracks the last line written by each log file, or by each value of a
+// source label, and reports sources that went quiet for longer than allowed.
+// Any parseable line counts; there is no pattern.
+type SourceSilenceEngine struct {
+	name         string
+	description  string
+	maxSilence   time.Duration
+	label        string // source label to group by, empty to track files
+	sourceLabels config.SourceLabels
+
+	// State
+	mu     sync.Mutex
+	last   map[string]lastLine // keyed by file path or label value
+	files  []string            // log files that matched log_sources
+	parsed map[string]bool     // files that produced a parseable line
+	issues []Issue
+	seen   TimeRange // span of line timestamps processed
+	window TimeRange // analysis window set by the analyzer
+	stats  RuleStats
+}
+
+// NewSourceSilenceEngine creates a new source silence engine from a rule config.
+func NewSourceSilenceEngine(rule *config.RuleConfig) (*SourceSilenceEngine, error) {
+	if rule.RuleTypeEnum() != config.RuleTypeSourceSilence {
+		return nil, fmt.Errorf("rule %q is not a source_silence rule", rule.Name)
+	}
+
+	return &SourceSilenceEngine{
+		name:         rule.Name,
+		description:  rule.Description,
+		maxSilence:   rule.MaxSilence,
+		label:        rule.GroupByLabel,
+		sourceLabels: rule.SourceLabels(),
+		last:         make(map[string]lastLine),
+	}, nil
+}
+
+// Name returns the rule name.
+func (e *SourceSilenceEngine) Name() string {
+	return e.name
+}
+
+// Type returns the rule type.
+func (e *SourceSilenceEngine) Type() RuleType {
+	return RuleTypeSourceSilence
+}
+
+// key returns the source a file is tracked as, and false if the rule groups
+// by a label the file doesn't have.
+func (e *SourceSilenceEngine) key(file string) (string, bool) {
+	if e.label == "" {
+		return file, true
+	}
+	value, ok := e.sourceLabels.For(file)[e.label]
+	return value, ok
+}
+
+// Process handles a single log line, reporting a silence if its source had
+// been quiet for too long before it.
+func (e *SourceSilenceEngine) Process(ctx context.Context, line *parser.ParsedLine) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stats.LinesProcessed++
+	if e.stats.LinesProcessed == 1 || line.Timestamp.Before(e.seen.Start) {
+		e.seen.Start = line.Timestamp
+	}
+	if e.stats.LinesProcessed == 1 || line.Timestamp.After(e.seen.End) {
+		e.seen.End = line.Timestamp
+	}
+
+	key, ok := e.key(line.Source)
+	if !ok {
+		return nil
+	}
+	e.stats.LinesMatched++
+
+	prev, ok := e.last[key]
+	if ok && line.Timestamp.Sub(prev.timestamp) > e.maxSilence {
+		e.issues = append(e.issues, e.silenceIssue(key, prev, line.Timestamp))
+	}
+	if !ok || !line.Timestamp.Before(prev.timestamp) {
+		e.last[key] = lastLine{timestamp: line.Timestamp, file: line.Source, lineNum: line.LineNum}
+	}
+
+	return nil
+}
+
+// silenceIssue reports a source that was quiet from prev until end.
+func (e *SourceSilenceEngine) silenceIssue(key string, prev lastLine, end time.Time) Issue {
+	gap := end.Sub(prev.timestamp)
+	return Issue{
+		Type: IssueTypeSourceSilence,
+		Description: fmt.Sprintf("%s wrote nothing for %s, between %s and %s (max allowed: %s)",
+			key, gap.Round(time.Second), prev.timestamp.Format(time.RFC3339), end.Format(time.RFC3339), e.maxSilence),
+		Context: IssueContext{
+			CorrelationID: e.correlationID(key),
+			StartTime:     prev.timestamp,
+			EndTime:       end,
+			ActualGap:     gap,
+			ExpectedGap:   e.maxSilence,
+			Source:        prev.file,
+			LineNum:       prev.lineNum,
+		},
+	}
+}
+
+// SetWindow sets the analysis window. Sources are judged silent up to its end.
+func (e *SourceSilenceEngine) SetWindow(window TimeRange) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.window = window
+}
+
+// SetSources sets the log files that matched log_sources.
+func (e *SourceSilenceEngine) SetSources(files []string, parsed map[string]bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.files = files
+	e.parsed = parsed
+}
+
+// evalWindow returns the analysis window, falling back to the span of
+// lines this engine has processed when no window was set.
+func (e *SourceSilenceEngine) evalWindow() TimeRange {
+	window := e.window
+	if window.Start.IsZero() {
+		window.Start = e.seen.Start
+	}
+	if window.End.IsZero() {
+		window.End = e.seen.End
+	}
+	return window
+}
+
+// Finalize completes analysis and returns detected issues.
+func (e *SourceSilenceEngine) Finalize(ctx context.Context) (*RuleResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stats.EndTime = time.Now()
+
+	result := &RuleResult{
+		RuleName:    e.name,
+		RuleType:    RuleTypeSourceSilence,
+		Description: e.description,
+		Issues:      e.issues,
+		Stats:       e.stats,
+	}
+	e.issues = nil
+	if result.Issues == nil {
+		result.Issues = make([]Issue, 0)
+	}
+
+	// Files that matched but never produced a parseable line
+	keys := make(map[string]bool, len(e.last))
+	for key := range e.last {
+		keys[key] = true
+	}
+	for _, file := range e.files {
+		key, ok := e.key(file)
+		if !ok {
+			continue
+		}
+		if !e.parsed[file] {
+			result.Issues = append(result.Issues, Issue{
+				Type:        IssueTypeEmptySource,
+				Description: fmt.Sprintf("%s has no parseable log lines", file),
+				Context: IssueContext{
+					CorrelationID: e.correlationID(key),
+					Source:        file,
+				},
+			})
+			continue
+		}
+		// Lines from this source all fell outside the time range
+		keys[key] = true
+	}
+
+	window := e.evalWindow()
+	if window.End.IsZero() {
+		return result, nil
+	}
+
+	// Trailing silence up to the evaluation end time
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	for _, key := range sorted {
+		prev, ok := e.last[key]
+		if !ok {
+			if window.Start.IsZero() {
+				continue
+			}
+			// Nothing within the window at all
+			prev = lastLine{timestamp: window.Start}
+			if e.label == "" {
+				prev.file = key
+			}
+		}
+		if window.End.Sub(prev.timestamp) > e.maxSilence {
+			result.Issues = append(result.Issues, e.silenceIssue(key, prev, window.End))
+		}
+	}
+
+	return result, nil
+}
+
+// correlationID returns the label value a source is grouped under, if any.
+func (e *SourceSilenceEngine) correlationID(key string) string {
+	if e.label == "" {
+		return ""
+	}
+	return key
+}
+
+// Reset clears internal state for reuse.
+func (e *SourceSilenceEngine) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.last = make(map[string]lastLine)
+	e.files = nil
+	e.parsed = nil
+	e.issues = nil
+	e.seen = TimeRange{}
+	e.window = TimeRange{}
+	e.stats = RuleStats{}
+}
@@ -0,0 +1,188 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ccollicutt/negalog/pkg/config"
+	"github.com/ccollicutt/negalog/pkg/parser"
+)
+
+func TestNewSourceSilenceEngine_WrongType(t *testing.T) {
+	rule := &config.RuleConfig{
+		Name: "test",
+		Type: "rate",
+	}
+	if _, err := NewSourceSilenceEngine(rule); err == nil {
+		t.Error("NewSourceSilenceEngine() expected error for wrong type")
+	}
+}
+
+func TestSourceSilenceEngine_Files(t *testing.T) {
+	engine := createSourceSilenceEngine(t, "")
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// app.log writes steadily; worker.log pauses for 20m then stops at 10:30
+	for i := 0; i <= 6; i++ {
+		processSilence(t, engine, "/var/log/app.log", i+1, baseTime.Add(time.Duration(i)*10*time.Minute))
+	}
+	processSilence(t, engine, "/var/log/worker.log", 1, baseTime)
+	processSilence(t, engine, "/var/log/worker.log", 2, baseTime.Add(20*time.Minute))
+	processSilence(t, engine, "/var/log/worker.log", 3, baseTime.Add(30*time.Minute))
+
+	engine.SetSources(
+		[]string{"/var/log/app.log", "/var/log/worker.log", "/var/log/empty.log"},
+		map[string]bool{"/var/log/app.log": true, "/var/log/worker.log": true},
+	)
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 3 {
+		t.Fatalf("Issues = %v, want 3", result.Issues)
+	}
+
+	gap := result.Issues[0]
+	if gap.Type != IssueTypeSourceSilence || gap.Context.Source != "/var/log/worker.log" {
+		t.Errorf("Issues[0] = %v, want worker.log silence", gap)
+	}
+	if gap.Context.ActualGap != 20*time.Minute || gap.Context.LineNum != 1 {
+		t.Errorf("ActualGap = %v, LineNum = %d, want 20m after line 1", gap.Context.ActualGap, gap.Context.LineNum)
+	}
+
+	empty := result.Issues[1]
+	if empty.Type != IssueTypeEmptySource || empty.Context.Source != "/var/log/empty.log" {
+		t.Errorf("Issues[1] = %v, want empty.log empty_source", empty)
+	}
+
+	// Trailing silence relative to the last line seen (11:00)
+	trailing := result.Issues[2]
+	if trailing.Type != IssueTypeSourceSilence || trailing.Context.Source != "/var/log/worker.log" {
+		t.Errorf("Issues[2] = %v, want worker.log trailing silence", trailing)
+	}
+	if !trailing.Context.EndTime.Equal(baseTime.Add(time.Hour)) || trailing.Context.ActualGap != 30*time.Minute {
+		t.Errorf("trailing = %v to %v, want 30m to 11:00", trailing.Context.StartTime, trailing.Context.EndTime)
+	}
+}
+
+func TestSourceSilenceEngine_Window(t *testing.T) {
+	engine := createSourceSilenceEngine(t, "")
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	processSilence(t, engine, "/var/log/app.log", 1, baseTime)
+	processSilence(t, engine, "/var/log/app.log", 2, baseTime.Add(10*time.Minute))
+
+	// old.log parsed, but all its lines fell before the time range
+	engine.SetSources(
+		[]string{"/var/log/app.log", "/var/log/old.log"},
+		map[string]bool{"/var/log/app.log": true, "/var/log/old.log": true},
+	)
+	engine.SetWindow(TimeRange{Start: baseTime, End: baseTime.Add(20 * time.Minute)})
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %v, want 1", result.Issues)
+	}
+	issue := result.Issues[0]
+	if issue.Context.Source != "/var/log/old.log" || issue.Context.ActualGap != 20*time.Minute {
+		t.Errorf("issue = %v, want old.log silent for the whole window", issue)
+	}
+
+	// Silent relative to --now
+	engine.Reset()
+	processSilence(t, engine, "/var/log/app.log", 1, baseTime)
+	engine.SetWindow(TimeRange{Start: baseTime, End: baseTime.Add(time.Hour)})
+
+	result, err = engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Context.ActualGap != time.Hour {
+		t.Errorf("Issues = %v, want app.log silent for 1h", result.Issues)
+	}
+}
+
+func TestSourceSilenceEngine_GroupByLabel(t *testing.T) {
+	engine := createSourceSilenceEngine(t, "service")
+
+	ctx := context.Background()
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// Two api replicas take turns; the db writes once; audit.log has no service
+	processSilence(t, engine, "/var/log/api-1.log", 1, baseTime)
+	processSilence(t, engine, "/var/log/db.log", 1, baseTime)
+	processSilence(t, engine, "/var/log/api-2.log", 1, baseTime.Add(10*time.Minute))
+	processSilence(t, engine, "/var/log/api-1.log", 2, baseTime.Add(20*time.Minute))
+	processSilence(t, engine, "/var/log/audit.log", 1, baseTime.Add(30*time.Minute))
+
+	engine.SetSources(
+		[]string{"/var/log/api-1.log", "/var/log/api-2.log", "/var/log/db.log", "/var/log/audit.log"},
+		map[string]bool{"/var/log/api-1.log": true, "/var/log/api-2.log": true, "/var/log/db.log": true, "/var/log/audit.log": true},
+	)
+
+	result, err := engine.Finalize(ctx)
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if len(result.Issues) != 1 {
+		t.Fatalf("Issues = %v, want 1", result.Issues)
+	}
+	issue := result.Issues[0]
+	if issue.Context.CorrelationID != "db" || issue.Context.Source != "/var/log/db.log" {
+		t.Errorf("issue = %v, want service db", issue)
+	}
+	if result.Stats.LinesMatched != 4 {
+		t.Errorf("LinesMatched = %d, want 4", result.Stats.LinesMatched)
+	}
+}
+
+func createSourceSilenceEngine(t *testing.T, groupByLabel string) *SourceSilenceEngine {
+	t.Helper()
+
+	cfg := &config.Config{
+		LogSources: []string{"/var/log/*.log"},
+		SourceLabels: config.SourceLabels{
+			{Path: "/var/log/api-*.log", Labels: map[string]string{"service": "api"}},
+			{Path: "/var/log/db.log", Labels: map[string]string{"service": "db"}},
+		},
+		TimestampFormat: config.TimestampConfig{Pattern: `^(\d+)`, Layout: "2006"},
+		Rules: []config.RuleConfig{{
+			Name:         "test",
+			Type:         "source_silence",
+			MaxSilence:   15 * time.Minute,
+			GroupByLabel: groupByLabel,
+		}},
+	}
+
+	if err := config.Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	engine, err := NewSourceSilenceEngine(&cfg.Rules[0])
+	if err != nil {
+		t.Fatalf("NewSourceSilenceEngine() error = %v", err)
+	}
+
+	return engine
+}
+
+func processSilence(t *testing.T, engine *SourceSilenceEngine, source string, lineNum int, ts time.Time) {
+	t.Helper()
+
+	line := &parser.ParsedLine{Raw: "line", Timestamp: ts, Source: source, LineNum: lineNum}
+	if err := engine.Process(context.Background(), line); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+}
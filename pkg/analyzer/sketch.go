@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// sketchAccuracy is the relative accuracy of quantiles reported by latencySketch.
+const sketchAccuracy = 0.01
+
+// latencySketch is a streaming quantile sketch for durations. Values are
+// counted in logarithmically sized buckets, so memory grows with the range of
+// durations seen rather than their number, and any quantile is accurate to
+// within sketchAccuracy of the true value.
+type latencySketch struct {
+	gamma   float64
+	logG    float64
+	buckets map[int]int
+	zeros   int // durations of zero or less
+	count   int
+	min     time.Duration
+	max     time.Duration
+}
+
+// newLatencySketch creates an empty sketch.
+func newLatencySketch() *latencySketch {
+	gamma := (1 + sketchAccuracy) / (1 - sketchAccuracy)
+	return &latencySketch{
+		gamma:   gamma,
+		logG:    math.Log(gamma),
+		buckets: make(map[int]int),
+	}
+}
+
+// Add records a single duration.
+func (s *latencySketch) Add(d time.Duration) {
+	if s.count == 0 || d < s.min {
+		s.min = d
+	}
+	if s.count == 0 || d > s.max {
+		s.max = d
+	}
+	s.count++
+
+	if d <= 0 {
+		s.zeros++
+		return
+	}
+	s.buckets[int(math.Ceil(math.Log(float64(d))/s.logG))]++
+}
+
+// Count returns the number of durations recorded.
+func (s *latencySketch) Count() int {
+	return s.count
+}
+
+// Quantile returns an estimate of the q-th quantile (0 <= q <= 1).
+func (s *latencySketch) Quantile(q float64) time.Duration {
+	if s.count == 0 {
+		return 0
+	}
+
+	rank := int(q * float64(s.count-1))
+	if rank < s.zeros {
+		return max(s.min, 0)
+	}
+	rank -= s.zeros
+
+	keys := make([]int, 0, len(s.buckets))
+	for k := range s.buckets {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	for _, k := range keys {
+		if rank < s.buckets[k] {
+			// Midpoint of the bucket (gamma^(k-1), gamma^k], which keeps the
+			// relative error within sketchAccuracy
+			v := time.Duration(2 * math.Pow(s.gamma, float64(k)) / (s.gamma + 1))
+			return min(max(v, s.min), s.max)
+		}
+		rank -= s.buckets[k]
+	}
+	return s.max
+}
+
+// Summary returns the latency distribution, or nil if nothing was recorded.
+func (s *latencySketch) Summary() *LatencyStats {
+	if s.count == 0 {
+		return nil
+	}
+	return &LatencyStats{
+		Min: s.min,
+		P50: s.Quantile(0.50),
+		P95: s.Quantile(0.95),
+		P99: s.Quantile(0.99),
+		Max: s.max,
+	}
+}
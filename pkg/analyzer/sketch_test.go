@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLatencySketch_Empty(t *testing.T) {
+	s := newLatencySketch()
+
+	if s.Count() != 0 {
+		t.Errorf("Count() = %d, want 0", s.Count())
+	}
+	if s.Summary() != nil {
+		t.Error("Summary() should be nil for an empty sketch")
+	}
+}
+
+func TestLatencySketch_Quantiles(t *testing.T) {
+	s := newLatencySketch()
+
+	// 1ms..1000ms, shuffled by stepping through with a stride coprime to 1000
+	for i := 0; i < 1000; i++ {
+		s.Add(time.Duration((i*7919)%1000+1) * time.Millisecond)
+	}
+
+	if s.Count() != 1000 {
+		t.Fatalf("Count() = %d, want 1000", s.Count())
+	}
+
+	tests := []struct {
+		q    float64
+		want time.Duration
+	}{
+		{0.50, 500 * time.Millisecond},
+		{0.95, 950 * time.Millisecond},
+		{0.99, 990 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		got := s.Quantile(tt.q)
+		if relErr := math.Abs(float64(got-tt.want)) / float64(tt.want); relErr > 0.02 {
+			t.Errorf("Quantile(%v) = %v, want %v within 2%%", tt.q, got, tt.want)
+		}
+	}
+
+	summary := s.Summary()
+	if summary.Min != time.Millisecond {
+		t.Errorf("Min = %v, want 1ms", summary.Min)
+	}
+	if summary.Max != time.Second {
+		t.Errorf("Max = %v, want 1s", summary.Max)
+	}
+}
+
+func TestLatencySketch_SingleValue(t *testing.T) {
+	s := newLatencySketch()
+	s.Add(42 * time.Second)
+
+	summary := s.Summary()
+	for name, got := range map[string]time.Duration{
+		"Min": summary.Min, "P50": summary.P50, "P99": summary.P99, "Max": summary.Max,
+	} {
+		if got != 42*time.Second {
+			t.Errorf("%s = %v, want 42s", name, got)
+		}
+	}
+}
+
+func TestLatencySketch_Zero(t *testing.T) {
+	s := newLatencySketch()
+	s.Add(0)
+	s.Add(0)
+	s.Add(time.Second)
+
+	if got := s.Quantile(0.5); got != 0 {
+		t.Errorf("Quantile(0.5) = %v, want 0", got)
+	}
+	if got := s.Quantile(1); got != time.Second {
+		t.Errorf("Quantile(1) = %v, want 1s", got)
+	}
+}
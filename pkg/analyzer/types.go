@@ -3,15 +3,22 @@ package analyzer
 
 import (
 	"time"
+
+	"github.com/ccollicutt/negalog/pkg/config"
 )
 
 // RuleType enumerates detection strategies.
 type RuleType string
 
 const (
-	RuleTypeSequence    RuleType = "sequence"
-	RuleTypePeriodic    RuleType = "periodic"
-	RuleTypeConditional RuleType = "conditional"
+	RuleTypeSequence       RuleType = "sequence"
+	RuleTypePeriodic       RuleType = "periodic"
+	RuleTypeConditional    RuleType = "conditional"
+	RuleTypeRate           RuleType = "rate"
+	RuleTypeReconciliation RuleType = "reconciliation"
+	RuleTypeBaseline       RuleType = "baseline"
+	RuleTypeSourceSilence  RuleType = "source_silence"
+	RuleTypeOrdering       RuleType = "ordering"
 )
 
 // IssueType categorizes detected issues.
@@ -21,12 +28,74 @@ const (
 	// IssueTypeMissingEnd indicates a sequence start without matching end.
 	IssueTypeMissingEnd IssueType = "missing_end"
 
+	// IssueTypeLateEnd indicates a sequence that completed, but only after its timeout.
+	IssueTypeLateEnd IssueType = "late_end"
+
+	// IssueTypeMissingStep indicates a multi-step sequence stalled before an intermediate step.
+	IssueTypeMissingStep IssueType = "missing_step"
+
+	// IssueTypeStepOutOfOrder indicates a sequence step was seen before an earlier step.
+	IssueTypeStepOutOfOrder IssueType = "step_out_of_order"
+
+	// IssueTypeOrphanEnd indicates a sequence end without a matching start.
+	IssueTypeOrphanEnd IssueType = "orphan_end"
+
+	// IssueTypeDuplicateStart indicates a sequence started again while already open.
+	IssueTypeDuplicateStart IssueType = "duplicate_start"
+
+	// IssueTypeExcessiveRetries indicates a sequence was restarted more often than allowed.
+	IssueTypeExcessiveRetries IssueType = "excessive_retries"
+
 	// IssueTypeGapExceeded indicates a periodic log gap exceeds the threshold.
 	IssueTypeGapExceeded IssueType = "gap_exceeded"
 
+	// IssueTypeLeadingSilence indicates a periodic log was absent for too long
+	// after the start of the analysis window (or for the whole window).
+	IssueTypeLeadingSilence IssueType = "leading_silence"
+
+	// IssueTypeTrailingSilence indicates a periodic log stopped and was absent
+	// for too long before the end of the analysis window.
+	IssueTypeTrailingSilence IssueType = "trailing_silence"
+
+	// IssueTypeMissedSlot indicates a scheduled periodic log did not occur near its scheduled time.
+	IssueTypeMissedSlot IssueType = "missed_slot"
+
+	// IssueTypeUnexpectedRun indicates a scheduled periodic log occurred outside any scheduled slot.
+	IssueTypeUnexpectedRun IssueType = "unexpected_run"
+
+	// IssueTypeAbsentMember indicates an expected roster member produced nothing in the window.
+	IssueTypeAbsentMember IssueType = "absent_member"
+
+	// IssueTypeBelowMinRate indicates a rate window with fewer matches than required.
+	IssueTypeBelowMinRate IssueType = "below_min_rate"
+
+	// IssueTypeBelowBaseline indicates an hour with far fewer matches than its learned baseline.
+	IssueTypeBelowBaseline IssueType = "below_baseline"
+
+	// IssueTypeSourceSilence indicates a log source wrote nothing for longer than allowed.
+	IssueTypeSourceSilence IssueType = "source_silence"
+
+	// IssueTypeEmptySource indicates a log file that matched log_sources but had no parseable lines.
+	IssueTypeEmptySource IssueType = "empty_source"
+
+	// IssueTypeOrderViolation indicates an event seen after one that should have followed it.
+	IssueTypeOrderViolation IssueType = "order_violation"
+
+	// IssueTypeCountMismatch indicates a reconciliation window whose two counts disagree.
+	IssueTypeCountMismatch IssueType = "count_mismatch"
+
 	// IssueTypeMissingConsequence indicates a trigger without expected consequence.
 	IssueTypeMissingConsequence IssueType = "missing_consequence"
 
+	// IssueTypeConsequenceCount indicates a trigger followed by too few or too many expected events.
+	IssueTypeConsequenceCount IssueType = "consequence_count"
+
+	// IssueTypeForbiddenEvent indicates a forbidden event occurred within the timeout of a trigger.
+	IssueTypeForbiddenEvent IssueType = "forbidden_event"
+
+	// IssueTypeUnprecededEvent indicates a trigger without its required preceding event.
+	IssueTypeUnprecededEvent IssueType = "unpreceded_event"
+
 	// IssueTypeBelowMinOccurrences indicates fewer occurrences than required.
 	IssueTypeBelowMinOccurrences IssueType = "below_min_occurrences"
 )
@@ -42,9 +111,19 @@ type RuleResult struct {
 	// Description is the rule's description, if any.
 	Description string
 
+	// Severity is the rule's severity, shared by all its issues.
+	Severity config.Severity
+
+	// Tags are the rule's tags, if any.
+	Tags []string
+
 	// Issues contains all detected problems.
 	Issues []Issue
 
+	// Suppressed contains problems that overlapped a maintenance window.
+	// They are reported but don't count as issues.
+	Suppressed []Issue
+
 	// Stats provides execution statistics.
 	Stats RuleStats
 }
@@ -57,6 +136,17 @@ type RuleStats struct {
 	// LinesMatched is the number of lines that matched the rule's patterns.
 	LinesMatched int
 
+	// Pending is the number of open expectations that had not yet timed out
+	// at the evaluation clock, and so were not reported as issues.
+	Pending int
+
+	// Completed is the number of expectations that were met, late or not
+	// (for sequence and correlated conditional rules).
+	Completed int
+
+	// Latency is the distribution of completion times, nil if none completed.
+	Latency *LatencyStats
+
 	// StartTime is when rule processing began.
 	StartTime time.Time
 
@@ -64,6 +154,16 @@ type RuleStats struct {
 	EndTime time.Time
 }
 
+// LatencyStats summarizes how long completed expectations took.
+// Quantiles are estimates accurate to within 1% of the true value.
+type LatencyStats struct {
+	Min time.Duration
+	P50 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
 // HasIssues returns true if any issues were detected.
 func (r *RuleResult) HasIssues() bool {
 	return len(r.Issues) > 0
@@ -79,6 +179,9 @@ type Issue struct {
 
 	// Context provides details about where/when the issue occurred.
 	Context IssueContext
+
+	// SuppressedBy names the maintenance window a suppressed issue overlapped.
+	SuppressedBy string
 }
 
 // IssueContext provides detailed information about an issue.
@@ -98,6 +201,24 @@ type IssueContext struct {
 	// LineNum is the line number of the triggering event.
 	LineNum int
 
+	// EndSource is the log file of the event that completed a sequence (for
+	// late_end), of the forbidden event (for forbidden_event), or of the
+	// before_pattern event that came too late (for order_violation).
+	EndSource string
+
+	// EndLineNum is the line number of that event.
+	EndLineNum int
+
+	// Duration is how long a late sequence actually took to complete (for
+	// late_end), or how long after its trigger a forbidden event occurred.
+	Duration time.Duration
+
+	// LastStep is the last sequence step reached in order (for sequence rules).
+	LastStep string
+
+	// MissingStep is the sequence step that was expected next (for sequence rules).
+	MissingStep string
+
 	// Timeout is the expected maximum time for completion.
 	Timeout time.Duration
 
@@ -107,9 +228,39 @@ type IssueContext struct {
 	// ExpectedGap is the maximum allowed gap (for periodic rules).
 	ExpectedGap time.Duration
 
-	// Occurrences is the actual count (for min_occurrences checks).
+	// ScheduledTime is the expected time of a missed slot (for scheduled periodic rules).
+	ScheduledTime time.Time
+
+	// Tolerance is how far from a scheduled time an occurrence may be (for scheduled periodic rules).
+	Tolerance time.Duration
+
+	// Occurrences is the actual count (for min_occurrences, rate, and
+	// expected_count checks).
 	Occurrences int
 
 	// MinRequired is the minimum required count.
 	MinRequired int
+
+	// Baseline is the learned mean count for the window (for baseline rules).
+	Baseline float64
+
+	// MaxAllowed is the maximum allowed count.
+	MaxAllowed int
+
+	// NoMaxAllowed is set when the count has no maximum, so MaxAllowed is
+	// unused (for expected_count checks).
+	NoMaxAllowed bool
+
+	// InvalidCount is the trigger's expected count when it could not be read
+	// as a number (for expected_count checks with from_group).
+	InvalidCount string
+
+	// LeftCount and RightCount are the counts of the two patterns in the
+	// window (for reconciliation rules).
+	LeftCount  int
+	RightCount int
+
+	// PossiblyTruncated is set when the issue is close enough to the start of
+	// the analysis window that the log input may simply begin mid-flow.
+	PossiblyTruncated bool
 }
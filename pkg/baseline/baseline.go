@@ -0,0 +1,178 @@
+// Package baseline learns and stores the expected volume of a log pattern for
+// each hour of the week.
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/ccollicutt/negalog/pkg/parser"
+)
+
+// HoursPerWeek is the number of slots in a baseline.
+const HoursPerWeek = 7 * 24
+
+// Slot is the learned volume for one hour of the week.
+type Slot struct {
+	// Mean is the average number of matches in this hour.
+	Mean float64 `json:"mean"`
+
+	// StdDev is the population standard deviation of the hourly counts.
+	StdDev float64 `json:"stddev"`
+
+	// Samples is the number of complete hours of history averaged.
+	Samples int `json:"samples"`
+}
+
+// Baseline is the learned volume of a pattern per hour of the week.
+type Baseline struct {
+	// Rule is the name of the rule the baseline was built for.
+	Rule string `json:"rule"`
+
+	// Pattern is the regex that was counted.
+	Pattern string `json:"pattern"`
+
+	// Timezone is the IANA zone hours of the week were taken in.
+	Timezone string `json:"timezone"`
+
+	// Start and End bound the history the baseline was learned from.
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	// Slots holds one entry per hour of the week, starting Sunday 00:00.
+	Slots []Slot `json:"slots"`
+}
+
+// HourOfWeek returns the slot index of t in loc, 0 for Sunday 00:00-01:00.
+func HourOfWeek(t time.Time, loc *time.Location) int {
+	t = t.In(loc)
+	return int(t.Weekday())*24 + t.Hour()
+}
+
+// HourStart returns the start of the hour in loc that contains t, in UTC.
+// Zones offset from UTC by part of an hour, like Asia/Kolkata, start their
+// hours off the UTC hour.
+func HourStart(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	into := time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second +
+		time.Duration(local.Nanosecond())
+	return t.Add(-into).UTC()
+}
+
+// Load reads a baseline file written by Save.
+func Load(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- user-provided baseline path is expected
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline: %w", err)
+	}
+
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing baseline %s: %w", path, err)
+	}
+	if len(b.Slots) != HoursPerWeek {
+		return nil, fmt.Errorf("baseline %s has %d slots, want %d", path, len(b.Slots), HoursPerWeek)
+	}
+
+	return &b, nil
+}
+
+// Save writes the baseline to path as JSON.
+func (b *Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding baseline: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing baseline: %w", err)
+	}
+	return nil
+}
+
+// Learner counts matches of a pattern per hour over historical logs.
+// Lines that don't match still extend the span of history, so hours in
+// which the pattern never appeared are learned as zero.
+type Learner struct {
+	rule    string
+	pattern *regexp.Regexp
+	loc     *time.Location
+
+	counts map[time.Time]int // matches per hour, keyed by HourStart
+	start  time.Time
+	end    time.Time
+	lines  int
+}
+
+// NewLearner creates a learner for a rule's pattern. Hours of the week are
+// taken in loc, whose name is recorded in the baseline.
+func NewLearner(rule string, pattern *regexp.Regexp, loc *time.Location) *Learner {
+	return &Learner{
+		rule:    rule,
+		pattern: pattern,
+		loc:     loc,
+		counts:  make(map[time.Time]int),
+	}
+}
+
+// Add counts a single log line.
+func (l *Learner) Add(line *parser.ParsedLine) {
+	l.lines++
+	if l.lines == 1 || line.Timestamp.Before(l.start) {
+		l.start = line.Timestamp
+	}
+	if l.lines == 1 || line.Timestamp.After(l.end) {
+		l.end = line.Timestamp
+	}
+
+	if l.pattern.MatchString(line.Raw) {
+		l.counts[HourStart(line.Timestamp, l.loc)]++
+	}
+}
+
+// Baseline computes the baseline from the lines added so far. Only hours that
+// lie entirely within the history are sampled.
+func (l *Learner) Baseline() *Baseline {
+	b := &Baseline{
+		Rule:     l.rule,
+		Pattern:  l.pattern.String(),
+		Timezone: l.loc.String(),
+		Start:    l.start,
+		End:      l.end,
+		Slots:    make([]Slot, HoursPerWeek),
+	}
+	if l.lines == 0 {
+		return b
+	}
+
+	// Running mean and sum of squared differences per slot (Welford)
+	m2 := make([]float64, HoursPerWeek)
+
+	hour := HourStart(l.start, l.loc)
+	if hour.Before(l.start) {
+		hour = hour.Add(time.Hour)
+	}
+	for ; !hour.Add(time.Hour).After(l.end); hour = hour.Add(time.Hour) {
+		i := HourOfWeek(hour, l.loc)
+		s := &b.Slots[i]
+		x := float64(l.counts[hour])
+
+		s.Samples++
+		delta := x - s.Mean
+		s.Mean += delta / float64(s.Samples)
+		m2[i] += delta * (x - s.Mean)
+	}
+
+	for i := range b.Slots {
+		if b.Slots[i].Samples > 0 {
+			b.Slots[i].StdDev = math.Sqrt(m2[i] / float64(b.Slots[i].Samples))
+		}
+	}
+
+	return b
+}
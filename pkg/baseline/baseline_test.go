@@ -0,0 +1,176 @@
+package baseline
+
+import (
+	"math"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ccollicutt/negalog/pkg/parser"
+)
+
+func TestHourOfWeek(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		t    time.Time
+		loc  *time.Location
+		want int
+	}{
+		{"sunday midnight", time.Date(2024, 1, 14, 0, 30, 0, 0, time.UTC), time.UTC, 0},
+		{"monday 10am", time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), time.UTC, 34},
+		{"saturday 11pm", time.Date(2024, 1, 20, 23, 59, 0, 0, time.UTC), time.UTC, 167},
+		{"other zone", time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), ny, 29},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HourOfWeek(tt.t, tt.loc); got != tt.want {
+				t.Errorf("HourOfWeek() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHourStart(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		t    time.Time
+		loc  *time.Location
+		want time.Time
+	}{
+		{"utc", time.Date(2024, 1, 15, 10, 45, 30, 0, time.UTC), time.UTC, time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)},
+		// 10:15 UTC is 15:45 in Kolkata, whose hour began at 15:00 local, 09:30 UTC
+		{"half-hour zone", time.Date(2024, 1, 15, 10, 15, 0, 0, time.UTC), kolkata, time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)},
+		{"on the hour", time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC), kolkata, time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HourStart(tt.t, tt.loc)
+			if !got.Equal(tt.want) || got.Location() != time.UTC {
+				t.Errorf("HourStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLearner_Baseline(t *testing.T) {
+	l := NewLearner("orders", regexp.MustCompile(`ORDER_ACCEPTED`), time.UTC)
+
+	// Monday 10:00 in three consecutive weeks, with 4, 6, and 0 orders
+	monday := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+	for w, n := range []int{4, 6, 0} {
+		for i := 0; i < n; i++ {
+			l.Add(&parser.ParsedLine{Raw: "ORDER_ACCEPTED", Timestamp: monday.Add(time.Duration(w)*week + time.Minute)})
+		}
+	}
+	// History runs from a little before the first hour to the end of the last
+	l.Add(&parser.ParsedLine{Raw: "start", Timestamp: monday.Add(-time.Minute)})
+	l.Add(&parser.ParsedLine{Raw: "end", Timestamp: monday.Add(2*week + time.Hour)})
+
+	b := l.Baseline()
+
+	slot := b.Slots[HourOfWeek(monday, time.UTC)]
+	if slot.Samples != 3 {
+		t.Errorf("Samples = %d, want 3", slot.Samples)
+	}
+	if math.Abs(slot.Mean-10.0/3) > 1e-9 {
+		t.Errorf("Mean = %v, want 3.33", slot.Mean)
+	}
+	// Population standard deviation of 4, 6, 0
+	if want := math.Sqrt((math.Pow(4-10.0/3, 2) + math.Pow(6-10.0/3, 2) + math.Pow(10.0/3, 2)) / 3); math.Abs(slot.StdDev-want) > 1e-9 {
+		t.Errorf("StdDev = %v, want %v", slot.StdDev, want)
+	}
+
+	// The partial hour before Monday 10:00 is not sampled
+	if b.Slots[HourOfWeek(monday.Add(-time.Hour), time.UTC)].Samples != 2 {
+		t.Errorf("Monday 09:00 samples = %d, want 2", b.Slots[HourOfWeek(monday.Add(-time.Hour), time.UTC)].Samples)
+	}
+	if b.Pattern != "ORDER_ACCEPTED" || b.Timezone != "UTC" {
+		t.Errorf("Pattern, Timezone = %q, %q", b.Pattern, b.Timezone)
+	}
+}
+
+func TestLearner_Baseline_HalfHourZone(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+	l := NewLearner("orders", regexp.MustCompile(`ORDER_ACCEPTED`), kolkata)
+
+	// Monday 15:00-16:00 in Kolkata is 09:30-10:30 UTC. Orders either side of
+	// 10:00 UTC fall in the same local hour.
+	hour := time.Date(2024, 1, 15, 15, 0, 0, 0, kolkata)
+	l.Add(&parser.ParsedLine{Raw: "start", Timestamp: hour})
+	l.Add(&parser.ParsedLine{Raw: "ORDER_ACCEPTED", Timestamp: hour.Add(10 * time.Minute)})
+	l.Add(&parser.ParsedLine{Raw: "ORDER_ACCEPTED", Timestamp: hour.Add(50 * time.Minute)})
+	l.Add(&parser.ParsedLine{Raw: "end", Timestamp: hour.Add(time.Hour)})
+
+	b := l.Baseline()
+
+	slot := b.Slots[HourOfWeek(hour, kolkata)]
+	if slot.Samples != 1 || slot.Mean != 2 {
+		t.Errorf("Monday 15:00 slot = %+v, want one sample of 2", slot)
+	}
+}
+
+func TestBaseline_SaveLoad(t *testing.T) {
+	l := NewLearner("orders", regexp.MustCompile(`ORDER_ACCEPTED`), time.UTC)
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	l.Add(&parser.ParsedLine{Raw: "ORDER_ACCEPTED", Timestamp: start})
+	l.Add(&parser.ParsedLine{Raw: "end", Timestamp: start.Add(time.Hour)})
+
+	path := filepath.Join(t.TempDir(), "orders.json")
+	if err := l.Baseline().Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	b, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if b.Rule != "orders" || b.Slots[HourOfWeek(start, time.UTC)].Mean != 1 {
+		t.Errorf("Load() = %+v", b)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	short := &Baseline{Slots: make([]Slot, 24)}
+	shortPath := filepath.Join(dir, "short.json")
+	if err := short.Save(shortPath); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		errMsg string
+	}{
+		{"missing", filepath.Join(dir, "missing.json"), "reading baseline"},
+		{"wrong size", shortPath, "has 24 slots, want 168"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.path)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Load() error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+// Package calendar describes when expectations are active: weekly hours in a
+// timezone, minus holidays.
+package calendar
+
+import (
+	"time"
+)
+
+// maxSearch bounds how far ahead Add looks for active time, so that a
+// calendar that is never active again terminates.
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// dateLayout is the format of holiday dates.
+const dateLayout = "2006-01-02"
+
+// Span is a range of active time within a day, in minutes after midnight.
+// End may be 1440 (24:00).
+type Span struct {
+	Start int
+	End   int
+}
+
+// Calendar holds the active hours for each day of the week in a timezone,
+// and the dates that are not active at all.
+//
+// A nil *Calendar is always active, so that rules without a calendar can use
+// Elapsed and Add to measure plain wall-clock time.
+type Calendar struct {
+	loc      *time.Location
+	week     [7][]Span
+	holidays map[string]bool // keyed by date in dateLayout
+}
+
+// New creates a calendar in loc with no active hours.
+func New(loc *time.Location) *Calendar {
+	return &Calendar{
+		loc:      loc,
+		holidays: make(map[string]bool),
+	}
+}
+
+// AddHours makes a span of the given weekday active.
+func (c *Calendar) AddHours(day time.Weekday, span Span) {
+	c.week[day] = append(c.week[day], span)
+}
+
+// AddHoliday makes a date, given as YYYY-MM-DD, inactive all day.
+func (c *Calendar) AddHoliday(date string) error {
+	d, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return err
+	}
+	c.holidays[d.Format(dateLayout)] = true
+	return nil
+}
+
+// Location returns the timezone the calendar's hours are in.
+func (c *Calendar) Location() *time.Location {
+	return c.loc
+}
+
+// Active returns true if t falls within active hours.
+func (c *Calendar) Active(t time.Time) bool {
+	if c == nil {
+		return true
+	}
+
+	t = t.In(c.loc)
+	if c.holidays[t.Format(dateLayout)] {
+		return false
+	}
+	for _, s := range c.week[t.Weekday()] {
+		start, end := c.bounds(t, s)
+		if !t.Before(start) && t.Before(end) {
+			return true
+		}
+	}
+	return false
+}
+
+// Elapsed returns how much active time passes between from and to. For a
+// nil calendar it is to.Sub(from).
+func (c *Calendar) Elapsed(from, to time.Time) time.Duration {
+	if c == nil || !to.After(from) {
+		return to.Sub(from)
+	}
+
+	var total time.Duration
+	for day := c.midnight(from); day.Before(to); day = c.nextDay(day) {
+		if c.holidays[day.Format(dateLayout)] {
+			continue
+		}
+		for _, s := range c.week[day.Weekday()] {
+			start, end := c.bounds(day, s)
+			start, end = later(start, from), earlier(end, to)
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+	}
+	return total
+}
+
+// Add returns the time at which d of active time has passed since t. For a
+// nil calendar it is t.Add(d). It returns the zero time if the calendar has
+// too little active time left.
+func (c *Calendar) Add(t time.Time, d time.Duration) time.Time {
+	if c == nil || d <= 0 {
+		return t.Add(d)
+	}
+
+	limit := t.Add(maxSearch)
+	for day := c.midnight(t); day.Before(limit); day = c.nextDay(day) {
+		if c.holidays[day.Format(dateLayout)] {
+			continue
+		}
+		for _, s := range c.week[day.Weekday()] {
+			start, end := c.bounds(day, s)
+			start = later(start, t)
+			if !end.After(start) {
+				continue
+			}
+			avail := end.Sub(start)
+			if d <= avail {
+				return start.Add(d)
+			}
+			d -= avail
+		}
+	}
+	return time.Time{}
+}
+
+// midnight returns the start of t's day in the calendar's timezone.
+func (c *Calendar) midnight(t time.Time) time.Time {
+	t = t.In(c.loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.loc)
+}
+
+// nextDay returns the midnight after day.
+func (c *Calendar) nextDay(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, c.loc)
+}
+
+// bounds returns the start and end of a span on the day of t, in wall-clock
+// time, so that spans keep their hours across daylight saving changes.
+func (c *Calendar) bounds(t time.Time, s Span) (time.Time, time.Time) {
+	t = t.In(c.loc)
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, s.Start, 0, 0, c.loc), time.Date(y, m, d, 0, s.End, 0, 0, c.loc)
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earlier(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
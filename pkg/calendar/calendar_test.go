@@ -0,0 +1,129 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+// businessHours is Monday to Friday 08:00-20:00 in New York, with a holiday
+// on Wednesday 2024-01-17.
+func businessHours(t *testing.T) *Calendar {
+	t.Helper()
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	c := New(loc)
+	for day := time.Monday; day <= time.Friday; day++ {
+		c.AddHours(day, Span{Start: 8 * 60, End: 20 * 60})
+	}
+	if err := c.AddHoliday("2024-01-17"); err != nil {
+		t.Fatalf("AddHoliday() error = %v", err)
+	}
+	return c
+}
+
+func TestCalendar_Active(t *testing.T) {
+	c := businessHours(t)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, c.Location())
+	}
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{"monday morning", at(15, 8, 0), true},
+		{"monday before hours", at(15, 7, 59), false},
+		{"monday at close", at(15, 20, 0), false},
+		{"holiday", at(17, 12, 0), false},
+		{"saturday", at(20, 12, 0), false},
+		{"in UTC", time.Date(2024, 1, 15, 13, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Active(tt.t); got != tt.want {
+				t.Errorf("Active(%v) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendar_Elapsed(t *testing.T) {
+	c := businessHours(t)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, c.Location())
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     time.Duration
+	}{
+		{"within a day", at(15, 9, 0), at(15, 10, 30), 90 * time.Minute},
+		{"overnight", at(15, 19, 0), at(16, 9, 0), 2 * time.Hour},
+		{"over the holiday", at(16, 19, 0), at(18, 9, 0), 2 * time.Hour},
+		{"over the weekend", at(19, 19, 30), at(22, 8, 30), time.Hour},
+		{"all inactive", at(20, 0, 0), at(21, 23, 0), 0},
+		{"reversed", at(15, 10, 0), at(15, 9, 0), -time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Elapsed(tt.from, tt.to); got != tt.want {
+				t.Errorf("Elapsed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendar_Add(t *testing.T) {
+	c := businessHours(t)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, c.Location())
+	}
+
+	tests := []struct {
+		name string
+		t    time.Time
+		d    time.Duration
+		want time.Time
+	}{
+		{"within a day", at(15, 9, 0), time.Hour, at(15, 10, 0)},
+		{"exactly to close", at(15, 19, 0), time.Hour, at(15, 20, 0)},
+		{"carries overnight", at(15, 19, 0), 2 * time.Hour, at(16, 9, 0)},
+		{"skips the holiday", at(16, 19, 0), 2 * time.Hour, at(18, 9, 0)},
+		{"starts outside hours", at(20, 12, 0), 30 * time.Minute, at(22, 8, 30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Add(tt.t, tt.d); !got.Equal(tt.want) {
+				t.Errorf("Add() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := New(time.UTC).Add(at(15, 9, 0), time.Hour); !got.IsZero() {
+		t.Errorf("Add() on a calendar with no hours = %v, want zero", got)
+	}
+}
+
+func TestCalendar_Nil(t *testing.T) {
+	var c *Calendar
+	from := time.Date(2024, 1, 20, 12, 0, 0, 0, time.UTC)
+
+	if !c.Active(from) {
+		t.Error("nil calendar is not active")
+	}
+	if got := c.Elapsed(from, from.Add(time.Hour)); got != time.Hour {
+		t.Errorf("Elapsed() = %v, want 1h", got)
+	}
+	if got := c.Add(from, time.Hour); !got.Equal(from.Add(time.Hour)) {
+		t.Errorf("Add() = %v, want %v", got, from.Add(time.Hour))
+	}
+}
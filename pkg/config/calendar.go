@@ -0,0 +1,224 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ccollicutt/negalog/pkg/calendar"
+)
+
+// CalendarConfig defines when the rules that reference it are active, such
+// as business hours. Periodic rules measure gaps across active time only,
+// sequence and conditional rules pause their timeouts outside it, and rate
+// rules only judge windows that lie entirely within it.
+type CalendarConfig struct {
+	// Name is how rules refer to the calendar.
+	Name string `yaml:"name"`
+
+	// Timezone is the IANA zone the hours are in. Defaults to UTC.
+	Timezone string `yaml:"timezone,omitempty"`
+
+	// Hours lists the weekly active hours.
+	Hours []HoursConfig `yaml:"hours"`
+
+	// Holidays lists inactive dates (YYYY-MM-DD).
+	Holidays []string `yaml:"holidays,omitempty"`
+
+	// HolidaysFile is a path to a file with one date per line. Blank lines
+	// and lines starting with # are ignored.
+	HolidaysFile string `yaml:"holidays_file,omitempty"`
+
+	// compiled is the calendar built during validation; holidays from the
+	// file are added to it by LoadCalendars.
+	compiled *calendar.Calendar
+}
+
+// HoursConfig is a daily range of active time on some days of the week.
+type HoursConfig struct {
+	// Days lists weekdays by name ("mon") or as ranges ("mon-fri").
+	Days []string `yaml:"days"`
+
+	// Start and End are times of day (HH:MM). End may be 24:00.
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+}
+
+// Calendar returns the calendar built during validation.
+func (c *CalendarConfig) Calendar() *calendar.Calendar {
+	return c.compiled
+}
+
+// validateCalendars checks each calendar and builds it, so that rules can
+// then refer to it.
+func validateCalendars(calendars []CalendarConfig) error {
+	names := make(map[string]bool, len(calendars))
+	for i := range calendars {
+		c := &calendars[i]
+		if err := validateCalendar(c); err != nil {
+			return fmt.Errorf("calendars[%d] (%s): %w", i, c.Name, err)
+		}
+		if names[c.Name] {
+			return fmt.Errorf("calendars[%d]: duplicate name %q", i, c.Name)
+		}
+		names[c.Name] = true
+	}
+	return nil
+}
+
+func validateCalendar(c *CalendarConfig) error {
+	if c.Name == "" {
+		return errors.New("name is required")
+	}
+
+	loc := time.UTC
+	if c.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(c.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone: %w", err)
+		}
+	}
+	cal := calendar.New(loc)
+
+	if len(c.Hours) == 0 {
+		return errors.New("hours is required")
+	}
+	for i, h := range c.Hours {
+		if err := addHours(cal, h); err != nil {
+			return fmt.Errorf("hours[%d]: %w", i, err)
+		}
+	}
+
+	for _, date := range c.Holidays {
+		if err := cal.AddHoliday(date); err != nil {
+			return fmt.Errorf("invalid holiday %q (use YYYY-MM-DD)", date)
+		}
+	}
+
+	c.compiled = cal
+	return nil
+}
+
+// addHours adds one hours entry to a calendar.
+func addHours(cal *calendar.Calendar, h HoursConfig) error {
+	if len(h.Days) == 0 {
+		return errors.New("days is required")
+	}
+
+	start, err := parseTimeOfDay(h.Start)
+	if err != nil {
+		return fmt.Errorf("invalid start: %w", err)
+	}
+	end, err := parseTimeOfDay(h.End)
+	if err != nil {
+		return fmt.Errorf("invalid end: %w", err)
+	}
+	if end <= start {
+		return fmt.Errorf("end (%s) must be after start (%s); split hours that cross midnight", h.End, h.Start)
+	}
+
+	for _, spec := range h.Days {
+		days, err := parseDays(spec)
+		if err != nil {
+			return err
+		}
+		for _, day := range days {
+			cal.AddHours(day, calendar.Span{Start: start, End: end})
+		}
+	}
+	return nil
+}
+
+// parseTimeOfDay parses HH:MM (00:00 to 24:00) into minutes after midnight.
+func parseTimeOfDay(s string) (int, error) {
+	if s == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a time of day (use HH:MM)", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseDays parses a weekday name or a range of them such as "mon-fri".
+// Ranges may wrap around the end of the week ("fri-mon").
+func parseDays(spec string) ([]time.Weekday, error) {
+	from, to, isRange := strings.Cut(strings.ToLower(spec), "-")
+	first, ok := weekdays[from]
+	if !ok {
+		return nil, fmt.Errorf("invalid day %q (use sun, mon, ..., sat or a range like mon-fri)", spec)
+	}
+	if !isRange {
+		return []time.Weekday{first}, nil
+	}
+
+	last, ok := weekdays[to]
+	if !ok {
+		return nil, fmt.Errorf("invalid day %q (use sun, mon, ..., sat or a range like mon-fri)", spec)
+	}
+	days := []time.Weekday{first}
+	for day := first; day != last; {
+		day = (day + 1) % 7
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// resolveCalendar links a rule to the calendar it names.
+func resolveCalendar(cfg *Config, rule *RuleConfig) error {
+	if rule.Calendar == "" {
+		return nil
+	}
+
+	switch rule.RuleTypeEnum() {
+	case RuleTypePeriodic:
+		if rule.Schedule != "" {
+			return errors.New("calendar cannot be combined with schedule")
+		}
+	case RuleTypeSequence, RuleTypeConditional:
+		// Timeouts pause outside active time
+	case RuleTypeRate:
+		// Windows outside active time are not judged
+	default:
+		return fmt.Errorf("calendar is not supported on %s rules", rule.Type)
+	}
+
+	for i := range cfg.Calendars {
+		if cfg.Calendars[i].Name == rule.Calendar {
+			rule.calendar = cfg.Calendars[i].compiled
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown calendar %q", rule.Calendar)
+}
+
+// LoadCalendars reads holidays from each calendar's holidays_file. It is
+// called by Load after validation.
+func (c *Config) LoadCalendars() error {
+	for i := range c.Calendars {
+		cal := &c.Calendars[i]
+		if cal.HolidaysFile == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(cal.HolidaysFile) // #nosec G304 -- user-provided holidays path is expected
+		if err != nil {
+			return fmt.Errorf("calendars[%d] (%s): reading holidays_file: %w", i, cal.Name, err)
+		}
+		for _, date := range parseMembers(data) {
+			if err := cal.compiled.AddHoliday(date); err != nil {
+				return fmt.Errorf("calendars[%d] (%s): %s: invalid holiday %q (use YYYY-MM-DD)",
+					i, cal.Name, cal.HolidaysFile, date)
+			}
+		}
+	}
+	return nil
+}
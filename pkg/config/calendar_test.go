@@ -0,0 +1,228 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad_Calendars(t *testing.T) {
+	holidays := writeTempFile(t, "holidays.txt", "# 2024\n2024-01-01\n\n2024-07-04\n")
+	content := fmt.Sprintf(`log_sources:
+  - /var/log/*.log
+timestamp_format:
+  pattern: '^\[(\d{4})\]'
+  layout: "2006"
+calendars:
+  - name: business-hours
+    timezone: America/New_York
+    hours:
+      - days: [mon-fri]
+        start: "08:00"
+        end: "20:00"
+    holidays: [2024-12-25]
+    holidays_file: %s
+rules:
+  - name: batch
+    type: periodic
+    pattern: 'BATCH'
+    max_gap: 1h
+    calendar: business-hours
+`, holidays)
+	path := writeTempFile(t, "config.yaml", content)
+	cfg, err := Load(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	cal := cfg.Rules[0].ActiveCalendar()
+	if cal == nil {
+		t.Fatal("ActiveCalendar() = nil")
+	}
+	if cal != cfg.Calendars[0].Calendar() {
+		t.Error("rule does not share the named calendar")
+	}
+
+	ny, _ := time.LoadLocation("America/New_York")
+	tests := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{"weekday", time.Date(2024, 1, 2, 9, 0, 0, 0, ny), true},
+		{"evening", time.Date(2024, 1, 2, 21, 0, 0, 0, ny), false},
+		{"weekend", time.Date(2024, 1, 6, 9, 0, 0, 0, ny), false},
+		{"inline holiday", time.Date(2024, 12, 25, 9, 0, 0, 0, ny), false},
+		{"file holiday", time.Date(2024, 7, 4, 9, 0, 0, 0, ny), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cal.Active(tt.t); got != tt.want {
+				t.Errorf("Active(%v) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_Calendars_BadHolidaysFile(t *testing.T) {
+	holidays := writeTempFile(t, "holidays.txt", "2024-01-01\nJan 2\n")
+
+	for _, tt := range []struct {
+		name   string
+		file   string
+		errMsg string
+	}{
+		{"missing", filepath.Join(t.TempDir(), "nope.txt"), "reading holidays_file"},
+		{"bad date", holidays, `invalid holiday "Jan 2"`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			content := fmt.Sprintf(`log_sources:
+  - /var/log/*.log
+timestamp_format:
+  pattern: '^\[(\d{4})\]'
+  layout: "2006"
+calendars:
+  - name: business-hours
+    hours:
+      - days: [mon-fri]
+        start: "08:00"
+        end: "20:00"
+    holidays_file: %s
+rules:
+  - name: batch
+    type: periodic
+    pattern: 'BATCH'
+    max_gap: 1h
+`, tt.file)
+			path := writeTempFile(t, "config.yaml", content)
+			_, err := Load(context.Background(), path)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Load() error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestValidate_Calendars(t *testing.T) {
+	weekdays := HoursConfig{Days: []string{"mon-fri"}, Start: "08:00", End: "20:00"}
+	periodic := RuleConfig{Name: "test", Type: "periodic", Pattern: "X", MaxGap: time.Hour, Calendar: "work"}
+
+	tests := []struct {
+		name      string
+		calendars []CalendarConfig
+		rule      RuleConfig
+		errMsg    string
+	}{
+		{"no name", []CalendarConfig{{Hours: []HoursConfig{weekdays}}}, periodic, "name is required"},
+		{"no hours", []CalendarConfig{{Name: "work"}}, periodic, "hours is required"},
+		{
+			"duplicate",
+			[]CalendarConfig{{Name: "work", Hours: []HoursConfig{weekdays}}, {Name: "work", Hours: []HoursConfig{weekdays}}},
+			periodic,
+			`duplicate name "work"`,
+		},
+		{
+			"bad timezone",
+			[]CalendarConfig{{Name: "work", Timezone: "Mars/Olympus", Hours: []HoursConfig{weekdays}}},
+			periodic,
+			"invalid timezone",
+		},
+		{
+			"no days",
+			[]CalendarConfig{{Name: "work", Hours: []HoursConfig{{Start: "08:00", End: "20:00"}}}},
+			periodic,
+			"days is required",
+		},
+		{
+			"bad day",
+			[]CalendarConfig{{Name: "work", Hours: []HoursConfig{{Days: []string{"monday"}, Start: "08:00", End: "20:00"}}}},
+			periodic,
+			`invalid day "monday"`,
+		},
+		{
+			"bad time",
+			[]CalendarConfig{{Name: "work", Hours: []HoursConfig{{Days: []string{"mon"}, Start: "8am", End: "20:00"}}}},
+			periodic,
+			"invalid start",
+		},
+		{
+			"crosses midnight",
+			[]CalendarConfig{{Name: "work", Hours: []HoursConfig{{Days: []string{"mon"}, Start: "22:00", End: "06:00"}}}},
+			periodic,
+			"must be after start",
+		},
+		{
+			"bad holiday",
+			[]CalendarConfig{{Name: "work", Hours: []HoursConfig{weekdays}, Holidays: []string{"2024-13-01"}}},
+			periodic,
+			`invalid holiday "2024-13-01"`,
+		},
+		{
+			"unknown calendar",
+			[]CalendarConfig{{Name: "other", Hours: []HoursConfig{weekdays}}},
+			periodic,
+			`unknown calendar "work"`,
+		},
+		{
+			"with schedule",
+			[]CalendarConfig{{Name: "work", Hours: []HoursConfig{weekdays}}},
+			RuleConfig{Name: "test", Type: "periodic", Pattern: "X", Schedule: "@daily", Tolerance: time.Hour, Calendar: "work"},
+			"cannot be combined with schedule",
+		},
+		{
+			"rate unknown calendar",
+			[]CalendarConfig{{Name: "other", Hours: []HoursConfig{weekdays}}},
+			RuleConfig{Name: "test", Type: "rate", Pattern: "X", Window: time.Minute, MinCount: 1, Calendar: "work"},
+			`unknown calendar "work"`,
+		},
+		{
+			"unsupported type",
+			[]CalendarConfig{{Name: "work", Hours: []HoursConfig{weekdays}}},
+			RuleConfig{Name: "test", Type: "source_silence", MaxSilence: time.Minute, Calendar: "work"},
+			"calendar is not supported on source_silence rules",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				LogSources:      []string{"/var/log/*.log"},
+				TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+				Rules:           []RuleConfig{tt.rule},
+				Calendars:       tt.calendars,
+			}
+			err := Validate(cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestParseDays(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []time.Weekday
+	}{
+		{"wed", []time.Weekday{time.Wednesday}},
+		{"Mon-Wed", []time.Weekday{time.Monday, time.Tuesday, time.Wednesday}},
+		{"fri-mon", []time.Weekday{time.Friday, time.Saturday, time.Sunday, time.Monday}},
+		{"sun-sun", []time.Weekday{time.Sunday}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := parseDays(tt.spec)
+			if err != nil {
+				t.Fatalf("parseDays() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDays() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
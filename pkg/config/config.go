@@ -7,13 +7,17 @@ import (
 	"net/url"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/ccollicutt/negalog/pkg/cron"
 )
 
 // Load reads and validates a configuration file.
-func Load(_ context.Context, path string) (*Config, error) {
+func Load(ctx context.Context, path string) (*Config, error) {
 	data, err := os.ReadFile(path) // #nosec G304 -- user-provided config path is expected
 	if err != nil {
 		return nil, fmt.Errorf("reading config file: %w", err)
@@ -30,6 +34,14 @@ func Load(_ context.Context, path string) (*Config, error) {
 		return nil, fmt.Errorf("validating config: %w", err)
 	}
 
+	if err := cfg.LoadRosters(ctx); err != nil {
+		return nil, fmt.Errorf("loading rosters: %w", err)
+	}
+
+	if err := cfg.LoadCalendars(); err != nil {
+		return nil, fmt.Errorf("loading calendars: %w", err)
+	}
+
 	return cfg, nil
 }
 
@@ -43,16 +55,33 @@ func Validate(cfg *Config) error {
 		return fmt.Errorf("timestamp_format: %w", err)
 	}
 
+	if err := validateSourceLabels(cfg.SourceLabels); err != nil {
+		return err
+	}
+
+	if err := validateCalendars(cfg.Calendars); err != nil {
+		return err
+	}
+
 	if len(cfg.Rules) == 0 {
 		return errors.New("rules: at least one rule is required")
 	}
 
 	for i := range cfg.Rules {
-		if err := validateRule(&cfg.Rules[i]); err != nil {
-			return fmt.Errorf("rules[%d] (%s): %w", i, cfg.Rules[i].Name, err)
+		rule := &cfg.Rules[i]
+		rule.sourceLabels = cfg.SourceLabels
+		if err := validateRule(rule); err != nil {
+			return fmt.Errorf("rules[%d] (%s): %w", i, rule.Name, err)
+		}
+		if err := resolveCalendar(cfg, rule); err != nil {
+			return fmt.Errorf("rules[%d] (%s): %w", i, rule.Name, err)
 		}
 	}
 
+	if err := validateMaintenanceWindows(cfg); err != nil {
+		return err
+	}
+
 	// Webhooks are optional, but validate if present
 	for i := range cfg.Webhooks {
 		if err := validateWebhook(&cfg.Webhooks[i]); err != nil {
@@ -95,19 +124,61 @@ func validateRule(rule *RuleConfig) error {
 		return errors.New("name is required")
 	}
 
+	if err := validateSeverity(rule); err != nil {
+		return err
+	}
+
+	var err error
 	switch RuleType(rule.Type) {
 	case RuleTypeSequence:
-		return validateSequenceRule(rule)
+		err = validateSequenceRule(rule)
 	case RuleTypePeriodic:
-		return validatePeriodicRule(rule)
+		err = validatePeriodicRule(rule)
 	case RuleTypeConditional:
-		return validateConditionalRule(rule)
+		err = validateConditionalRule(rule)
+	case RuleTypeRate:
+		err = validateRateRule(rule)
+	case RuleTypeReconciliation:
+		err = validateReconciliationRule(rule)
+	case RuleTypeBaseline:
+		err = validateBaselineRule(rule)
+	case RuleTypeSourceSilence:
+		err = validateSourceSilenceRule(rule)
+	case RuleTypeOrdering:
+		err = validateOrderingRule(rule)
 	default:
-		return fmt.Errorf("invalid type %q (must be sequence, periodic, or conditional)", rule.Type)
+		return fmt.Errorf("invalid type %q (must be sequence, periodic, conditional, rate, reconciliation, baseline, source_silence, or ordering)", rule.Type)
+	}
+	if err != nil {
+		return err
 	}
+
+	if err := validateScope(rule); err != nil {
+		return err
+	}
+
+	return validateRoster(rule)
 }
 
 func validateSequenceRule(rule *RuleConfig) error {
+	switch rule.DuplicateStarts {
+	case "":
+		rule.DuplicateStarts = DuplicateStartsKeepLast
+	case DuplicateStartsKeepLast, DuplicateStartsKeepFirst, DuplicateStartsReport, DuplicateStartsMultiset:
+		// Valid
+	default:
+		return fmt.Errorf("invalid duplicate_starts %q (must be keep_last, keep_first, report, or multiset)",
+			rule.DuplicateStarts)
+	}
+
+	if rule.MaxRetries < 0 {
+		return errors.New("max_retries must not be negative")
+	}
+
+	if len(rule.Steps) > 0 {
+		return validateSequenceSteps(rule)
+	}
+
 	if rule.StartPattern == "" {
 		return errors.New("start_pattern is required for sequence rules")
 	}
@@ -128,18 +199,80 @@ func validateSequenceRule(rule *RuleConfig) error {
 	}
 	rule.compiledEndPattern = re
 
-	if rule.CorrelationField < 1 {
-		return errors.New("correlation_field must be >= 1 (capture group index)")
+	if err := requireCorrelation(rule); err != nil {
+		return err
+	}
+
+	if rule.startKey, err = resolveCorrelationKey(rule, "start_pattern",
+		rule.compiledStartPattern, rule.StartCorrelationGroups); err != nil {
+		return err
 	}
 
-	if rule.compiledStartPattern.NumSubexp() < rule.CorrelationField {
-		return fmt.Errorf("start_pattern has only %d capture groups, but correlation_field is %d",
-			rule.compiledStartPattern.NumSubexp(), rule.CorrelationField)
+	if rule.endKey, err = resolveCorrelationKey(rule, "end_pattern",
+		rule.compiledEndPattern, rule.EndCorrelationGroups); err != nil {
+		return err
 	}
 
-	if rule.compiledEndPattern.NumSubexp() < rule.CorrelationField {
-		return fmt.Errorf("end_pattern has only %d capture groups, but correlation_field is %d",
-			rule.compiledEndPattern.NumSubexp(), rule.CorrelationField)
+	if err := matchCorrelationKeys("start_pattern", rule.startKey, "end_pattern", rule.endKey); err != nil {
+		return err
+	}
+
+	if rule.Timeout <= 0 {
+		rule.Timeout = DefaultTimeout
+	}
+
+	return nil
+}
+
+// validateSequenceSteps validates a sequence rule defined with an ordered steps list.
+func validateSequenceSteps(rule *RuleConfig) error {
+	if rule.StartPattern != "" || rule.EndPattern != "" {
+		return errors.New("steps cannot be combined with start_pattern/end_pattern")
+	}
+
+	if len(rule.Steps) < 2 {
+		return errors.New("steps must define at least two steps")
+	}
+
+	if err := requireCorrelation(rule); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(rule.Steps))
+	for i := range rule.Steps {
+		step := &rule.Steps[i]
+		if step.Name == "" {
+			step.Name = fmt.Sprintf("step%d", i+1)
+		}
+		if seen[step.Name] {
+			return fmt.Errorf("steps[%d]: duplicate step name %q", i, step.Name)
+		}
+		seen[step.Name] = true
+
+		if step.Pattern == "" {
+			return fmt.Errorf("steps[%d] (%s): pattern is required", i, step.Name)
+		}
+
+		re, err := regexp.Compile(step.Pattern)
+		if err != nil {
+			return fmt.Errorf("steps[%d] (%s): invalid pattern: %w", i, step.Name, err)
+		}
+		step.compiledPattern = re
+
+		field := fmt.Sprintf("steps[%d] (%s): pattern", i, step.Name)
+		if step.key, err = resolveCorrelationKey(rule, field, re, step.CorrelationGroups); err != nil {
+			return err
+		}
+		if i > 0 {
+			first := fmt.Sprintf("steps[0] (%s): pattern", rule.Steps[0].Name)
+			if err := matchCorrelationKeys(first, rule.Steps[0].key, field, step.key); err != nil {
+				return err
+			}
+		}
+
+		if step.Timeout < 0 {
+			return fmt.Errorf("steps[%d] (%s): timeout must not be negative", i, step.Name)
+		}
 	}
 
 	if rule.Timeout <= 0 {
@@ -160,6 +293,22 @@ func validatePeriodicRule(rule *RuleConfig) error {
 	}
 	rule.compiledPattern = re
 
+	if rule.GroupBy != "" {
+		idx, err := resolveGroup(re, "pattern", rule.GroupBy)
+		if err != nil {
+			return fmt.Errorf("invalid group_by: %w", err)
+		}
+		rule.groupIndex = idx
+	}
+
+	if rule.Schedule != "" {
+		return validateSchedule(rule)
+	}
+
+	if rule.Tolerance != 0 || rule.Timezone != "" {
+		return errors.New("tolerance and timezone require a schedule")
+	}
+
 	if rule.MaxGap <= 0 {
 		rule.MaxGap = DefaultMaxGap
 	}
@@ -167,6 +316,42 @@ func validatePeriodicRule(rule *RuleConfig) error {
 	return nil
 }
 
+// validateSchedule validates a periodic rule that uses a cron schedule.
+func validateSchedule(rule *RuleConfig) error {
+	if rule.MaxGap != 0 {
+		return errors.New("schedule cannot be combined with max_gap")
+	}
+
+	sched, err := cron.Parse(rule.Schedule)
+	if err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+	rule.compiledSchedule = sched
+
+	if rule.Tolerance < 0 {
+		return errors.New("tolerance must not be negative")
+	}
+	if rule.Tolerance == 0 {
+		rule.Tolerance = DefaultTolerance
+	}
+
+	return resolveTimezone(rule)
+}
+
+// resolveTimezone loads the rule's timezone, if set.
+func resolveTimezone(rule *RuleConfig) error {
+	if rule.Timezone == "" {
+		return nil
+	}
+
+	loc, err := time.LoadLocation(rule.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone: %w", err)
+	}
+	rule.location = loc
+	return nil
+}
+
 func validateConditionalRule(rule *RuleConfig) error {
 	if rule.TriggerPattern == "" {
 		return errors.New("trigger_pattern is required for conditional rules")
@@ -178,32 +363,300 @@ func validateConditionalRule(rule *RuleConfig) error {
 	}
 	rule.compiledTriggerPattern = re
 
-	if rule.ExpectedPattern == "" {
-		return errors.New("expected_pattern is required for conditional rules")
+	// Exactly one pattern sets what must (or must not) go with each trigger
+	modes := []struct {
+		field    string
+		pattern  string
+		groups   []string
+		compiled **regexp.Regexp
+		key      *CorrelationKey
+	}{
+		{"expected_pattern", rule.ExpectedPattern, rule.ExpectedCorrelationGroups,
+			&rule.compiledExpectedPattern, &rule.expectedKey},
+		{"forbidden_pattern", rule.ForbiddenPattern, rule.ForbiddenCorrelationGroups,
+			&rule.compiledForbiddenPattern, &rule.forbiddenKey},
+		{"preceded_by", rule.PrecededBy, rule.PrecededByCorrelationGroups,
+			&rule.compiledPrecededBy, &rule.precededByKey},
+	}
+
+	mode := -1
+	for i, m := range modes {
+		if m.pattern == "" {
+			continue
+		}
+		if mode >= 0 {
+			return fmt.Errorf("%s and %s cannot both be set", modes[mode].field, m.field)
+		}
+		mode = i
 	}
+	if mode < 0 {
+		return errors.New("expected_pattern, forbidden_pattern, or preceded_by is required for conditional rules")
+	}
+	m := modes[mode]
 
-	re, err = regexp.Compile(rule.ExpectedPattern)
+	re, err = regexp.Compile(m.pattern)
 	if err != nil {
-		return fmt.Errorf("invalid expected_pattern: %w", err)
+		return fmt.Errorf("invalid %s: %w", m.field, err)
 	}
-	rule.compiledExpectedPattern = re
+	*m.compiled = re
 
 	if rule.Timeout <= 0 {
 		rule.Timeout = DefaultTimeout
 	}
 
-	// Validate correlation_field if specified
-	if rule.CorrelationField > 0 {
-		if rule.compiledTriggerPattern.NumSubexp() < rule.CorrelationField {
-			return fmt.Errorf("trigger_pattern has only %d capture groups, but correlation_field is %d",
-				rule.compiledTriggerPattern.NumSubexp(), rule.CorrelationField)
+	// Validate correlation if specified
+	if len(rule.CorrelationGroups) > 0 && rule.CorrelationField > 0 {
+		return errors.New("correlation_field and correlation_groups cannot both be set")
+	}
+
+	if rule.CorrelationField > 0 || len(rule.CorrelationGroups) > 0 ||
+		len(rule.TriggerCorrelationGroups) > 0 || len(m.groups) > 0 {
+		if rule.triggerKey, err = resolveCorrelationKey(rule, "trigger_pattern",
+			rule.compiledTriggerPattern, rule.TriggerCorrelationGroups); err != nil {
+			return err
+		}
+		if *m.key, err = resolveCorrelationKey(rule, m.field, re, m.groups); err != nil {
+			return err
+		}
+		if err := matchCorrelationKeys("trigger_pattern", rule.triggerKey, m.field, *m.key); err != nil {
+			return err
+		}
+	}
+
+	return validateExpectedCount(rule)
+}
+
+func validateRateRule(rule *RuleConfig) error {
+	if rule.Pattern == "" {
+		return errors.New("pattern is required for rate rules")
+	}
+
+	re, err := regexp.Compile(rule.Pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+	rule.compiledPattern = re
+
+	if rule.Window <= 0 {
+		return errors.New("window is required for rate rules")
+	}
+
+	if rule.MinCount < 1 {
+		return errors.New("min_count must be >= 1 for rate rules")
+	}
+
+	if rule.Slide < 0 {
+		return errors.New("slide must not be negative")
+	}
+	if rule.Slide == 0 {
+		rule.Slide = rule.Window
+	}
+	if rule.Window%rule.Slide != 0 {
+		return fmt.Errorf("window (%s) must be a multiple of slide (%s)", rule.Window, rule.Slide)
+	}
+
+	return nil
+}
+
+func validateBaselineRule(rule *RuleConfig) error {
+	if rule.Pattern == "" {
+		return errors.New("pattern is required for baseline rules")
+	}
+
+	re, err := regexp.Compile(rule.Pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+	rule.compiledPattern = re
+
+	if rule.BaselineFile == "" {
+		return errors.New("baseline_file is required for baseline rules")
+	}
+
+	if rule.MinFraction < 0 || rule.MinFraction > 1 {
+		return fmt.Errorf("min_fraction must be between 0 and 1, got %g", rule.MinFraction)
+	}
+	if rule.MaxDeviations < 0 {
+		return errors.New("max_deviations must not be negative")
+	}
+	if rule.MinFraction == 0 && rule.MaxDeviations == 0 {
+		return errors.New("min_fraction or max_deviations is required for baseline rules")
+	}
+
+	return resolveTimezone(rule)
+}
+
+func validateSourceSilenceRule(rule *RuleConfig) error {
+	if rule.MaxSilence <= 0 {
+		return errors.New("max_silence is required for source_silence rules")
+	}
+
+	if rule.GroupByLabel != "" && !rule.sourceLabels.Defines(rule.GroupByLabel) {
+		return fmt.Errorf("group_by_label %q is not set by any source_labels entry", rule.GroupByLabel)
+	}
+
+	return nil
+}
+
+func validateOrderingRule(rule *RuleConfig) error {
+	if rule.BeforePattern == "" || rule.AfterPattern == "" {
+		return errors.New("before_pattern and after_pattern are required for ordering rules")
+	}
+
+	re, err := regexp.Compile(rule.BeforePattern)
+	if err != nil {
+		return fmt.Errorf("invalid before_pattern: %w", err)
+	}
+	rule.compiledBeforePattern = re
+
+	re, err = regexp.Compile(rule.AfterPattern)
+	if err != nil {
+		return fmt.Errorf("invalid after_pattern: %w", err)
+	}
+	rule.compiledAfterPattern = re
+
+	if rule.Window < 0 {
+		return errors.New("window must not be negative")
+	}
+
+	// Correlation is optional: without it the order is checked across all lines
+	if len(rule.CorrelationGroups) > 0 && rule.CorrelationField > 0 {
+		return errors.New("correlation_field and correlation_groups cannot both be set")
+	}
+
+	if rule.CorrelationField > 0 || len(rule.CorrelationGroups) > 0 ||
+		len(rule.BeforeCorrelationGroups) > 0 || len(rule.AfterCorrelationGroups) > 0 {
+		if rule.beforeKey, err = resolveCorrelationKey(rule, "before_pattern",
+			rule.compiledBeforePattern, rule.BeforeCorrelationGroups); err != nil {
+			return err
+		}
+		if rule.afterKey, err = resolveCorrelationKey(rule, "after_pattern",
+			rule.compiledAfterPattern, rule.AfterCorrelationGroups); err != nil {
+			return err
+		}
+		if err := matchCorrelationKeys("before_pattern", rule.beforeKey, "after_pattern", rule.afterKey); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateReconciliationRule(rule *RuleConfig) error {
+	if rule.LeftPattern == "" || rule.RightPattern == "" {
+		return errors.New("left_pattern and right_pattern are required for reconciliation rules")
+	}
+
+	left, err := regexp.Compile(rule.LeftPattern)
+	if err != nil {
+		return fmt.Errorf("invalid left_pattern: %w", err)
+	}
+	rule.compiledLeftPattern = left
+
+	right, err := regexp.Compile(rule.RightPattern)
+	if err != nil {
+		return fmt.Errorf("invalid right_pattern: %w", err)
+	}
+	rule.compiledRightPattern = right
+
+	if rule.Window <= 0 {
+		return errors.New("window is required for reconciliation rules")
+	}
+
+	if rule.MaxDifference < 0 {
+		return errors.New("max_difference must not be negative")
+	}
+	if rule.MaxRatio != 0 && rule.MaxRatio < 1 {
+		return fmt.Errorf("max_ratio must be >= 1, got %g", rule.MaxRatio)
+	}
+
+	if rule.GroupBy != "" {
+		if rule.groupIndex, err = resolveGroup(left, "left_pattern", rule.GroupBy); err != nil {
+			return err
+		}
+		if rule.rightGroupIndex, err = resolveGroup(right, "right_pattern", rule.GroupBy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// requireCorrelation checks that a sequence rule sets either correlation_field
+// or correlation groups, but not both at the rule level.
+func requireCorrelation(rule *RuleConfig) error {
+	if len(rule.CorrelationGroups) > 0 && rule.CorrelationField > 0 {
+		return errors.New("correlation_field and correlation_groups cannot both be set")
+	}
+
+	if rule.CorrelationField > 0 || len(rule.CorrelationGroups) > 0 ||
+		len(rule.StartCorrelationGroups) > 0 || len(rule.EndCorrelationGroups) > 0 {
+		return nil
+	}
+	for _, step := range rule.Steps {
+		if len(step.CorrelationGroups) > 0 {
+			return nil
 		}
-		if rule.compiledExpectedPattern.NumSubexp() < rule.CorrelationField {
-			return fmt.Errorf("expected_pattern has only %d capture groups, but correlation_field is %d",
-				rule.compiledExpectedPattern.NumSubexp(), rule.CorrelationField)
+	}
+
+	return errors.New("correlation_field must be >= 1 (capture group index), or correlation_groups must be set")
+}
+
+// resolveCorrelationKey resolves the correlation key for one pattern from its
+// own groups if given, otherwise from the rule's correlation_groups or
+// correlation_field. Every named group must exist in the pattern.
+func resolveCorrelationKey(rule *RuleConfig, field string, re *regexp.Regexp, groups []string) (CorrelationKey, error) {
+	if len(groups) == 0 {
+		groups = rule.CorrelationGroups
+	}
+
+	if len(groups) > 0 {
+		key := make(CorrelationKey, 0, len(groups))
+		for _, name := range groups {
+			idx := re.SubexpIndex(name)
+			if idx < 0 {
+				return nil, fmt.Errorf("%s has no capture group named %q", field, name)
+			}
+			key = append(key, idx)
 		}
+		return key, nil
 	}
 
+	if rule.CorrelationField < 1 {
+		return nil, fmt.Errorf("%s has no correlation groups and correlation_field is not set", field)
+	}
+
+	if re.NumSubexp() < rule.CorrelationField {
+		return nil, fmt.Errorf("%s has only %d capture groups, but correlation_field is %d",
+			field, re.NumSubexp(), rule.CorrelationField)
+	}
+
+	return CorrelationKey{rule.CorrelationField}, nil
+}
+
+// resolveGroup resolves a capture group given by name or 1-based index.
+func resolveGroup(re *regexp.Regexp, field, group string) (int, error) {
+	if idx, err := strconv.Atoi(group); err == nil {
+		if idx < 1 || idx > re.NumSubexp() {
+			return 0, fmt.Errorf("%s has %d capture groups, so group %d does not exist",
+				field, re.NumSubexp(), idx)
+		}
+		return idx, nil
+	}
+
+	idx := re.SubexpIndex(group)
+	if idx < 0 {
+		return 0, fmt.Errorf("%s has no capture group named %q", field, group)
+	}
+	return idx, nil
+}
+
+// matchCorrelationKeys checks that two patterns build keys from the same number of groups.
+func matchCorrelationKeys(fieldA string, a CorrelationKey, fieldB string, b CorrelationKey) error {
+	if len(a) != len(b) {
+		return fmt.Errorf("%s builds its correlation key from %d group(s), but %s uses %d",
+			fieldA, len(a), fieldB, len(b))
+	}
 	return nil
 }
 
@@ -4,6 +4,8 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -182,6 +184,143 @@ func TestValidate_SequenceRule_InvalidCorrelationField(t *testing.T) {
 	}
 }
 
+func TestValidate_SequenceRule_Steps(t *testing.T) {
+	cfg := &Config{
+		LogSources: []string{"/var/log/*.log"},
+		TimestampFormat: TimestampConfig{
+			Pattern: `^\[(\d{4})\]`,
+			Layout:  "2006",
+		},
+		Rules: []RuleConfig{{
+			Name: "order-flow",
+			Type: "sequence",
+			Steps: []SequenceStep{
+				{Name: "created", Pattern: `ORDER_CREATED id=(\w+)`},
+				{Pattern: `PAYMENT_AUTHORIZED id=(\w+)`, Timeout: 10 * time.Second},
+				{Name: "shipped", Pattern: `SHIPPED id=(\w+)`},
+			},
+			CorrelationField: 1,
+		}},
+	}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	rule := cfg.Rules[0]
+	if rule.Steps[1].Name != "step2" {
+		t.Errorf("Steps[1].Name = %q, want default %q", rule.Steps[1].Name, "step2")
+	}
+	for i, step := range rule.Steps {
+		if step.CompiledPattern() == nil {
+			t.Errorf("Steps[%d].CompiledPattern() is nil", i)
+		}
+	}
+	if rule.Timeout != DefaultTimeout {
+		t.Errorf("Timeout = %v, want default %v", rule.Timeout, DefaultTimeout)
+	}
+}
+
+func TestValidate_SequenceRule_StepsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		rule RuleConfig
+	}{
+		{
+			name: "single step",
+			rule: RuleConfig{
+				Steps:            []SequenceStep{{Pattern: `A id=(\w+)`}},
+				CorrelationField: 1,
+			},
+		},
+		{
+			name: "combined with start_pattern",
+			rule: RuleConfig{
+				StartPattern:     `A id=(\w+)`,
+				Steps:            []SequenceStep{{Pattern: `A id=(\w+)`}, {Pattern: `B id=(\w+)`}},
+				CorrelationField: 1,
+			},
+		},
+		{
+			name: "duplicate names",
+			rule: RuleConfig{
+				Steps:            []SequenceStep{{Name: "a", Pattern: `A id=(\w+)`}, {Name: "a", Pattern: `B id=(\w+)`}},
+				CorrelationField: 1,
+			},
+		},
+		{
+			name: "missing capture group",
+			rule: RuleConfig{
+				Steps:            []SequenceStep{{Pattern: `A id=(\w+)`}, {Pattern: `B`}},
+				CorrelationField: 1,
+			},
+		},
+		{
+			name: "missing pattern",
+			rule: RuleConfig{
+				Steps:            []SequenceStep{{Pattern: `A id=(\w+)`}, {Name: "b"}},
+				CorrelationField: 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := tt.rule
+			rule.Name = "test"
+			rule.Type = "sequence"
+			cfg := &Config{
+				LogSources:      []string{"/var/log/*.log"},
+				TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+				Rules:           []RuleConfig{rule},
+			}
+			if err := Validate(cfg); err == nil {
+				t.Error("Validate() expected error")
+			}
+		})
+	}
+}
+
+func TestValidate_SequenceRule_DuplicateStarts(t *testing.T) {
+	newConfig := func(policy DuplicateStartPolicy, maxRetries int) *Config {
+		return &Config{
+			LogSources:      []string{"/var/log/*.log"},
+			TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+			Rules: []RuleConfig{{
+				Name:             "test",
+				Type:             "sequence",
+				StartPattern:     `START id=(\w+)`,
+				EndPattern:       `END id=(\w+)`,
+				CorrelationField: 1,
+				DuplicateStarts:  policy,
+				MaxRetries:       maxRetries,
+			}},
+		}
+	}
+
+	cfg := newConfig("", 0)
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if cfg.Rules[0].DuplicateStarts != DuplicateStartsKeepLast {
+		t.Errorf("DuplicateStarts = %q, want default %q", cfg.Rules[0].DuplicateStarts, DuplicateStartsKeepLast)
+	}
+
+	for _, policy := range []DuplicateStartPolicy{
+		DuplicateStartsKeepLast, DuplicateStartsKeepFirst, DuplicateStartsReport, DuplicateStartsMultiset,
+	} {
+		if err := Validate(newConfig(policy, 3)); err != nil {
+			t.Errorf("Validate(%q) error = %v", policy, err)
+		}
+	}
+
+	if err := Validate(newConfig("sometimes", 0)); err == nil {
+		t.Error("Validate() expected error for invalid duplicate_starts")
+	}
+	if err := Validate(newConfig("", -1)); err == nil {
+		t.Error("Validate() expected error for negative max_retries")
+	}
+}
+
 func TestValidate_PeriodicRule_Valid(t *testing.T) {
 	cfg := &Config{
 		LogSources: []string{"/var/log/*.log"},
@@ -221,6 +360,535 @@ func TestValidate_PeriodicRule_MissingPattern(t *testing.T) {
 	}
 }
 
+func TestValidate_SequenceRule_CorrelationGroups(t *testing.T) {
+	cfg := &Config{
+		LogSources:      []string{"/var/log/*.log"},
+		TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+		Rules: []RuleConfig{{
+			Name:              "test",
+			Type:              "sequence",
+			StartPattern:      `START tenant=(?P<tenant>\w+) order=(?P<order>\w+)`,
+			EndPattern:        `END order=(?P<order>\w+) tenant=(?P<tenant>\w+)`,
+			CorrelationGroups: []string{"tenant", "order"},
+		}},
+	}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	rule := &cfg.Rules[0]
+	if got := rule.StartCorrelationKey(); len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("StartCorrelationKey() = %v, want [1 2]", got)
+	}
+	if got := rule.EndCorrelationKey(); len(got) != 2 || got[0] != 2 || got[1] != 1 {
+		t.Errorf("EndCorrelationKey() = %v, want [2 1]", got)
+	}
+}
+
+func TestValidate_CorrelationGroupsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		rule RuleConfig
+		want string
+	}{
+		{
+			name: "name missing from end pattern",
+			rule: RuleConfig{
+				Type:              "sequence",
+				StartPattern:      `START id=(?P<id>\w+)`,
+				EndPattern:        `END id=(\w+)`,
+				CorrelationGroups: []string{"id"},
+			},
+			want: `end_pattern has no capture group named "id"`,
+		},
+		{
+			name: "field and groups",
+			rule: RuleConfig{
+				Type:              "sequence",
+				StartPattern:      `START id=(?P<id>\w+)`,
+				EndPattern:        `END id=(?P<id>\w+)`,
+				CorrelationField:  1,
+				CorrelationGroups: []string{"id"},
+			},
+			want: "cannot both be set",
+		},
+		{
+			name: "mismatched key sizes",
+			rule: RuleConfig{
+				Type:                   "sequence",
+				StartPattern:           `START t=(?P<t>\w+) id=(?P<id>\w+)`,
+				EndPattern:             `END id=(?P<id>\w+)`,
+				StartCorrelationGroups: []string{"t", "id"},
+				EndCorrelationGroups:   []string{"id"},
+			},
+			want: "correlation key",
+		},
+		{
+			name: "override without fallback",
+			rule: RuleConfig{
+				Type:                   "sequence",
+				StartPattern:           `START id=(?P<id>\w+)`,
+				EndPattern:             `END id=(\w+)`,
+				StartCorrelationGroups: []string{"id"},
+			},
+			want: "end_pattern has no correlation groups",
+		},
+		{
+			name: "step name missing",
+			rule: RuleConfig{
+				Type: "sequence",
+				Steps: []SequenceStep{
+					{Pattern: `A id=(?P<id>\w+)`},
+					{Pattern: `B ref=(?P<ref>\w+)`},
+				},
+				CorrelationGroups: []string{"id"},
+			},
+			want: `steps[1] (step2): pattern has no capture group named "id"`,
+		},
+		{
+			name: "conditional name missing from expected",
+			rule: RuleConfig{
+				Type:              "conditional",
+				TriggerPattern:    `ERROR id=(?P<id>\w+)`,
+				ExpectedPattern:   `ALERT ref=(?P<ref>\w+)`,
+				CorrelationGroups: []string{"id"},
+			},
+			want: `expected_pattern has no capture group named "id"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := tt.rule
+			rule.Name = "test"
+			cfg := &Config{
+				LogSources:      []string{"/var/log/*.log"},
+				TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+				Rules:           []RuleConfig{rule},
+			}
+			err := Validate(cfg)
+			if err == nil {
+				t.Fatal("Validate() expected error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_ConditionalRule_CorrelationGroups(t *testing.T) {
+	cfg := &Config{
+		LogSources:      []string{"/var/log/*.log"},
+		TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+		Rules: []RuleConfig{{
+			Name:                      "test",
+			Type:                      "conditional",
+			TriggerPattern:            `ERROR code=(\d+) req=(?P<req>\w+)`,
+			ExpectedPattern:           `ALERT for=(?P<request>\w+)`,
+			TriggerCorrelationGroups:  []string{"req"},
+			ExpectedCorrelationGroups: []string{"request"},
+		}},
+	}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	rule := &cfg.Rules[0]
+	if got := rule.TriggerCorrelationKey(); len(got) != 1 || got[0] != 2 {
+		t.Errorf("TriggerCorrelationKey() = %v, want [2]", got)
+	}
+	if got := rule.ExpectedCorrelationKey(); len(got) != 1 || got[0] != 1 {
+		t.Errorf("ExpectedCorrelationKey() = %v, want [1]", got)
+	}
+}
+
+func TestCorrelationKey_Extract(t *testing.T) {
+	matches := []string{"full", "acme", "o1"}
+
+	if id, ok := (CorrelationKey{2}).Extract(matches); !ok || id != "o1" {
+		t.Errorf("Extract() = %q, %v, want o1, true", id, ok)
+	}
+	if id, ok := (CorrelationKey{1, 2}).Extract(matches); !ok || id != "acme|o1" {
+		t.Errorf("Extract() = %q, %v, want acme|o1, true", id, ok)
+	}
+	if _, ok := (CorrelationKey{3}).Extract(matches); ok {
+		t.Error("Extract() should fail for a missing group")
+	}
+}
+
+func TestValidate_PeriodicRule_Schedule(t *testing.T) {
+	newConfig := func(rule RuleConfig) *Config {
+		rule.Name = "test"
+		rule.Type = "periodic"
+		rule.Pattern = `SYNC done`
+		return &Config{
+			LogSources:      []string{"/var/log/*.log"},
+			TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+			Rules:           []RuleConfig{rule},
+		}
+	}
+
+	cfg := newConfig(RuleConfig{Schedule: "0 2 * * *", Timezone: "Europe/Berlin"})
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	rule := &cfg.Rules[0]
+	if rule.CompiledSchedule() == nil {
+		t.Error("CompiledSchedule() = nil")
+	}
+	if rule.Tolerance != DefaultTolerance {
+		t.Errorf("Tolerance = %v, want default %v", rule.Tolerance, DefaultTolerance)
+	}
+	if rule.MaxGap != 0 {
+		t.Errorf("MaxGap = %v, want 0 for scheduled rules", rule.MaxGap)
+	}
+	if rule.Location().String() != "Europe/Berlin" {
+		t.Errorf("Location() = %v, want Europe/Berlin", rule.Location())
+	}
+
+	errorCases := map[string]RuleConfig{
+		"invalid schedule":       {Schedule: "0 25 * * *"},
+		"invalid timezone":       {Schedule: "0 2 * * *", Timezone: "Mars/Olympus"},
+		"with max_gap":           {Schedule: "0 2 * * *", MaxGap: time.Hour},
+		"negative tolerance":     {Schedule: "0 2 * * *", Tolerance: -time.Minute},
+		"tolerance without cron": {Tolerance: time.Minute},
+	}
+	for name, rule := range errorCases {
+		t.Run(name, func(t *testing.T) {
+			if err := Validate(newConfig(rule)); err == nil {
+				t.Error("Validate() expected error")
+			}
+		})
+	}
+}
+
+func TestValidate_PeriodicRule_GroupBy(t *testing.T) {
+	tests := []struct {
+		groupBy string
+		want    int
+		wantErr bool
+	}{
+		{groupBy: "host", want: 2},
+		{groupBy: "1", want: 1},
+		{groupBy: "2", want: 2},
+		{groupBy: "3", wantErr: true},
+		{groupBy: "0", wantErr: true},
+		{groupBy: "region", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.groupBy, func(t *testing.T) {
+			cfg := &Config{
+				LogSources:      []string{"/var/log/*.log"},
+				TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+				Rules: []RuleConfig{{
+					Name:    "test",
+					Type:    "periodic",
+					Pattern: `HEARTBEAT svc=(\w+) host=(?P<host>\S+)`,
+					GroupBy: tt.groupBy,
+				}},
+			}
+			err := Validate(cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Validate() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if got := cfg.Rules[0].GroupIndex(); got != tt.want {
+				t.Errorf("GroupIndex() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_RateRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		window    time.Duration
+		slide     time.Duration
+		minCount  int
+		wantSlide time.Duration
+		errMsg    string
+	}{
+		{name: "tumbling", window: 5 * time.Minute, minCount: 500, wantSlide: 5 * time.Minute},
+		{name: "sliding", window: 5 * time.Minute, slide: time.Minute, minCount: 500, wantSlide: time.Minute},
+		{name: "no window", minCount: 500, errMsg: "window is required"},
+		{name: "no min_count", window: 5 * time.Minute, errMsg: "min_count must be >= 1"},
+		{name: "negative slide", window: 5 * time.Minute, slide: -time.Minute, minCount: 1, errMsg: "slide must not be negative"},
+		{name: "uneven slide", window: 5 * time.Minute, slide: 2 * time.Minute, minCount: 1, errMsg: "multiple of slide"},
+		{name: "slide over window", window: time.Minute, slide: 2 * time.Minute, minCount: 1, errMsg: "multiple of slide"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				LogSources:      []string{"/var/log/*.log"},
+				TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+				Rules: []RuleConfig{{
+					Name:     "test",
+					Type:     "rate",
+					Pattern:  `ORDER_ACCEPTED`,
+					Window:   tt.window,
+					Slide:    tt.slide,
+					MinCount: tt.minCount,
+				}},
+			}
+			err := Validate(cfg)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("Validate() error = %v, want %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if cfg.Rules[0].Slide != tt.wantSlide {
+				t.Errorf("Slide = %v, want %v", cfg.Rules[0].Slide, tt.wantSlide)
+			}
+			if cfg.Rules[0].CompiledPattern() == nil {
+				t.Error("CompiledPattern() = nil")
+			}
+		})
+	}
+}
+
+func TestValidate_BaselineRule(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   RuleConfig
+		errMsg string
+	}{
+		{
+			name: "valid",
+			rule: RuleConfig{
+				Pattern: `ORDER_ACCEPTED`, BaselineFile: "orders.json", MinFraction: 0.3, Timezone: "America/New_York",
+			},
+		},
+		{
+			name:   "no baseline_file",
+			rule:   RuleConfig{Pattern: `ORDER_ACCEPTED`, MinFraction: 0.3},
+			errMsg: "baseline_file is required",
+		},
+		{
+			name:   "no threshold",
+			rule:   RuleConfig{Pattern: `ORDER_ACCEPTED`, BaselineFile: "orders.json"},
+			errMsg: "min_fraction or max_deviations is required",
+		},
+		{
+			name:   "fraction over one",
+			rule:   RuleConfig{Pattern: `ORDER_ACCEPTED`, BaselineFile: "orders.json", MinFraction: 30},
+			errMsg: "min_fraction must be between 0 and 1",
+		},
+		{
+			name:   "negative deviations",
+			rule:   RuleConfig{Pattern: `ORDER_ACCEPTED`, BaselineFile: "orders.json", MaxDeviations: -2},
+			errMsg: "max_deviations must not be negative",
+		},
+		{
+			name: "invalid timezone",
+			rule: RuleConfig{
+				Pattern: `ORDER_ACCEPTED`, BaselineFile: "orders.json", MaxDeviations: 3, Timezone: "Mars/Olympus",
+			},
+			errMsg: "invalid timezone",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := tt.rule
+			rule.Name = "test"
+			rule.Type = "baseline"
+			cfg := &Config{
+				LogSources:      []string{"/var/log/*.log"},
+				TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+				Rules:           []RuleConfig{rule},
+			}
+			err := Validate(cfg)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("Validate() error = %v, want %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if cfg.Rules[0].CompiledPattern() == nil {
+				t.Error("CompiledPattern() = nil")
+			}
+			if cfg.Rules[0].Location().String() != "America/New_York" {
+				t.Errorf("Location() = %v, want America/New_York", cfg.Rules[0].Location())
+			}
+		})
+	}
+}
+
+func TestValidate_ReconciliationRule(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   RuleConfig
+		errMsg string
+	}{
+		{
+			name: "valid",
+			rule: RuleConfig{
+				LeftPattern:  `PRODUCED queue=(?P<queue>\w+)`,
+				RightPattern: `CONSUMED (\d+) queue=(?P<queue>\w+)`,
+				Window:       time.Hour,
+				GroupBy:      "queue",
+				MaxRatio:     1.05,
+			},
+		},
+		{
+			name:   "missing right_pattern",
+			rule:   RuleConfig{LeftPattern: `PRODUCED`, Window: time.Hour},
+			errMsg: "left_pattern and right_pattern are required",
+		},
+		{
+			name:   "no window",
+			rule:   RuleConfig{LeftPattern: `PRODUCED`, RightPattern: `CONSUMED`},
+			errMsg: "window is required",
+		},
+		{
+			name: "negative difference",
+			rule: RuleConfig{
+				LeftPattern: `PRODUCED`, RightPattern: `CONSUMED`, Window: time.Hour, MaxDifference: -1,
+			},
+			errMsg: "max_difference must not be negative",
+		},
+		{
+			name: "ratio below one",
+			rule: RuleConfig{
+				LeftPattern: `PRODUCED`, RightPattern: `CONSUMED`, Window: time.Hour, MaxRatio: 0.9,
+			},
+			errMsg: "max_ratio must be >= 1",
+		},
+		{
+			name: "group missing on one side",
+			rule: RuleConfig{
+				LeftPattern:  `PRODUCED queue=(?P<queue>\w+)`,
+				RightPattern: `CONSUMED`,
+				Window:       time.Hour,
+				GroupBy:      "queue",
+			},
+			errMsg: `right_pattern has no capture group named "queue"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := tt.rule
+			rule.Name = "test"
+			rule.Type = "reconciliation"
+			cfg := &Config{
+				LogSources:      []string{"/var/log/*.log"},
+				TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+				Rules:           []RuleConfig{rule},
+			}
+			err := Validate(cfg)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("Validate() error = %v, want %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			r := cfg.Rules[0]
+			if r.CompiledLeftPattern() == nil || r.CompiledRightPattern() == nil {
+				t.Error("patterns not compiled")
+			}
+			if r.GroupIndex() != 1 || r.RightGroupIndex() != 2 {
+				t.Errorf("group indexes = %d, %d, want 1, 2", r.GroupIndex(), r.RightGroupIndex())
+			}
+		})
+	}
+}
+
+func TestValidate_OrderingRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    RuleConfig
+		wantKey CorrelationKey
+		errMsg  string
+	}{
+		{
+			name: "uncorrelated",
+			rule: RuleConfig{BeforePattern: `.`, AfterPattern: `SHUTDOWN_COMPLETE`, PerSource: true},
+		},
+		{
+			name: "correlated",
+			rule: RuleConfig{
+				BeforePattern:     `PREPARE txn=(?P<txn>\w+)`,
+				AfterPattern:      `COMMIT (\w+) txn=(?P<txn>\w+)`,
+				CorrelationGroups: []string{"txn"},
+			},
+			wantKey: CorrelationKey{2},
+		},
+		{
+			name:   "missing after_pattern",
+			rule:   RuleConfig{BeforePattern: `PREPARE`},
+			errMsg: "before_pattern and after_pattern are required",
+		},
+		{
+			name:   "invalid before_pattern",
+			rule:   RuleConfig{BeforePattern: `PREPARE (`, AfterPattern: `COMMIT`},
+			errMsg: "invalid before_pattern",
+		},
+		{
+			name:   "negative window",
+			rule:   RuleConfig{BeforePattern: `PREPARE`, AfterPattern: `COMMIT`, Window: -time.Minute},
+			errMsg: "window must not be negative",
+		},
+		{
+			name: "group missing",
+			rule: RuleConfig{
+				BeforePattern:     `PREPARE txn=(?P<txn>\w+)`,
+				AfterPattern:      `COMMIT`,
+				CorrelationGroups: []string{"txn"},
+			},
+			errMsg: `after_pattern has no capture group named "txn"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := tt.rule
+			rule.Name = "test"
+			rule.Type = "ordering"
+			cfg := &Config{
+				LogSources:      []string{"/var/log/*.log"},
+				TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+				Rules:           []RuleConfig{rule},
+			}
+			err := Validate(cfg)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("Validate() error = %v, want %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			r := cfg.Rules[0]
+			if r.CompiledBeforePattern() == nil || r.CompiledAfterPattern() == nil {
+				t.Error("patterns not compiled")
+			}
+			if !reflect.DeepEqual(r.AfterCorrelationKey(), tt.wantKey) {
+				t.Errorf("AfterCorrelationKey() = %v, want %v", r.AfterCorrelationKey(), tt.wantKey)
+			}
+		})
+	}
+}
+
 func TestValidate_ConditionalRule_Valid(t *testing.T) {
 	cfg := &Config{
 		LogSources: []string{"/var/log/*.log"},
@@ -261,6 +929,156 @@ func TestValidate_ConditionalRule_MissingTrigger(t *testing.T) {
 	}
 }
 
+func TestValidate_ConditionalRule_Forbidden(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   RuleConfig
+		errMsg string
+	}{
+		{
+			name: "valid",
+			rule: RuleConfig{
+				TriggerPattern:    `PAYMENT_CAPTURED id=(?P<id>\w+)`,
+				ForbiddenPattern:  `PAYMENT_CAPTURED id=(?P<id>\w+)`,
+				CorrelationGroups: []string{"id"},
+			},
+		},
+		{
+			name: "both patterns",
+			rule: RuleConfig{
+				TriggerPattern:   `DEPLOY_START`,
+				ExpectedPattern:  `DEPLOY_DONE`,
+				ForbiddenPattern: `ROLLBACK`,
+			},
+			errMsg: "cannot both be set",
+		},
+		{
+			name:   "neither pattern",
+			rule:   RuleConfig{TriggerPattern: `DEPLOY_START`},
+			errMsg: "expected_pattern, forbidden_pattern, or preceded_by is required",
+		},
+		{
+			name: "missing correlation group",
+			rule: RuleConfig{
+				TriggerPattern:    `DEPLOY_START id=(?P<id>\w+)`,
+				ForbiddenPattern:  `ROLLBACK`,
+				CorrelationGroups: []string{"id"},
+			},
+			errMsg: `forbidden_pattern has no capture group named "id"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := tt.rule
+			rule.Name = "test"
+			rule.Type = "conditional"
+			cfg := &Config{
+				LogSources:      []string{"/var/log/*.log"},
+				TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+				Rules:           []RuleConfig{rule},
+			}
+			err := Validate(cfg)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("Validate() error = %v, want %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if cfg.Rules[0].CompiledForbiddenPattern() == nil {
+				t.Error("CompiledForbiddenPattern() = nil")
+			}
+			if len(cfg.Rules[0].ForbiddenCorrelationKey()) != 1 {
+				t.Errorf("ForbiddenCorrelationKey() = %v, want one group", cfg.Rules[0].ForbiddenCorrelationKey())
+			}
+		})
+	}
+}
+
+func TestValidate_ConditionalRule_PrecededBy(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   RuleConfig
+		errMsg string
+	}{
+		{
+			name: "valid",
+			rule: RuleConfig{
+				TriggerPattern:    `REFUND_ISSUED order=(?P<order>\w+)`,
+				PrecededBy:        `REFUND_APPROVED order=(?P<order>\w+)`,
+				CorrelationGroups: []string{"order"},
+			},
+		},
+		{
+			name: "with expected_pattern",
+			rule: RuleConfig{
+				TriggerPattern:  `REFUND_ISSUED`,
+				ExpectedPattern: `REFUND_SENT`,
+				PrecededBy:      `REFUND_APPROVED`,
+			},
+			errMsg: "expected_pattern and preceded_by cannot both be set",
+		},
+		{
+			name: "with forbidden_pattern",
+			rule: RuleConfig{
+				TriggerPattern:   `REFUND_ISSUED`,
+				ForbiddenPattern: `REFUND_ISSUED`,
+				PrecededBy:       `REFUND_APPROVED`,
+			},
+			errMsg: "forbidden_pattern and preceded_by cannot both be set",
+		},
+		{
+			name: "invalid pattern",
+			rule: RuleConfig{
+				TriggerPattern: `REFUND_ISSUED`,
+				PrecededBy:     `REFUND_APPROVED (`,
+			},
+			errMsg: "preceded_by",
+		},
+		{
+			name: "missing correlation group",
+			rule: RuleConfig{
+				TriggerPattern:    `REFUND_ISSUED order=(?P<order>\w+)`,
+				PrecededBy:        `REFUND_APPROVED`,
+				CorrelationGroups: []string{"order"},
+			},
+			errMsg: `preceded_by has no capture group named "order"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := tt.rule
+			rule.Name = "test"
+			rule.Type = "conditional"
+			cfg := &Config{
+				LogSources:      []string{"/var/log/*.log"},
+				TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+				Rules:           []RuleConfig{rule},
+			}
+			err := Validate(cfg)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("Validate() error = %v, want %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if cfg.Rules[0].CompiledPrecededBy() == nil {
+				t.Error("CompiledPrecededBy() = nil")
+			}
+			if len(cfg.Rules[0].PrecededByCorrelationKey()) != 1 {
+				t.Errorf("PrecededByCorrelationKey() = %v, want one group", cfg.Rules[0].PrecededByCorrelationKey())
+			}
+		})
+	}
+}
+
 func TestValidate_InvalidRuleType(t *testing.T) {
 	cfg := &Config{
 		LogSources: []string{"/var/log/*.log"},
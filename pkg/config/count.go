@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CountConfig sets how many expected events must follow each trigger of a
+// conditional rule. In YAML it is either a number, meaning exactly that many,
+// or a mapping with min and max bounds or from_group.
+type CountConfig struct {
+	// Min is the fewest expected events allowed.
+	Min int `yaml:"min,omitempty"`
+
+	// Max is the most expected events allowed; zero means no upper bound.
+	Max int `yaml:"max,omitempty"`
+
+	// FromGroup takes the exact count from a capture group of the trigger
+	// pattern, given as a group name or 1-based index (e.g. a batch size).
+	FromGroup string `yaml:"from_group,omitempty"`
+
+	// Resolved from_group capture group index (populated during validation)
+	groupIndex int
+}
+
+// UnmarshalYAML accepts a plain number as shorthand for an exact count.
+func (c *CountConfig) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		var n int
+		if err := value.Decode(&n); err != nil {
+			return fmt.Errorf("expected_count must be a number or a mapping: %w", err)
+		}
+		c.Min, c.Max = n, n
+		return nil
+	}
+
+	type plain CountConfig
+	return value.Decode((*plain)(c))
+}
+
+// GroupIndex returns the trigger capture group the count is taken from, 0 if
+// the count is fixed.
+func (c *CountConfig) GroupIndex() int {
+	return c.groupIndex
+}
+
+// validateExpectedCount checks a conditional rule's expected_count once its
+// patterns and correlation keys are resolved.
+func validateExpectedCount(rule *RuleConfig) error {
+	c := rule.ExpectedCount
+	if c == nil {
+		return nil
+	}
+
+	if rule.ExpectedPattern == "" {
+		return errors.New("expected_count requires expected_pattern")
+	}
+	if len(rule.triggerKey) == 0 {
+		return errors.New("expected_count requires correlation")
+	}
+
+	if c.FromGroup != "" {
+		if c.Min != 0 || c.Max != 0 {
+			return errors.New("expected_count from_group cannot be combined with min or max")
+		}
+		idx, err := resolveGroup(rule.compiledTriggerPattern, "trigger_pattern", c.FromGroup)
+		if err != nil {
+			return fmt.Errorf("expected_count: %w", err)
+		}
+		c.groupIndex = idx
+		return nil
+	}
+
+	if c.Min < 0 || c.Max < 0 {
+		return errors.New("expected_count must not be negative")
+	}
+	if c.Min == 0 && c.Max == 0 {
+		return errors.New("expected_count must set a count, min, max, or from_group")
+	}
+	if c.Max > 0 && c.Max < c.Min {
+		return fmt.Errorf("expected_count max (%d) must be >= min (%d)", c.Max, c.Min)
+	}
+
+	return nil
+}
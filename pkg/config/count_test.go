@@ -0,0 +1,123 @@
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLoad_ExpectedCount(t *testing.T) {
+	tests := []struct {
+		name  string
+		count string
+		want  CountConfig
+	}{
+		{"exact", "expected_count: 3", CountConfig{Min: 3, Max: 3}},
+		{"bounds", "expected_count: {min: 2, max: 5}", CountConfig{Min: 2, Max: 5}},
+		{"at least", "expected_count: {min: 3}", CountConfig{Min: 3}},
+		{"from group", "expected_count: {from_group: size}", CountConfig{FromGroup: "size", groupIndex: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTempFile(t, "config.yaml", countConfig(tt.count))
+			cfg, err := Load(context.Background(), path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if got := *cfg.Rules[0].ExpectedCount; got != tt.want {
+				t.Errorf("ExpectedCount = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_ExpectedCountErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		count  string
+		errMsg string
+	}{
+		{"not a number", "expected_count: many", "must be a number or a mapping"},
+		{"zero", "expected_count: 0", "must set a count"},
+		{"negative", "expected_count: {min: -1}", "must not be negative"},
+		{"max below min", "expected_count: {min: 5, max: 2}", "max (2) must be >= min (5)"},
+		{"from group with bounds", "expected_count: {from_group: size, min: 1}", "cannot be combined"},
+		{"unknown group", "expected_count: {from_group: total}", `no capture group named "total"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTempFile(t, "config.yaml", countConfig(tt.count))
+			_, err := Load(context.Background(), path)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Load() error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestValidate_ExpectedCountErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   RuleConfig
+		errMsg string
+	}{
+		{
+			name: "uncorrelated",
+			rule: RuleConfig{
+				TriggerPattern:  `BATCH_START`,
+				ExpectedPattern: `ITEM_DONE`,
+				ExpectedCount:   &CountConfig{Min: 3, Max: 3},
+			},
+			errMsg: "expected_count requires correlation",
+		},
+		{
+			name: "forbidden pattern",
+			rule: RuleConfig{
+				TriggerPattern:   `BATCH_START id=(\w+)`,
+				ForbiddenPattern: `BATCH_FAILED id=(\w+)`,
+				CorrelationField: 1,
+				ExpectedCount:    &CountConfig{Min: 3, Max: 3},
+			},
+			errMsg: "expected_count requires expected_pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := tt.rule
+			rule.Name = "test"
+			rule.Type = "conditional"
+			cfg := &Config{
+				LogSources:      []string{"/var/log/*.log"},
+				TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+				Rules:           []RuleConfig{rule},
+			}
+			err := Validate(cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+// countConfig returns a config with one batch rule using the given
+// expected_count line.
+func countConfig(count string) string {
+	return `
+log_sources:
+  - /var/log/*.log
+timestamp_format:
+  pattern: '^(\S+)'
+  layout: "2006-01-02T15:04:05Z07:00"
+rules:
+  - name: batches
+    type: conditional
+    trigger_pattern: 'BATCH_START id=(?P<id>\w+) size=(?P<size>\d+)'
+    expected_pattern: 'ITEM_DONE batch=(?P<id>\w+)'
+    correlation_groups: [id]
+    timeout: 10m
+    ` + count + `
+`
+}
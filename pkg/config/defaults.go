@@ -9,6 +9,7 @@ import (
 const (
 	DefaultTimeout          = 60 * time.Second
 	DefaultMaxGap           = 5 * time.Minute
+	DefaultTolerance        = 5 * time.Minute
 	DefaultWebhookTimeout   = 10 * time.Second
 	DefaultTimestampPattern = `^\[(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2})\]`
 	DefaultTimestampLayout  = "2006-01-02 15:04:05"
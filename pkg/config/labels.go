@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// SourceLabelConfig attaches labels to the log files matching a glob, so
+// that rules can refer to a service or environment rather than file paths.
+type SourceLabelConfig struct {
+	// Path is a glob matched against log file paths, as in log_sources.
+	Path string `yaml:"path"`
+
+	// Labels are attached to every matching file.
+	Labels map[string]string `yaml:"labels"`
+}
+
+// SourceLabels maps log files to their labels.
+type SourceLabels []SourceLabelConfig
+
+// For returns the labels of a log file, merged from every entry whose path
+// matches it. Later entries override earlier ones. It returns nil if no
+// entry matches.
+func (s SourceLabels) For(path string) map[string]string {
+	var labels map[string]string
+	for _, entry := range s {
+		if ok, _ := filepath.Match(entry.Path, path); !ok {
+			continue
+		}
+		if labels == nil {
+			labels = make(map[string]string, len(entry.Labels))
+		}
+		for k, v := range entry.Labels {
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
+// Defines returns true if any entry sets the label key.
+func (s SourceLabels) Defines(key string) bool {
+	for _, entry := range s {
+		if _, ok := entry.Labels[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSourceLabels checks each source_labels entry.
+func validateSourceLabels(labels SourceLabels) error {
+	for i, entry := range labels {
+		if entry.Path == "" {
+			return fmt.Errorf("source_labels[%d]: path is required", i)
+		}
+		if _, err := filepath.Match(entry.Path, ""); err != nil {
+			return fmt.Errorf("source_labels[%d]: invalid path %q: %w", i, entry.Path, err)
+		}
+		if len(entry.Labels) == 0 {
+			return fmt.Errorf("source_labels[%d]: labels are required", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSourceLabels_For(t *testing.T) {
+	labels := SourceLabels{
+		{Path: "/var/log/*.log", Labels: map[string]string{"env": "prod"}},
+		{Path: "/var/log/api-*.log", Labels: map[string]string{"service": "api"}},
+		{Path: "/var/log/api-canary.log", Labels: map[string]string{"env": "canary"}},
+	}
+
+	tests := []struct {
+		path string
+		want map[string]string
+	}{
+		{"/var/log/db.log", map[string]string{"env": "prod"}},
+		{"/var/log/api-1.log", map[string]string{"env": "prod", "service": "api"}},
+		{"/var/log/api-canary.log", map[string]string{"env": "canary", "service": "api"}},
+		{"/tmp/other.log", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := labels.For(tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("For() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_SourceLabels(t *testing.T) {
+	content := `log_sources:
+  - /var/log/*.log
+source_labels:
+  - path: /var/log/api-*.log
+    labels:
+      service: api
+timestamp_format:
+  pattern: '^\[(\d{4})\]'
+  layout: "2006"
+rules:
+  - name: services-alive
+    type: source_silence
+    max_silence: 10m
+    group_by_label: service
+`
+	path := writeTempFile(t, "config.yaml", content)
+	cfg, err := Load(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	rule := cfg.Rules[0]
+	if rule.MaxSilence != 10*time.Minute || rule.GroupByLabel != "service" {
+		t.Errorf("rule = %+v, want max_silence 10m grouped by service", rule)
+	}
+	if got := rule.SourceLabels().For("/var/log/api-2.log")["service"]; got != "api" {
+		t.Errorf("SourceLabels().For() service = %q, want api", got)
+	}
+}
+
+func TestValidate_SourceLabels(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels SourceLabels
+		errMsg string
+	}{
+		{"no path", SourceLabels{{Labels: map[string]string{"a": "b"}}}, "source_labels[0]: path is required"},
+		{"bad glob", SourceLabels{{Path: "/var/log/[", Labels: map[string]string{"a": "b"}}}, "invalid path"},
+		{"no labels", SourceLabels{{Path: "/var/log/*.log"}}, "labels are required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				LogSources:      []string{"/var/log/*.log"},
+				SourceLabels:    tt.labels,
+				TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+				Rules:           []RuleConfig{{Name: "test", Type: "source_silence", MaxSilence: time.Minute}},
+			}
+			err := Validate(cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestValidate_SourceSilenceRule(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   RuleConfig
+		errMsg string
+	}{
+		{name: "valid", rule: RuleConfig{MaxSilence: time.Minute}},
+		{name: "grouped", rule: RuleConfig{MaxSilence: time.Minute, GroupByLabel: "service"}},
+		{name: "no max_silence", rule: RuleConfig{}, errMsg: "max_silence is required"},
+		{
+			name:   "unknown label",
+			rule:   RuleConfig{MaxSilence: time.Minute, GroupByLabel: "team"},
+			errMsg: `group_by_label "team" is not set by any source_labels entry`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := tt.rule
+			rule.Name = "test"
+			rule.Type = "source_silence"
+			cfg := &Config{
+				LogSources: []string{"/var/log/*.log"},
+				SourceLabels: SourceLabels{
+					{Path: "/var/log/api-*.log", Labels: map[string]string{"service": "api"}},
+				},
+				TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+				Rules:           []RuleConfig{rule},
+			}
+			err := Validate(cfg)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("Validate() error = %v, want %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ccollicutt/negalog/pkg/cron"
+)
+
+// MaintenanceWindow is a planned period in which gaps and incomplete
+// sequences are expected. Issues overlapping it are reported as suppressed
+// rather than as issues. A window is either a one-off range (start and end)
+// or recurring (schedule and duration).
+type MaintenanceWindow struct {
+	// Name identifies the window in reports.
+	Name string `yaml:"name"`
+
+	// Start and End bound a one-off window (RFC 3339).
+	Start time.Time `yaml:"start,omitempty"`
+	End   time.Time `yaml:"end,omitempty"`
+
+	// Schedule is a cron expression giving the start of each occurrence of a
+	// recurring window, which lasts Duration.
+	Schedule string        `yaml:"schedule,omitempty"`
+	Duration time.Duration `yaml:"duration,omitempty"`
+
+	// Timezone is the IANA zone the schedule is evaluated in. Defaults to UTC.
+	Timezone string `yaml:"timezone,omitempty"`
+
+	// Rules limits the window to the named rules. Empty means all rules.
+	Rules []string `yaml:"rules,omitempty"`
+
+	// Labels limits the window to issues from log files carrying all of
+	// these source labels.
+	Labels map[string]string `yaml:"labels,omitempty"`
+
+	// Parsed schedule and its location (populated during validation)
+	compiledSchedule *cron.Schedule
+	location         *time.Location
+}
+
+// Applies returns true if the window covers issues of the named rule found
+// in a log file with the given source labels.
+func (m *MaintenanceWindow) Applies(rule string, labels map[string]string) bool {
+	if len(m.Rules) > 0 && !slices.Contains(m.Rules, rule) {
+		return false
+	}
+	for k, v := range m.Labels {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Overlaps returns true if any part of the span from start to end falls
+// within the window. Windows include their start but not their end.
+func (m *MaintenanceWindow) Overlaps(start, end time.Time) bool {
+	if m.compiledSchedule == nil {
+		return !m.Start.After(end) && m.End.After(start)
+	}
+
+	// The first occurrence that is still open at start
+	next := m.compiledSchedule.Next(start.Add(-m.Duration).In(m.location))
+	return !next.IsZero() && !next.After(end)
+}
+
+// validateMaintenanceWindows checks each maintenance window once the rules
+// have been validated.
+func validateMaintenanceWindows(cfg *Config) error {
+	for i := range cfg.MaintenanceWindows {
+		m := &cfg.MaintenanceWindows[i]
+		if err := validateMaintenanceWindow(cfg, m); err != nil {
+			return fmt.Errorf("maintenance_windows[%d] (%s): %w", i, m.Name, err)
+		}
+	}
+	return nil
+}
+
+func validateMaintenanceWindow(cfg *Config, m *MaintenanceWindow) error {
+	if m.Name == "" {
+		return errors.New("name is required")
+	}
+
+	oneOff := !m.Start.IsZero() || !m.End.IsZero()
+	recurring := m.Schedule != "" || m.Duration != 0
+	switch {
+	case oneOff && recurring:
+		return errors.New("start/end cannot be combined with schedule/duration")
+	case oneOff:
+		if m.Start.IsZero() || m.End.IsZero() {
+			return errors.New("start and end are both required")
+		}
+		if !m.End.After(m.Start) {
+			return errors.New("end must be after start")
+		}
+		if m.Timezone != "" {
+			return errors.New("timezone only applies to schedule (give start and end with an offset)")
+		}
+	case recurring:
+		if m.Schedule == "" || m.Duration <= 0 {
+			return errors.New("schedule and duration are both required")
+		}
+		sched, err := cron.Parse(m.Schedule)
+		if err != nil {
+			return fmt.Errorf("invalid schedule: %w", err)
+		}
+		m.compiledSchedule = sched
+
+		m.location = time.UTC
+		if m.Timezone != "" {
+			loc, err := time.LoadLocation(m.Timezone)
+			if err != nil {
+				return fmt.Errorf("invalid timezone: %w", err)
+			}
+			m.location = loc
+		}
+	default:
+		return errors.New("start and end, or schedule and duration, are required")
+	}
+
+	for _, name := range m.Rules {
+		if !slices.ContainsFunc(cfg.Rules, func(r RuleConfig) bool { return r.Name == name }) {
+			return fmt.Errorf("unknown rule %q", name)
+		}
+	}
+	for key := range m.Labels {
+		if !cfg.SourceLabels.Defines(key) {
+			return fmt.Errorf("label %q is not set by any source_labels entry", key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad_MaintenanceWindows(t *testing.T) {
+	content := `log_sources:
+  - /var/log/*.log
+source_labels:
+  - path: /var/log/db.log
+    labels:
+      service: db
+timestamp_format:
+  pattern: '^\[(\d{4})\]'
+  layout: "2006"
+rules:
+  - name: heartbeat
+    type: periodic
+    pattern: 'HEARTBEAT'
+    max_gap: 5m
+maintenance_windows:
+  - name: upgrade
+    start: 2024-01-15T22:00:00Z
+    end: 2024-01-15T23:30:00Z
+    rules: [heartbeat]
+  - name: nightly-backup
+    schedule: "0 2 * * *"
+    duration: 30m
+    timezone: America/New_York
+    labels:
+      service: db
+`
+	path := writeTempFile(t, "config.yaml", content)
+	cfg, err := Load(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(cfg.MaintenanceWindows) != 2 {
+		t.Fatalf("MaintenanceWindows = %d, want 2", len(cfg.MaintenanceWindows))
+	}
+
+	upgrade := cfg.MaintenanceWindows[0]
+	if !upgrade.Overlaps(time.Date(2024, 1, 15, 23, 0, 0, 0, time.UTC), time.Date(2024, 1, 15, 23, 0, 0, 0, time.UTC)) {
+		t.Error("upgrade does not overlap 23:00")
+	}
+
+	backup := cfg.MaintenanceWindows[1]
+	ny, _ := time.LoadLocation("America/New_York")
+	if !backup.Overlaps(time.Date(2024, 1, 16, 2, 15, 0, 0, ny), time.Date(2024, 1, 16, 2, 15, 0, 0, ny)) {
+		t.Error("nightly-backup does not overlap 02:15 New York time")
+	}
+	if backup.Overlaps(time.Date(2024, 1, 16, 2, 15, 0, 0, time.UTC), time.Date(2024, 1, 16, 2, 15, 0, 0, time.UTC)) {
+		t.Error("nightly-backup overlaps 02:15 UTC")
+	}
+}
+
+func TestMaintenanceWindow_Overlaps(t *testing.T) {
+	base := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time { return base.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+
+	oneOff := MaintenanceWindow{Name: "upgrade", Start: at(10, 0), End: at(11, 0)}
+	recurring := MaintenanceWindow{Name: "backup", Schedule: "0 */6 * * *", Duration: 30 * time.Minute}
+	cfg := &Config{
+		LogSources:         []string{"/var/log/*.log"},
+		TimestampFormat:    TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+		Rules:              []RuleConfig{{Name: "test", Type: "source_silence", MaxSilence: time.Minute}},
+		MaintenanceWindows: []MaintenanceWindow{oneOff, recurring},
+	}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		window     int
+		start, end time.Time
+		want       bool
+	}{
+		{"inside", 0, at(10, 15), at(10, 30), true},
+		{"spans start", 0, at(9, 0), at(10, 0), true},
+		{"spans whole", 0, at(9, 0), at(12, 0), true},
+		{"at end", 0, at(11, 0), at(11, 30), false},
+		{"before", 0, at(8, 0), at(9, 59), false},
+		{"in occurrence", 1, at(6, 10), at(6, 10), true},
+		{"overruns occurrence", 1, at(5, 0), at(6, 0), true},
+		{"between occurrences", 1, at(6, 30), at(11, 59), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.MaintenanceWindows[tt.window].Overlaps(tt.start, tt.end); got != tt.want {
+				t.Errorf("Overlaps(%v, %v) = %v, want %v", tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindow_Applies(t *testing.T) {
+	m := MaintenanceWindow{Rules: []string{"heartbeat"}, Labels: map[string]string{"service": "db"}}
+
+	tests := []struct {
+		name   string
+		rule   string
+		labels map[string]string
+		want   bool
+	}{
+		{"match", "heartbeat", map[string]string{"service": "db", "env": "prod"}, true},
+		{"other rule", "orders", map[string]string{"service": "db"}, false},
+		{"other service", "heartbeat", map[string]string{"service": "api"}, false},
+		{"unlabeled", "heartbeat", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Applies(tt.rule, tt.labels); got != tt.want {
+				t.Errorf("Applies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_MaintenanceWindows(t *testing.T) {
+	start := time.Date(2024, 1, 15, 22, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		errMsg string
+	}{
+		{"no name", MaintenanceWindow{Start: start, End: start.Add(time.Hour)}, "name is required"},
+		{"empty", MaintenanceWindow{Name: "w"}, "start and end, or schedule and duration, are required"},
+		{"no end", MaintenanceWindow{Name: "w", Start: start}, "start and end are both required"},
+		{"end before start", MaintenanceWindow{Name: "w", Start: start, End: start.Add(-time.Hour)}, "end must be after start"},
+		{"mixed", MaintenanceWindow{Name: "w", Start: start, Schedule: "@daily"}, "cannot be combined"},
+		{"no duration", MaintenanceWindow{Name: "w", Schedule: "@daily"}, "schedule and duration are both required"},
+		{"bad schedule", MaintenanceWindow{Name: "w", Schedule: "daily", Duration: time.Hour}, "invalid schedule"},
+		{
+			"bad timezone",
+			MaintenanceWindow{Name: "w", Schedule: "@daily", Duration: time.Hour, Timezone: "Mars/Olympus"},
+			"invalid timezone",
+		},
+		{
+			"unknown rule",
+			MaintenanceWindow{Name: "w", Start: start, End: start.Add(time.Hour), Rules: []string{"nope"}},
+			`unknown rule "nope"`,
+		},
+		{
+			"unknown label",
+			MaintenanceWindow{Name: "w", Start: start, End: start.Add(time.Hour), Labels: map[string]string{"team": "x"}},
+			`label "team" is not set by any source_labels entry`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				LogSources:         []string{"/var/log/*.log"},
+				TimestampFormat:    TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+				Rules:              []RuleConfig{{Name: "test", Type: "source_silence", MaxSilence: time.Minute}},
+				MaintenanceWindows: []MaintenanceWindow{tt.window},
+			}
+			err := Validate(cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// rosterCommandTimeout bounds how long a roster command may run.
+var rosterCommandTimeout = 30 * time.Second
+
+// RosterConfig lists the keys a rule expects to see, so that members which
+// never log at all can be reported. Members may be given inline, read from a
+// file, or taken from a command's output; all sources are combined.
+type RosterConfig struct {
+	// Members lists expected keys inline.
+	Members []string `yaml:"members,omitempty"`
+
+	// File is a path to a file with one member per line. Blank lines and
+	// lines starting with # are ignored.
+	File string `yaml:"file,omitempty"`
+
+	// Command is run with sh -c before analysis (not by validate or
+	// diagnose); its output is read like File.
+	Command string `yaml:"command,omitempty"`
+
+	// resolved is the combined, deduplicated member list.
+	resolved []string
+}
+
+// Expected returns the roster members, including those loaded from the file
+// once LoadRosters has run, and from the command once RunRosterCommands has.
+func (r *RosterConfig) Expected() []string {
+	return r.resolved
+}
+
+// validateRoster checks a rule's roster and resolves its inline members.
+func validateRoster(rule *RuleConfig) error {
+	r := rule.Roster
+	if r == nil {
+		return nil
+	}
+
+	switch rule.RuleTypeEnum() {
+	case RuleTypePeriodic:
+		if rule.GroupBy == "" {
+			return errors.New("roster requires group_by on periodic rules")
+		}
+	case RuleTypeConditional:
+		if len(rule.triggerKey) == 0 {
+			return errors.New("roster requires correlation on conditional rules")
+		}
+	default:
+		return fmt.Errorf("roster is not supported on %s rules", rule.Type)
+	}
+
+	if len(r.Members) == 0 && r.File == "" && r.Command == "" {
+		return errors.New("roster must set members, file, or command")
+	}
+
+	r.resolved = mergeMembers(nil, r.Members)
+	return nil
+}
+
+// LoadRosters reads roster members from files for every rule. It is called
+// by Load after validation. Commands are not run here, so validating a config
+// never executes them; see RunRosterCommands.
+func (c *Config) LoadRosters(ctx context.Context) error {
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if rule.Roster == nil || rule.Roster.File == "" {
+			continue
+		}
+		data, err := os.ReadFile(rule.Roster.File) // #nosec G304 -- user-provided roster path is expected
+		if err != nil {
+			return fmt.Errorf("rules[%d] (%s): roster: reading file: %w", i, rule.Name, err)
+		}
+		rule.Roster.resolved = mergeMembers(rule.Roster.resolved, parseMembers(data))
+	}
+	return nil
+}
+
+// RunRosterCommands adds roster members from each rule's command. It is
+// called before analysis; each command is killed if it runs longer than
+// rosterCommandTimeout.
+func (c *Config) RunRosterCommands(ctx context.Context) error {
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if rule.Roster == nil || rule.Roster.Command == "" {
+			continue
+		}
+		if err := rule.Roster.run(ctx); err != nil {
+			return fmt.Errorf("rules[%d] (%s): roster: %w", i, rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// run adds members from the roster's command output.
+func (r *RosterConfig) run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, rosterCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", r.Command) // #nosec G204 -- command comes from the user's config
+	cmd.WaitDelay = time.Second                            // don't wait on children still holding the output open
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("running command: timed out after %s", rosterCommandTimeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("running command: %w: %s", err, msg)
+		}
+		return fmt.Errorf("running command: %w", err)
+	}
+
+	r.resolved = mergeMembers(r.resolved, parseMembers(out))
+	return nil
+}
+
+// parseMembers reads one member per line, skipping blanks and # comments.
+func parseMembers(data []byte) []string {
+	var members []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		members = append(members, line)
+	}
+	return members
+}
+
+// mergeMembers appends members not already in list.
+func mergeMembers(list, members []string) []string {
+	seen := make(map[string]bool, len(list))
+	for _, m := range list {
+		seen[m] = true
+	}
+	for _, m := range members {
+		if !seen[m] {
+			seen[m] = true
+			list = append(list, m)
+		}
+	}
+	return list
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad_Roster(t *testing.T) {
+	members := writeTempFile(t, "hosts.txt", "# web tier\nweb-1\n\nweb-2\n  web-3  \n")
+
+	content := `
+log_sources:
+  - /var/log/*.log
+timestamp_format:
+  pattern: '^(\S+)'
+  layout: "2006-01-02T15:04:05Z07:00"
+rules:
+  - name: heartbeats
+    type: periodic
+    pattern: 'HEARTBEAT host=(?P<host>\S+)'
+    max_gap: 5m
+    group_by: host
+    roster:
+      members: [web-1, db-1]
+      file: ` + members + `
+      command: "printf 'db-1\\ndb-2\\n'"
+`
+	path := writeTempFile(t, "config.yaml", content)
+	cfg, err := Load(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// Loading alone doesn't run the command
+	want := []string{"web-1", "db-1", "web-2", "web-3"}
+	if got := cfg.Rules[0].Roster.Expected(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected() after Load = %v, want %v", got, want)
+	}
+
+	if err := cfg.RunRosterCommands(context.Background()); err != nil {
+		t.Fatalf("RunRosterCommands() error = %v", err)
+	}
+	want = append(want, "db-2")
+	if got := cfg.Rules[0].Roster.Expected(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected() = %v, want %v", got, want)
+	}
+}
+
+func TestLoad_RosterErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		roster string
+		errMsg string
+	}{
+		{"missing file", "file: /nonexistent/hosts.txt", "reading file"},
+		{"failing command", "command: 'echo boom >&2; exit 1'", "boom"},
+		{"hung command", "command: 'sleep 10'", "timed out after 100ms"},
+	}
+
+	timeout := rosterCommandTimeout
+	rosterCommandTimeout = 100 * time.Millisecond
+	t.Cleanup(func() { rosterCommandTimeout = timeout })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := `
+log_sources:
+  - /var/log/*.log
+timestamp_format:
+  pattern: '^(\S+)'
+  layout: "2006-01-02T15:04:05Z07:00"
+rules:
+  - name: heartbeats
+    type: periodic
+    pattern: 'HEARTBEAT host=(?P<host>\S+)'
+    max_gap: 5m
+    group_by: host
+    roster:
+      ` + tt.roster + `
+`
+			path := writeTempFile(t, "config.yaml", content)
+			cfg, err := Load(context.Background(), path)
+			if err == nil {
+				err = cfg.RunRosterCommands(context.Background())
+			}
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("error = %q, want to contain %q", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestValidate_RosterErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   RuleConfig
+		errMsg string
+	}{
+		{
+			name: "periodic without group_by",
+			rule: RuleConfig{
+				Name: "r", Type: "periodic", Pattern: "HEARTBEAT", MaxGap: time.Minute,
+				Roster: &RosterConfig{Members: []string{"a"}},
+			},
+			errMsg: "requires group_by",
+		},
+		{
+			name: "conditional without correlation",
+			rule: RuleConfig{
+				Name: "r", Type: "conditional", TriggerPattern: "ERROR", ExpectedPattern: "ALERT",
+				Timeout: time.Minute, Roster: &RosterConfig{Members: []string{"a"}},
+			},
+			errMsg: "requires correlation",
+		},
+		{
+			name: "sequence rule",
+			rule: RuleConfig{
+				Name: "r", Type: "sequence", StartPattern: `START id=(\w+)`, EndPattern: `END id=(\w+)`,
+				CorrelationField: 1, Timeout: time.Minute, Roster: &RosterConfig{Members: []string{"a"}},
+			},
+			errMsg: "not supported on sequence rules",
+		},
+		{
+			name: "empty roster",
+			rule: RuleConfig{
+				Name: "r", Type: "periodic", Pattern: `HEARTBEAT host=(\S+)`, MaxGap: time.Minute,
+				GroupBy: "1", Roster: &RosterConfig{},
+			},
+			errMsg: "must set members, file, or command",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				LogSources:      []string{"/var/log/*.log"},
+				TimestampFormat: TimestampConfig{Pattern: `^(\S+)`, Layout: time.RFC3339},
+				Rules:           []RuleConfig{tt.rule},
+			}
+			err := Validate(cfg)
+			if err == nil {
+				t.Fatal("Validate() expected error")
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("error = %q, want to contain %q", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
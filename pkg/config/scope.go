@@ -0,0 +1,60 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// CoversSource returns true if lines from the log file at path are routed
+// to the rule: the path matches one of its sources globs, if any, and the
+// file carries all of its match_labels, if any.
+func (r *RuleConfig) CoversSource(path string) bool {
+	if len(r.Sources) > 0 {
+		matched := false
+		for _, pattern := range r.Sources {
+			if ok, _ := filepath.Match(pattern, path); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(r.MatchLabels) > 0 {
+		labels := r.sourceLabels.For(path)
+		for k, v := range r.MatchLabels {
+			if labels[k] != v {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// validateScope checks a rule's sources, match_labels and distinct_sources.
+func validateScope(rule *RuleConfig) error {
+	for _, pattern := range rule.Sources {
+		if pattern == "" {
+			return errors.New("sources must not be empty")
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid sources pattern %q: %w", pattern, err)
+		}
+	}
+
+	for key := range rule.MatchLabels {
+		if !rule.sourceLabels.Defines(key) {
+			return fmt.Errorf("match_labels: label %q is not set by any source_labels entry", key)
+		}
+	}
+
+	if rule.DistinctSources && rule.RuleTypeEnum() != RuleTypeSequence {
+		return fmt.Errorf("distinct_sources is not supported on %s rules", rule.Type)
+	}
+
+	return nil
+}
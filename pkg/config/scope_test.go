@@ -0,0 +1,86 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRuleConfig_CoversSource(t *testing.T) {
+	labels := SourceLabels{
+		{Path: "/var/log/prod/*.log", Labels: map[string]string{"env": "prod"}},
+		{Path: "/var/log/staging/*.log", Labels: map[string]string{"env": "staging"}},
+		{Path: "/var/log/*/api.log", Labels: map[string]string{"service": "api"}},
+	}
+
+	tests := []struct {
+		name string
+		rule RuleConfig
+		path string
+		want bool
+	}{
+		{"unscoped", RuleConfig{}, "/tmp/any.log", true},
+		{"sources match", RuleConfig{Sources: []string{"/var/log/prod/*.log"}}, "/var/log/prod/api.log", true},
+		{"sources any", RuleConfig{Sources: []string{"/x.log", "/var/log/*/api.log"}}, "/var/log/staging/api.log", true},
+		{"sources miss", RuleConfig{Sources: []string{"/var/log/prod/*.log"}}, "/var/log/staging/api.log", false},
+		{"labels match", RuleConfig{MatchLabels: map[string]string{"env": "prod", "service": "api"}}, "/var/log/prod/api.log", true},
+		{"labels partial", RuleConfig{MatchLabels: map[string]string{"env": "prod", "service": "api"}}, "/var/log/prod/db.log", false},
+		{"labels other value", RuleConfig{MatchLabels: map[string]string{"env": "prod"}}, "/var/log/staging/api.log", false},
+		{"unlabeled file", RuleConfig{MatchLabels: map[string]string{"env": "prod"}}, "/tmp/any.log", false},
+		{
+			"both",
+			RuleConfig{Sources: []string{"/var/log/*/api.log"}, MatchLabels: map[string]string{"env": "staging"}},
+			"/var/log/staging/api.log",
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.sourceLabels = labels
+			if got := tt.rule.CoversSource(tt.path); got != tt.want {
+				t.Errorf("CoversSource(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_Scope(t *testing.T) {
+	periodic := RuleConfig{Name: "test", Type: "periodic", Pattern: "X", MaxGap: time.Minute}
+
+	tests := []struct {
+		name   string
+		modify func(r *RuleConfig)
+		errMsg string
+	}{
+		{"empty source", func(r *RuleConfig) { r.Sources = []string{""} }, "sources must not be empty"},
+		{"bad source", func(r *RuleConfig) { r.Sources = []string{"[a-"} }, `invalid sources pattern "[a-"`},
+		{
+			"unknown label",
+			func(r *RuleConfig) { r.MatchLabels = map[string]string{"team": "x"} },
+			`match_labels: label "team" is not set by any source_labels entry`,
+		},
+		{
+			"distinct sources on periodic",
+			func(r *RuleConfig) { r.DistinctSources = true },
+			"distinct_sources is not supported on periodic rules",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := periodic
+			tt.modify(&rule)
+			cfg := &Config{
+				LogSources:      []string{"/var/log/*.log"},
+				SourceLabels:    SourceLabels{{Path: "/var/log/*.log", Labels: map[string]string{"env": "prod"}}},
+				TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+				Rules:           []RuleConfig{rule},
+			}
+			err := Validate(cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
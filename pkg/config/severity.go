@@ -0,0 +1,69 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Severity ranks how serious a rule's issues are.
+type Severity string
+
+const (
+	// SeverityInfo is for issues worth knowing about but not acting on.
+	SeverityInfo Severity = "info"
+	// SeverityWarning is for issues that need attention (default).
+	SeverityWarning Severity = "warning"
+	// SeverityCritical is for issues that need action now.
+	SeverityCritical Severity = "critical"
+)
+
+// severityRanks orders severities from least to most serious.
+var severityRanks = map[Severity]int{
+	SeverityInfo:     1,
+	SeverityWarning:  2,
+	SeverityCritical: 3,
+}
+
+// ParseSeverity parses a severity name, as given to --min-severity or --fail-on.
+func ParseSeverity(s string) (Severity, error) {
+	sev := Severity(s)
+	if _, ok := severityRanks[sev]; !ok {
+		return "", fmt.Errorf("invalid severity %q (must be info, warning, or critical)", s)
+	}
+	return sev, nil
+}
+
+// AtLeast returns true if s is as serious as min or more.
+func (s Severity) AtLeast(min Severity) bool {
+	return severityRanks[s] >= severityRanks[min]
+}
+
+// HasTag returns true if the rule has any of the given tags.
+func (r *RuleConfig) HasTag(tags ...string) bool {
+	for _, want := range tags {
+		for _, tag := range r.Tags {
+			if tag == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateSeverity checks a rule's severity and tags, defaulting the
+// severity to warning.
+func validateSeverity(rule *RuleConfig) error {
+	if rule.Severity == "" {
+		rule.Severity = SeverityWarning
+	}
+	if _, ok := severityRanks[rule.Severity]; !ok {
+		return fmt.Errorf("invalid severity %q (must be info, warning, or critical)", rule.Severity)
+	}
+
+	for _, tag := range rule.Tags {
+		if tag == "" {
+			return errors.New("tags must not be empty")
+		}
+	}
+	return nil
+}
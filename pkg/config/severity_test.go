@@ -0,0 +1,89 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSeverity(t *testing.T) {
+	for _, s := range []string{"info", "warning", "critical"} {
+		if got, err := ParseSeverity(s); err != nil || string(got) != s {
+			t.Errorf("ParseSeverity(%q) = %q, %v", s, got, err)
+		}
+	}
+
+	if _, err := ParseSeverity("high"); err == nil || !strings.Contains(err.Error(), `invalid severity "high"`) {
+		t.Errorf("ParseSeverity(high) error = %v", err)
+	}
+}
+
+func TestSeverity_AtLeast(t *testing.T) {
+	tests := []struct {
+		s, min Severity
+		want   bool
+	}{
+		{SeverityInfo, SeverityInfo, true},
+		{SeverityInfo, SeverityWarning, false},
+		{SeverityWarning, SeverityInfo, true},
+		{SeverityWarning, SeverityCritical, false},
+		{SeverityCritical, SeverityWarning, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.s.AtLeast(tt.min); got != tt.want {
+			t.Errorf("%s.AtLeast(%s) = %v, want %v", tt.s, tt.min, got, tt.want)
+		}
+	}
+}
+
+func TestRuleConfig_HasTag(t *testing.T) {
+	rule := RuleConfig{Tags: []string{"payments", "prod"}}
+
+	if !rule.HasTag("prod") {
+		t.Error("HasTag(prod) = false")
+	}
+	if !rule.HasTag("staging", "payments") {
+		t.Error("HasTag(staging, payments) = false")
+	}
+	if rule.HasTag("staging") {
+		t.Error("HasTag(staging) = true")
+	}
+}
+
+func TestValidate_Severity(t *testing.T) {
+	newConfig := func(rule RuleConfig) *Config {
+		rule.Name, rule.Type, rule.Pattern, rule.MaxGap = "test", "periodic", "X", time.Minute
+		return &Config{
+			LogSources:      []string{"/var/log/*.log"},
+			TimestampFormat: TimestampConfig{Pattern: `^\[(\d{4})\]`, Layout: "2006"},
+			Rules:           []RuleConfig{rule},
+		}
+	}
+
+	cfg := newConfig(RuleConfig{})
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if cfg.Rules[0].Severity != SeverityWarning {
+		t.Errorf("Severity = %q, want warning by default", cfg.Rules[0].Severity)
+	}
+
+	tests := []struct {
+		name   string
+		rule   RuleConfig
+		errMsg string
+	}{
+		{"invalid severity", RuleConfig{Severity: "high"}, `invalid severity "high"`},
+		{"empty tag", RuleConfig{Tags: []string{"prod", ""}}, "tags must not be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(newConfig(tt.rule))
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.errMsg)
+			}
+		})
+	}
+}
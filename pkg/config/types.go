@@ -3,15 +3,22 @@ package config
 
 import (
 	"regexp"
+	"strings"
 	"time"
+
+	"github.com/ccollicutt/negalog/pkg/calendar"
+	"github.com/ccollicutt/negalog/pkg/cron"
 )
 
 // Config is the root configuration structure loaded from YAML.
 type Config struct {
-	LogSources      []string        `yaml:"log_sources"`
-	TimestampFormat TimestampConfig `yaml:"timestamp_format"`
-	Rules           []RuleConfig    `yaml:"rules"`
-	Webhooks        []WebhookConfig `yaml:"webhooks,omitempty"`
+	LogSources         []string            `yaml:"log_sources"`
+	SourceLabels       SourceLabels        `yaml:"source_labels,omitempty"`
+	TimestampFormat    TimestampConfig     `yaml:"timestamp_format"`
+	Calendars          []CalendarConfig    `yaml:"calendars,omitempty"`
+	Rules              []RuleConfig        `yaml:"rules"`
+	MaintenanceWindows []MaintenanceWindow `yaml:"maintenance_windows,omitempty"`
+	Webhooks           []WebhookConfig     `yaml:"webhooks,omitempty"`
 }
 
 // TimestampConfig defines how to extract timestamps from log lines.
@@ -37,41 +44,293 @@ func (t *TimestampConfig) CompiledPattern() *regexp.Regexp {
 type RuleType string
 
 const (
-	RuleTypeSequence    RuleType = "sequence"
-	RuleTypePeriodic    RuleType = "periodic"
-	RuleTypeConditional RuleType = "conditional"
+	RuleTypeSequence       RuleType = "sequence"
+	RuleTypePeriodic       RuleType = "periodic"
+	RuleTypeConditional    RuleType = "conditional"
+	RuleTypeRate           RuleType = "rate"
+	RuleTypeReconciliation RuleType = "reconciliation"
+	RuleTypeBaseline       RuleType = "baseline"
+	RuleTypeSourceSilence  RuleType = "source_silence"
+	RuleTypeOrdering       RuleType = "ordering"
 )
 
 // RuleConfig defines a single detection rule.
 type RuleConfig struct {
 	// Common fields
 	Name        string `yaml:"name"`
-	Type        string `yaml:"type"` // sequence, periodic, conditional
+	Type        string `yaml:"type"` // sequence, periodic, conditional, rate, reconciliation, baseline, source_silence, ordering
 	Description string `yaml:"description,omitempty"`
 
+	// Severity ranks the rule's issues: info, warning (default), or critical.
+	Severity Severity `yaml:"severity,omitempty"`
+
+	// Tags are free-form labels for selecting rules with --tag.
+	Tags []string `yaml:"tags,omitempty"`
+
+	// Sources limits the rule to lines from log files matching any of these
+	// globs. By default a rule sees lines from every log source.
+	Sources []string `yaml:"sources,omitempty"`
+
+	// MatchLabels limits the rule to lines from log files carrying all of
+	// these source labels.
+	MatchLabels map[string]string `yaml:"match_labels,omitempty"`
+
+	// Calendar names the calendar that sets when the rule is active.
+	// Periodic rules measure gaps across active time only; sequence and
+	// conditional rules pause their timeouts outside it; rate rules skip
+	// windows that are not entirely within it.
+	Calendar string `yaml:"calendar,omitempty"`
+
 	// Sequence rule fields
 	StartPattern     string        `yaml:"start_pattern,omitempty"`
 	EndPattern       string        `yaml:"end_pattern,omitempty"`
 	CorrelationField int           `yaml:"correlation_field,omitempty"` // capture group index (1-based)
 	Timeout          time.Duration `yaml:"timeout,omitempty"`
 
+	// DistinctSources requires a sequence's end to come from a different log
+	// file than its start, such as a backend completing a request received
+	// by a gateway. Ends from the start's own file are ignored.
+	DistinctSources bool `yaml:"distinct_sources,omitempty"`
+
+	// CorrelationGroups names the capture groups that make up the correlation
+	// ID, as an alternative to correlation_field. Several names build a
+	// composite key. Shared by all patterns unless overridden per pattern.
+	CorrelationGroups []string `yaml:"correlation_groups,omitempty"`
+
+	// StartCorrelationGroups and EndCorrelationGroups override
+	// correlation_groups for the start and end patterns.
+	StartCorrelationGroups []string `yaml:"start_correlation_groups,omitempty"`
+	EndCorrelationGroups   []string `yaml:"end_correlation_groups,omitempty"`
+
+	// Steps defines an ordered multi-step sequence as an alternative to
+	// start_pattern/end_pattern. All steps share the correlation field.
+	Steps []SequenceStep `yaml:"steps,omitempty"`
+
+	// ReportOrphanEnds reports end events whose correlation ID has no open start.
+	ReportOrphanEnds bool `yaml:"report_orphan_ends,omitempty"`
+
+	// DuplicateStarts controls how a start is handled when its correlation ID
+	// is already open. Defaults to "keep_last".
+	DuplicateStarts DuplicateStartPolicy `yaml:"duplicate_starts,omitempty"`
+
+	// MaxRetries flags a sequence started more than MaxRetries+1 times
+	// before completing. Zero disables the check.
+	MaxRetries int `yaml:"max_retries,omitempty"`
+
+	// Roster lists the group_by values (periodic rules) or trigger correlation
+	// IDs (conditional rules) that must each appear in the window.
+	Roster *RosterConfig `yaml:"roster,omitempty"`
+
 	// Periodic rule fields
 	Pattern        string        `yaml:"pattern,omitempty"`
 	MaxGap         time.Duration `yaml:"max_gap,omitempty"`
 	MinOccurrences int           `yaml:"min_occurrences,omitempty"`
 
+	// GroupBy tracks a periodic rule separately per value of a capture group
+	// in pattern, given as a group name or 1-based index (e.g. per host).
+	// Reconciliation rules group both of their patterns by it.
+	GroupBy string `yaml:"group_by,omitempty"`
+
+	// Schedule is a cron expression giving the exact times a periodic log is
+	// expected, as an alternative to max_gap.
+	Schedule string `yaml:"schedule,omitempty"`
+
+	// Tolerance is how far from a scheduled time an occurrence may be and
+	// still count for it. Defaults to 5m.
+	Tolerance time.Duration `yaml:"tolerance,omitempty"`
+
+	// Timezone is the IANA zone the schedule is evaluated in (for baseline
+	// rules, the zone hours of the week are taken in). Defaults to UTC.
+	Timezone string `yaml:"timezone,omitempty"`
+
+	// Rate rule fields (pattern is shared with periodic rules)
+	// Window is the length of each counting window (also for reconciliation rules).
+	Window time.Duration `yaml:"window,omitempty"`
+
+	// Slide is how far each window starts after the previous one. Defaults
+	// to window, giving tumbling windows; a shorter slide gives sliding ones.
+	Slide time.Duration `yaml:"slide,omitempty"`
+
+	// MinCount is the fewest matches each window must contain.
+	MinCount int `yaml:"min_count,omitempty"`
+
+	// Baseline rule fields (pattern and timezone are shared)
+	// BaselineFile holds the learned volume per hour of the week, built from
+	// past logs with the baseline command.
+	BaselineFile string `yaml:"baseline_file,omitempty"`
+
+	// MinFraction flags hours with fewer matches than this fraction of the
+	// baseline mean (e.g. 0.5).
+	MinFraction float64 `yaml:"min_fraction,omitempty"`
+
+	// MaxDeviations flags hours more than this many standard deviations below
+	// the baseline mean.
+	MaxDeviations float64 `yaml:"max_deviations,omitempty"`
+
+	// Source silence rule fields
+	// MaxSilence is the longest a log source may go without a line.
+	MaxSilence time.Duration `yaml:"max_silence,omitempty"`
+
+	// GroupByLabel tracks sources by the value of this source label (e.g.
+	// service) instead of per file. Files without the label are ignored.
+	GroupByLabel string `yaml:"group_by_label,omitempty"`
+
+	// Ordering rule fields
+	// BeforePattern must never be matched after AfterPattern for the same
+	// correlation ID. A line matching both counts as after_pattern only.
+	BeforePattern string `yaml:"before_pattern,omitempty"`
+	AfterPattern  string `yaml:"after_pattern,omitempty"`
+	// CorrelationField and CorrelationGroups are shared, and optional
+
+	// BeforeCorrelationGroups and AfterCorrelationGroups override
+	// correlation_groups for the before and after patterns.
+	BeforeCorrelationGroups []string `yaml:"before_correlation_groups,omitempty"`
+	AfterCorrelationGroups  []string `yaml:"after_correlation_groups,omitempty"`
+
+	// PerSource checks the order within each log file separately.
+	PerSource bool `yaml:"per_source,omitempty"`
+	// Window is shared: for ordering rules, how long after an after_pattern
+	// match a before_pattern match is still checked (zero for no limit).
+
+	// Reconciliation rule fields (window and group_by are shared)
+	// LeftPattern and RightPattern match two event streams whose counts
+	// should agree in each window, such as messages produced and consumed.
+	LeftPattern  string `yaml:"left_pattern,omitempty"`
+	RightPattern string `yaml:"right_pattern,omitempty"`
+
+	// MaxDifference is how far apart the two counts may be.
+	MaxDifference int `yaml:"max_difference,omitempty"`
+
+	// MaxRatio is how many times the smaller count the larger may be (e.g.
+	// 1.05). A window is reported only if it exceeds every threshold set,
+	// and without either the counts must be equal.
+	MaxRatio float64 `yaml:"max_ratio,omitempty"`
+
 	// Conditional rule fields
 	TriggerPattern  string `yaml:"trigger_pattern,omitempty"`
 	ExpectedPattern string `yaml:"expected_pattern,omitempty"`
 	// Timeout is shared with sequence rules
-	// CorrelationField is shared with sequence rules
+	// CorrelationField and CorrelationGroups are shared with sequence rules
+
+	// ForbiddenPattern inverts a conditional rule: instead of requiring
+	// expected_pattern after each trigger, it reports a forbidden_pattern
+	// match within the timeout.
+	ForbiddenPattern string `yaml:"forbidden_pattern,omitempty"`
+
+	// ExpectedCount requires a number of expected events after each trigger
+	// instead of just one. Requires expected_pattern and correlation.
+	ExpectedCount *CountConfig `yaml:"expected_count,omitempty"`
+
+	// PrecededBy looks backward instead: each trigger must have been preceded
+	// by a match of this pattern within the timeout. Exactly one of
+	// expected_pattern, forbidden_pattern, and preceded_by must be set.
+	PrecededBy string `yaml:"preceded_by,omitempty"`
+
+	// TriggerCorrelationGroups, ExpectedCorrelationGroups,
+	// ForbiddenCorrelationGroups, and PrecededByCorrelationGroups override
+	// correlation_groups for the trigger, expected, forbidden, and
+	// preceded_by patterns.
+	TriggerCorrelationGroups    []string `yaml:"trigger_correlation_groups,omitempty"`
+	ExpectedCorrelationGroups   []string `yaml:"expected_correlation_groups,omitempty"`
+	ForbiddenCorrelationGroups  []string `yaml:"forbidden_correlation_groups,omitempty"`
+	PrecededByCorrelationGroups []string `yaml:"preceded_by_correlation_groups,omitempty"`
 
 	// Compiled patterns (populated during validation)
-	compiledStartPattern    *regexp.Regexp
-	compiledEndPattern      *regexp.Regexp
-	compiledPattern         *regexp.Regexp
-	compiledTriggerPattern  *regexp.Regexp
-	compiledExpectedPattern *regexp.Regexp
+	compiledStartPattern     *regexp.Regexp
+	compiledEndPattern       *regexp.Regexp
+	compiledPattern          *regexp.Regexp
+	compiledTriggerPattern   *regexp.Regexp
+	compiledExpectedPattern  *regexp.Regexp
+	compiledForbiddenPattern *regexp.Regexp
+	compiledPrecededBy       *regexp.Regexp
+	compiledLeftPattern      *regexp.Regexp
+	compiledRightPattern     *regexp.Regexp
+	compiledBeforePattern    *regexp.Regexp
+	compiledAfterPattern     *regexp.Regexp
+
+	// Resolved group_by capture group index (populated during validation);
+	// groupIndex is in pattern or left_pattern, rightGroupIndex in right_pattern
+	groupIndex      int
+	rightGroupIndex int
+
+	// Parsed schedule and its location (populated during validation)
+	compiledSchedule *cron.Schedule
+	location         *time.Location
+
+	// Source labels from the config (populated during validation)
+	sourceLabels SourceLabels
+
+	// Calendar named by the rule (populated during validation)
+	calendar *calendar.Calendar
+
+	// Resolved correlation keys (populated during validation)
+	startKey      CorrelationKey
+	endKey        CorrelationKey
+	triggerKey    CorrelationKey
+	expectedKey   CorrelationKey
+	forbiddenKey  CorrelationKey
+	precededByKey CorrelationKey
+	beforeKey     CorrelationKey
+	afterKey      CorrelationKey
+}
+
+// SequenceStep defines a single step of a multi-step sequence rule.
+type SequenceStep struct {
+	// Name identifies the step in reports. Defaults to "step<N>" (1-based).
+	Name string `yaml:"name,omitempty"`
+
+	// Pattern is the regex that marks this step as reached.
+	Pattern string `yaml:"pattern"`
+
+	// Timeout is the maximum time allowed since the previous step (optional).
+	// The rule-level timeout still bounds the sequence as a whole.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// CorrelationGroups overrides the rule's correlation_groups for this step.
+	CorrelationGroups []string `yaml:"correlation_groups,omitempty"`
+
+	// compiledPattern is the pre-compiled regex (populated during validation).
+	compiledPattern *regexp.Regexp
+
+	// key is the resolved correlation key (populated during validation).
+	key CorrelationKey
+}
+
+// CompiledPattern returns the compiled pattern for this step.
+func (s *SequenceStep) CompiledPattern() *regexp.Regexp {
+	return s.compiledPattern
+}
+
+// CorrelationKey returns the resolved correlation key for this step.
+func (s *SequenceStep) CorrelationKey() CorrelationKey {
+	return s.key
+}
+
+// CorrelationKey lists the 1-based capture group indexes that together form a
+// correlation ID. An empty key means the pattern is not correlated.
+type CorrelationKey []int
+
+// correlationKeySeparator joins the parts of a composite correlation ID.
+const correlationKeySeparator = "|"
+
+// Extract builds the correlation ID from a pattern's submatches. It returns
+// false if the matches do not contain every group in the key.
+func (k CorrelationKey) Extract(matches []string) (string, bool) {
+	if len(k) == 1 {
+		if k[0] >= len(matches) {
+			return "", false
+		}
+		return matches[k[0]], true
+	}
+
+	parts := make([]string, len(k))
+	for i, idx := range k {
+		if idx >= len(matches) {
+			return "", false
+		}
+		parts[i] = matches[idx]
+	}
+	return strings.Join(parts, correlationKeySeparator), true
 }
 
 // CompiledStartPattern returns the compiled start pattern for sequence rules.
@@ -84,7 +343,7 @@ func (r *RuleConfig) CompiledEndPattern() *regexp.Regexp {
 	return r.compiledEndPattern
 }
 
-// CompiledPattern returns the compiled pattern for periodic rules.
+// CompiledPattern returns the compiled pattern for periodic, rate, and baseline rules.
 func (r *RuleConfig) CompiledPattern() *regexp.Regexp {
 	return r.compiledPattern
 }
@@ -99,11 +358,147 @@ func (r *RuleConfig) CompiledExpectedPattern() *regexp.Regexp {
 	return r.compiledExpectedPattern
 }
 
+// CompiledForbiddenPattern returns the compiled forbidden pattern for
+// conditional rules, nil if the rule uses expected_pattern.
+func (r *RuleConfig) CompiledForbiddenPattern() *regexp.Regexp {
+	return r.compiledForbiddenPattern
+}
+
+// CompiledPrecededBy returns the compiled preceded_by pattern for
+// conditional rules, nil if the rule does not use one.
+func (r *RuleConfig) CompiledPrecededBy() *regexp.Regexp {
+	return r.compiledPrecededBy
+}
+
+// CompiledLeftPattern returns the compiled left pattern for reconciliation rules.
+func (r *RuleConfig) CompiledLeftPattern() *regexp.Regexp {
+	return r.compiledLeftPattern
+}
+
+// CompiledRightPattern returns the compiled right pattern for reconciliation rules.
+func (r *RuleConfig) CompiledRightPattern() *regexp.Regexp {
+	return r.compiledRightPattern
+}
+
+// CompiledBeforePattern returns the compiled before pattern for ordering rules.
+func (r *RuleConfig) CompiledBeforePattern() *regexp.Regexp {
+	return r.compiledBeforePattern
+}
+
+// CompiledAfterPattern returns the compiled after pattern for ordering rules.
+func (r *RuleConfig) CompiledAfterPattern() *regexp.Regexp {
+	return r.compiledAfterPattern
+}
+
+// GroupIndex returns the capture group index periodic matches (or
+// reconciliation left matches) are grouped by, 0 if not grouped.
+func (r *RuleConfig) GroupIndex() int {
+	return r.groupIndex
+}
+
+// RightGroupIndex returns the capture group index reconciliation right
+// matches are grouped by, 0 if not grouped.
+func (r *RuleConfig) RightGroupIndex() int {
+	return r.rightGroupIndex
+}
+
+// CompiledSchedule returns the parsed cron schedule for periodic rules, nil if not set.
+func (r *RuleConfig) CompiledSchedule() *cron.Schedule {
+	return r.compiledSchedule
+}
+
+// Location returns the timezone the schedule is evaluated in.
+func (r *RuleConfig) Location() *time.Location {
+	if r.location == nil {
+		return time.UTC
+	}
+	return r.location
+}
+
+// StartCorrelationKey returns the correlation key for the start pattern.
+func (r *RuleConfig) StartCorrelationKey() CorrelationKey {
+	return r.startKey
+}
+
+// SequenceCorrelationKey returns the correlation key that opens a sequence:
+// the first step's for rules with steps, otherwise the start pattern's. It
+// is empty if the sequence is not correlated.
+func (r *RuleConfig) SequenceCorrelationKey() CorrelationKey {
+	if len(r.Steps) > 0 {
+		return r.Steps[0].key
+	}
+	return r.startKey
+}
+
+// EndCorrelationKey returns the correlation key for the end pattern.
+func (r *RuleConfig) EndCorrelationKey() CorrelationKey {
+	return r.endKey
+}
+
+// TriggerCorrelationKey returns the correlation key for the trigger pattern,
+// empty if the rule is not correlated.
+func (r *RuleConfig) TriggerCorrelationKey() CorrelationKey {
+	return r.triggerKey
+}
+
+// ExpectedCorrelationKey returns the correlation key for the expected pattern,
+// empty if the rule is not correlated.
+func (r *RuleConfig) ExpectedCorrelationKey() CorrelationKey {
+	return r.expectedKey
+}
+
+// ForbiddenCorrelationKey returns the correlation key for the forbidden
+// pattern, empty if the rule is not correlated.
+func (r *RuleConfig) ForbiddenCorrelationKey() CorrelationKey {
+	return r.forbiddenKey
+}
+
+// PrecededByCorrelationKey returns the correlation key for the preceded_by
+// pattern, empty if the rule is not correlated.
+func (r *RuleConfig) PrecededByCorrelationKey() CorrelationKey {
+	return r.precededByKey
+}
+
+// BeforeCorrelationKey returns the correlation key for the before pattern,
+// empty if the ordering rule is not correlated.
+func (r *RuleConfig) BeforeCorrelationKey() CorrelationKey {
+	return r.beforeKey
+}
+
+// AfterCorrelationKey returns the correlation key for the after pattern.
+func (r *RuleConfig) AfterCorrelationKey() CorrelationKey {
+	return r.afterKey
+}
+
+// ActiveCalendar returns the rule's calendar, nil if it has none.
+func (r *RuleConfig) ActiveCalendar() *calendar.Calendar {
+	return r.calendar
+}
+
+// SourceLabels returns the config's source labels.
+func (r *RuleConfig) SourceLabels() SourceLabels {
+	return r.sourceLabels
+}
+
 // RuleTypeEnum returns the rule type as a RuleType enum.
 func (r *RuleConfig) RuleTypeEnum() RuleType {
 	return RuleType(r.Type)
 }
 
+// DuplicateStartPolicy determines how sequence rules handle repeated starts.
+type DuplicateStartPolicy string
+
+const (
+	// DuplicateStartsKeepLast replaces the open sequence with the new start (default).
+	DuplicateStartsKeepLast DuplicateStartPolicy = "keep_last"
+	// DuplicateStartsKeepFirst ignores the new start and keeps the original.
+	DuplicateStartsKeepFirst DuplicateStartPolicy = "keep_first"
+	// DuplicateStartsReport keeps the original start and reports the duplicate as an issue.
+	DuplicateStartsReport DuplicateStartPolicy = "report"
+	// DuplicateStartsMultiset tracks every start separately; each needs its own end.
+	DuplicateStartsMultiset DuplicateStartPolicy = "multiset"
+)
+
 // WebhookTrigger determines when a webhook fires.
 type WebhookTrigger string
 
@@ -0,0 +1,127 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"x * * * *",
+		"@sometimes",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := Parse(expr); err == nil {
+				t.Errorf("Parse(%q) expected error", expr)
+			}
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	base := time.Date(2024, 1, 15, 10, 7, 30, 0, time.UTC) // Monday
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 1, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 15, 10, 15, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2024, 1, 16, 2, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 9 * * mon-fri", time.Date(2024, 1, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 feb *", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"5,10 10 * * *", time.Date(2024, 1, 15, 10, 10, 0, 0, time.UTC)},
+		{"10/20 * * * *", time.Date(2024, 1, 15, 10, 10, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches (the 20th or a Wednesday)
+		{"0 0 20 * wed", time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := s.Next(base); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedule_Next_Strict(t *testing.T) {
+	s, err := Parse("0 2 * * *")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	at := time.Date(2024, 1, 15, 2, 0, 0, 0, time.UTC)
+	want := time.Date(2024, 1, 16, 2, 0, 0, 0, time.UTC)
+	if got := s.Next(at); !got.Equal(want) {
+		t.Errorf("Next(scheduled time) = %v, want %v", got, want)
+	}
+}
+
+func TestSchedule_Next_Location(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	s, err := Parse("0 2 * * *")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// 2024-01-15 12:00 UTC is 07:00 in New York, so the next run is 02:00
+	// New York time on the 16th, which is 07:00 UTC
+	got := s.Next(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC).In(loc))
+	want := time.Date(2024, 1, 16, 7, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+}
+
+func TestSchedule_Next_Never(t *testing.T) {
+	s, err := Parse("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if got := s.Next(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Errorf("Next() = %v, want zero time", got)
+	}
+}
+
+func TestSchedule_Matches(t *testing.T) {
+	s, err := Parse("0 2 * * *")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if !s.Matches(time.Date(2024, 1, 15, 2, 0, 0, 0, time.UTC)) {
+		t.Error("Matches(02:00) = false, want true")
+	}
+	if s.Matches(time.Date(2024, 1, 15, 2, 1, 0, 0, time.UTC)) {
+		t.Error("Matches(02:01) = true, want false")
+	}
+	if s.String() != "0 2 * * *" {
+		t.Errorf("String() = %q", s.String())
+	}
+}
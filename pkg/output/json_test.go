@@ -45,6 +45,37 @@ func TestJSONFormatter_Format(t *testing.T) {
 	}
 }
 
+func TestJSONFormatter_Format_Latency(t *testing.T) {
+	f := NewJSONFormatter(FormatOptions{})
+	report := createTestReport()
+	report.Results[0].Stats.Completed = 5
+	report.Results[0].Stats.Latency = &analyzer.LatencyStats{
+		Min: time.Second,
+		P50: 2 * time.Second,
+		P95: 3 * time.Second,
+		P99: 4 * time.Second,
+		Max: 5 * time.Second,
+	}
+
+	var buf bytes.Buffer
+	if err := f.Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	var parsed Report
+	if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	stats := parsed.Results[0].Stats
+	if stats.Completed != 5 {
+		t.Errorf("Completed = %d, want 5", stats.Completed)
+	}
+	if stats.Latency == nil || stats.Latency.P99 != 4*time.Second {
+		t.Errorf("Latency = %+v, want P99 4s", stats.Latency)
+	}
+}
+
 func TestJSONFormatter_Format_Quiet(t *testing.T) {
 	f := NewJSONFormatter(FormatOptions{Quiet: true})
 	report := createTestReport()
@@ -33,13 +33,22 @@ func (f *TextFormatter) Format(ctx context.Context, report *Report, w io.Writer)
 }
 
 func (f *TextFormatter) formatQuiet(report *Report, w io.Writer) error {
-	fmt.Fprintf(w, "NegaLog: %d rules checked, %d with issues, %d total issues\n",
+	fmt.Fprintf(w, "NegaLog: %d rules checked, %d with issues, %d total issues%s\n",
 		report.Summary.RulesChecked,
 		report.Summary.RulesWithIssues,
-		report.Summary.TotalIssues)
+		report.Summary.TotalIssues,
+		suppressedSuffix(report))
 	return nil
 }
 
+// suppressedSuffix returns the summary's suppressed issue count, if any.
+func suppressedSuffix(report *Report) string {
+	if report.Summary.SuppressedIssues == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", %d suppressed", report.Summary.SuppressedIssues)
+}
+
 func (f *TextFormatter) formatFull(report *Report, w io.Writer) error {
 	// Header
 	fmt.Fprintln(w, "=== NegaLog Analysis Report ===")
@@ -54,10 +63,11 @@ func (f *TextFormatter) formatFull(report *Report, w io.Writer) error {
 
 	// Summary
 	fmt.Fprintln(w, "---")
-	fmt.Fprintf(w, "Summary: %d rules checked, %d rules with issues, %d total issues\n",
+	fmt.Fprintf(w, "Summary: %d rules checked, %d rules with issues, %d total issues%s\n",
 		report.Summary.RulesChecked,
 		report.Summary.RulesWithIssues,
-		report.Summary.TotalIssues)
+		report.Summary.TotalIssues,
+		suppressedSuffix(report))
 
 	if f.opts.Verbose {
 		fmt.Fprintf(w, "Lines processed: %d\n", report.Summary.LinesProcessed)
@@ -76,18 +86,48 @@ func (f *TextFormatter) formatRuleResult(result *analyzer.RuleResult, w io.Write
 		fmt.Fprintf(w, "  %s\n", result.Description)
 	}
 
+	if result.Severity != "" && f.opts.Verbose {
+		tags := ""
+		if len(result.Tags) > 0 {
+			tags = fmt.Sprintf(", tags: %s", strings.Join(result.Tags, ", "))
+		}
+		fmt.Fprintf(w, "  Severity: %s%s\n", result.Severity, tags)
+	}
+
+	if result.Stats.Pending > 0 && f.opts.Verbose {
+		fmt.Fprintf(w, "  Pending: %d (not yet timed out)\n", result.Stats.Pending)
+	}
+
+	if lat := result.Stats.Latency; lat != nil && f.opts.Verbose {
+		fmt.Fprintf(w, "  Completed: %d, latency min %s, p50 %s, p95 %s, p99 %s, max %s\n",
+			result.Stats.Completed,
+			lat.Min.Round(1e6),
+			lat.P50.Round(1e6),
+			lat.P95.Round(1e6),
+			lat.P99.Round(1e6),
+			lat.Max.Round(1e6))
+	}
+
 	if !result.HasIssues() {
 		fmt.Fprintln(w, "  No issues detected")
-		fmt.Fprintln(w)
-		return nil
-	}
+	} else {
+		// Issue count summary
+		fmt.Fprintf(w, "  Missing: %d issue(s)\n", len(result.Issues))
 
-	// Issue count summary
-	fmt.Fprintf(w, "  Missing: %d issue(s)\n", len(result.Issues))
+		// Individual issues
+		for _, issue := range result.Issues {
+			f.formatIssue(&issue, w)
+		}
+	}
 
-	// Individual issues
-	for _, issue := range result.Issues {
-		f.formatIssue(&issue, w)
+	if len(result.Suppressed) > 0 {
+		fmt.Fprintf(w, "  Suppressed: %d issue(s) during maintenance\n", len(result.Suppressed))
+		if f.opts.Verbose {
+			for _, issue := range result.Suppressed {
+				f.formatIssue(&issue, w)
+				fmt.Fprintf(w, "    Suppressed by: %s\n", issue.SuppressedBy)
+			}
+		}
 	}
 
 	fmt.Fprintln(w)
@@ -98,12 +138,52 @@ func (f *TextFormatter) formatIssue(issue *analyzer.Issue, w io.Writer) {
 	switch issue.Type {
 	case analyzer.IssueTypeMissingEnd:
 		f.formatMissingEnd(issue, w)
+	case analyzer.IssueTypeLateEnd:
+		f.formatLateEnd(issue, w)
+	case analyzer.IssueTypeMissingStep:
+		f.formatMissingStep(issue, w)
+	case analyzer.IssueTypeStepOutOfOrder:
+		f.formatStepOutOfOrder(issue, w)
+	case analyzer.IssueTypeOrphanEnd:
+		f.formatOrphanEnd(issue, w)
+	case analyzer.IssueTypeDuplicateStart:
+		f.formatDuplicateStart(issue, w)
+	case analyzer.IssueTypeExcessiveRetries:
+		f.formatExcessiveRetries(issue, w)
 	case analyzer.IssueTypeGapExceeded:
 		f.formatGapExceeded(issue, w)
+	case analyzer.IssueTypeLeadingSilence:
+		f.formatLeadingSilence(issue, w)
+	case analyzer.IssueTypeTrailingSilence:
+		f.formatTrailingSilence(issue, w)
+	case analyzer.IssueTypeAbsentMember:
+		f.formatAbsentMember(issue, w)
+	case analyzer.IssueTypeMissedSlot:
+		f.formatMissedSlot(issue, w)
+	case analyzer.IssueTypeUnexpectedRun:
+		f.formatUnexpectedRun(issue, w)
+	case analyzer.IssueTypeBelowBaseline:
+		f.formatBelowBaseline(issue, w)
+	case analyzer.IssueTypeSourceSilence:
+		f.formatSourceSilence(issue, w)
+	case analyzer.IssueTypeEmptySource:
+		f.formatEmptySource(issue, w)
+	case analyzer.IssueTypeOrderViolation:
+		f.formatOrderViolation(issue, w)
+	case analyzer.IssueTypeCountMismatch:
+		f.formatCountMismatch(issue, w)
 	case analyzer.IssueTypeMissingConsequence:
 		f.formatMissingConsequence(issue, w)
+	case analyzer.IssueTypeConsequenceCount:
+		f.formatConsequenceCount(issue, w)
+	case analyzer.IssueTypeForbiddenEvent:
+		f.formatForbiddenEvent(issue, w)
+	case analyzer.IssueTypeUnprecededEvent:
+		f.formatUnprecededEvent(issue, w)
 	case analyzer.IssueTypeBelowMinOccurrences:
 		f.formatBelowMinOccurrences(issue, w)
+	case analyzer.IssueTypeBelowMinRate:
+		f.formatBelowMinRate(issue, w)
 	default:
 		fmt.Fprintf(w, "  - %s\n", issue.Description)
 	}
@@ -127,9 +207,114 @@ func (f *TextFormatter) formatMissingEnd(issue *analyzer.Issue, w io.Writer) {
 	}
 }
 
+func (f *TextFormatter) formatLateEnd(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	if ctx.CorrelationID != "" {
+		fmt.Fprintf(w, "  - id=%s: started at %s, ended late at %s after %s (timeout: %s)\n",
+			ctx.CorrelationID,
+			ctx.StartTime.Format("15:04:05"),
+			ctx.EndTime.Format("15:04:05"),
+			ctx.Duration,
+			ctx.Timeout)
+	} else {
+		fmt.Fprintf(w, "  - started at %s, ended late at %s after %s (timeout: %s)\n",
+			ctx.StartTime.Format("15:04:05"),
+			ctx.EndTime.Format("15:04:05"),
+			ctx.Duration,
+			ctx.Timeout)
+	}
+
+	// Within the overall timeout, so a step timeout was exceeded
+	if ctx.Duration <= ctx.Timeout {
+		fmt.Fprintf(w, "    %s\n", issue.Description)
+	}
+
+	if f.opts.Verbose {
+		fmt.Fprintf(w, "    Source: %s:%d\n", ctx.Source, ctx.LineNum)
+		fmt.Fprintf(w, "    End: %s:%d\n", ctx.EndSource, ctx.EndLineNum)
+	}
+}
+
+func (f *TextFormatter) formatMissingStep(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	fmt.Fprintf(w, "  - id=%s: started at %s, reached %s, missing %s (timeout: %s)\n",
+		ctx.CorrelationID,
+		ctx.StartTime.Format("15:04:05"),
+		ctx.LastStep,
+		ctx.MissingStep,
+		ctx.Timeout)
+
+	if f.opts.Verbose {
+		fmt.Fprintf(w, "    Source: %s:%d\n", ctx.Source, ctx.LineNum)
+	}
+}
+
+func (f *TextFormatter) formatStepOutOfOrder(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	fmt.Fprintf(w, "  - id=%s at %s: %s (last in order: %s)\n",
+		ctx.CorrelationID,
+		ctx.EndTime.Format("15:04:05"),
+		issue.Description,
+		ctx.LastStep)
+
+	if f.opts.Verbose {
+		fmt.Fprintf(w, "    Source: %s:%d\n", ctx.Source, ctx.LineNum)
+	}
+}
+
+func (f *TextFormatter) formatOrphanEnd(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	note := ""
+	if ctx.PossiblyTruncated {
+		note = " (possibly truncated input)"
+	}
+	fmt.Fprintf(w, "  - id=%s: ended at %s, no start%s\n",
+		ctx.CorrelationID,
+		ctx.EndTime.Format("15:04:05"),
+		note)
+
+	if f.opts.Verbose {
+		fmt.Fprintf(w, "    Source: %s:%d\n", ctx.Source, ctx.LineNum)
+	}
+}
+
+func (f *TextFormatter) formatDuplicateStart(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	fmt.Fprintf(w, "  - id=%s: started again at %s, already open since %s\n",
+		ctx.CorrelationID,
+		ctx.EndTime.Format("15:04:05"),
+		ctx.StartTime.Format("15:04:05"))
+
+	if f.opts.Verbose {
+		fmt.Fprintf(w, "    Source: %s:%d\n", ctx.Source, ctx.LineNum)
+	}
+}
+
+func (f *TextFormatter) formatExcessiveRetries(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	fmt.Fprintf(w, "  - id=%s: retried %d times since %s (max allowed: %d)\n",
+		ctx.CorrelationID,
+		ctx.Occurrences,
+		ctx.StartTime.Format("15:04:05"),
+		ctx.MaxAllowed)
+
+	if f.opts.Verbose {
+		fmt.Fprintf(w, "    Source: %s:%d\n", ctx.Source, ctx.LineNum)
+	}
+}
+
+// groupPrefix labels a periodic issue with its group_by value, if any.
+func groupPrefix(ctx analyzer.IssueContext) string {
+	if ctx.CorrelationID == "" {
+		return ""
+	}
+	return fmt.Sprintf("id=%s: ", ctx.CorrelationID)
+}
+
 func (f *TextFormatter) formatGapExceeded(issue *analyzer.Issue, w io.Writer) {
 	ctx := issue.Context
-	fmt.Fprintf(w, "  - Gap of %s between %s and %s (max allowed: %s)\n",
+	fmt.Fprintf(w, "  - %sGap of %s between %s and %s (max allowed: %s)\n",
+		groupPrefix(ctx),
 		ctx.ActualGap.Round(1e9),
 		ctx.StartTime.Format("15:04:05"),
 		ctx.EndTime.Format("15:04:05"),
@@ -140,6 +325,132 @@ func (f *TextFormatter) formatGapExceeded(issue *analyzer.Issue, w io.Writer) {
 	}
 }
 
+func (f *TextFormatter) formatLeadingSilence(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	if ctx.LineNum == 0 {
+		// Nothing matched in the whole window
+		fmt.Fprintf(w, "  - No occurrence between %s and %s (%s, max allowed: %s)\n",
+			ctx.StartTime.Format("15:04:05"),
+			ctx.EndTime.Format("15:04:05"),
+			ctx.ActualGap.Round(1e9),
+			ctx.ExpectedGap)
+		return
+	}
+
+	fmt.Fprintf(w, "  - No occurrence from window start %s until %s (%s, max allowed: %s)\n",
+		ctx.StartTime.Format("15:04:05"),
+		ctx.EndTime.Format("15:04:05"),
+		ctx.ActualGap.Round(1e9),
+		ctx.ExpectedGap)
+
+	if f.opts.Verbose {
+		fmt.Fprintf(w, "    Source: %s:%d\n", ctx.Source, ctx.LineNum)
+	}
+}
+
+func (f *TextFormatter) formatTrailingSilence(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	fmt.Fprintf(w, "  - %sLast occurrence at %s, silent until window end %s (%s, max allowed: %s)\n",
+		groupPrefix(ctx),
+		ctx.StartTime.Format("15:04:05"),
+		ctx.EndTime.Format("15:04:05"),
+		ctx.ActualGap.Round(1e9),
+		ctx.ExpectedGap)
+
+	if f.opts.Verbose {
+		fmt.Fprintf(w, "    Source: %s:%d\n", ctx.Source, ctx.LineNum)
+	}
+}
+
+func (f *TextFormatter) formatAbsentMember(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	if ctx.StartTime.IsZero() {
+		fmt.Fprintf(w, "  - id=%s: expected roster member, never seen\n", ctx.CorrelationID)
+		return
+	}
+
+	fmt.Fprintf(w, "  - id=%s: expected roster member, not seen between %s and %s\n",
+		ctx.CorrelationID,
+		ctx.StartTime.Format("15:04:05"),
+		ctx.EndTime.Format("15:04:05"))
+}
+
+func (f *TextFormatter) formatMissedSlot(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	fmt.Fprintf(w, "  - %sMissed scheduled run at %s (tolerance: %s)\n",
+		groupPrefix(ctx),
+		ctx.ScheduledTime.Format("2006-01-02 15:04 MST"),
+		ctx.Tolerance)
+}
+
+func (f *TextFormatter) formatUnexpectedRun(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	fmt.Fprintf(w, "  - %sUnexpected run at %s, not near any scheduled time (tolerance: %s)\n",
+		groupPrefix(ctx),
+		ctx.StartTime.Format("2006-01-02 15:04:05"),
+		ctx.Tolerance)
+
+	if f.opts.Verbose {
+		fmt.Fprintf(w, "    Source: %s:%d\n", ctx.Source, ctx.LineNum)
+	}
+}
+
+func (f *TextFormatter) formatBelowBaseline(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	fmt.Fprintf(w, "  - Only %d occurrences between %s and %s (baseline: %.1f, minimum: %d)\n",
+		ctx.Occurrences,
+		ctx.StartTime.Format("15:04:05"),
+		ctx.EndTime.Format("15:04:05"),
+		ctx.Baseline,
+		ctx.MinRequired)
+}
+
+func (f *TextFormatter) formatSourceSilence(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	name := ctx.Source
+	if ctx.CorrelationID != "" {
+		name = "id=" + ctx.CorrelationID
+	}
+	fmt.Fprintf(w, "  - %s: silent for %s between %s and %s (max allowed: %s)\n",
+		name,
+		ctx.ActualGap.Round(1e9),
+		ctx.StartTime.Format("15:04:05"),
+		ctx.EndTime.Format("15:04:05"),
+		ctx.ExpectedGap)
+
+	if f.opts.Verbose && ctx.LineNum > 0 {
+		fmt.Fprintf(w, "    Last line: %s:%d\n", ctx.Source, ctx.LineNum)
+	}
+}
+
+func (f *TextFormatter) formatEmptySource(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	fmt.Fprintf(w, "  - %s%s: no parseable log lines\n", groupPrefix(ctx), ctx.Source)
+}
+
+func (f *TextFormatter) formatOrderViolation(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	fmt.Fprintf(w, "  - %safter_pattern at %s (%s:%d) came before before_pattern at %s (%s:%d)\n",
+		groupPrefix(ctx),
+		ctx.StartTime.Format("15:04:05"),
+		ctx.Source,
+		ctx.LineNum,
+		ctx.EndTime.Format("15:04:05"),
+		ctx.EndSource,
+		ctx.EndLineNum)
+}
+
+func (f *TextFormatter) formatCountMismatch(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	fmt.Fprintf(w, "  - %s%s to %s: left %d, right %d (difference: %d)\n",
+		groupPrefix(ctx),
+		ctx.StartTime.Format("15:04:05"),
+		ctx.EndTime.Format("15:04:05"),
+		ctx.LeftCount,
+		ctx.RightCount,
+		ctx.LeftCount-ctx.RightCount)
+}
+
 func (f *TextFormatter) formatMissingConsequence(issue *analyzer.Issue, w io.Writer) {
 	ctx := issue.Context
 	if ctx.CorrelationID != "" {
@@ -158,8 +469,83 @@ func (f *TextFormatter) formatMissingConsequence(issue *analyzer.Issue, w io.Wri
 	}
 }
 
+func (f *TextFormatter) formatConsequenceCount(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	if ctx.InvalidCount != "" {
+		fmt.Fprintf(w, "  - trigger id=%s at %s: invalid expected count %q\n",
+			ctx.CorrelationID,
+			ctx.StartTime.Format("15:04:05"),
+			ctx.InvalidCount)
+	} else {
+		max := ctx.MaxAllowed
+		if ctx.NoMaxAllowed {
+			max = -1
+		}
+		fmt.Fprintf(w, "  - trigger id=%s at %s: %d expected events within %s, want %s\n",
+			ctx.CorrelationID,
+			ctx.StartTime.Format("15:04:05"),
+			ctx.Occurrences,
+			ctx.Timeout,
+			analyzer.CountBounds(ctx.MinRequired, max))
+	}
+
+	if f.opts.Verbose {
+		fmt.Fprintf(w, "    Source: %s:%d\n", ctx.Source, ctx.LineNum)
+	}
+}
+
+func (f *TextFormatter) formatForbiddenEvent(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	if ctx.CorrelationID != "" {
+		fmt.Fprintf(w, "  - trigger id=%s at %s: forbidden event at %s, %s later (window: %s)\n",
+			ctx.CorrelationID,
+			ctx.StartTime.Format("15:04:05"),
+			ctx.EndTime.Format("15:04:05"),
+			ctx.Duration,
+			ctx.Timeout)
+	} else {
+		fmt.Fprintf(w, "  - trigger at %s: forbidden event at %s, %s later (window: %s)\n",
+			ctx.StartTime.Format("15:04:05"),
+			ctx.EndTime.Format("15:04:05"),
+			ctx.Duration,
+			ctx.Timeout)
+	}
+
+	if f.opts.Verbose {
+		fmt.Fprintf(w, "    Source: %s:%d\n", ctx.Source, ctx.LineNum)
+		fmt.Fprintf(w, "    Forbidden: %s:%d\n", ctx.EndSource, ctx.EndLineNum)
+	}
+}
+
+func (f *TextFormatter) formatUnprecededEvent(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	if ctx.CorrelationID != "" {
+		fmt.Fprintf(w, "  - event id=%s at %s: not preceded within %s\n",
+			ctx.CorrelationID,
+			ctx.StartTime.Format("15:04:05"),
+			ctx.Timeout)
+	} else {
+		fmt.Fprintf(w, "  - event at %s: not preceded within %s\n",
+			ctx.StartTime.Format("15:04:05"),
+			ctx.Timeout)
+	}
+
+	if f.opts.Verbose {
+		fmt.Fprintf(w, "    Source: %s:%d\n", ctx.Source, ctx.LineNum)
+	}
+}
+
 func (f *TextFormatter) formatBelowMinOccurrences(issue *analyzer.Issue, w io.Writer) {
 	ctx := issue.Context
 	fmt.Fprintf(w, "  - Only %d occurrences (minimum required: %d)\n",
 		ctx.Occurrences, ctx.MinRequired)
 }
+
+func (f *TextFormatter) formatBelowMinRate(issue *analyzer.Issue, w io.Writer) {
+	ctx := issue.Context
+	fmt.Fprintf(w, "  - As few as %d occurrences per window between %s and %s (minimum required: %d)\n",
+		ctx.Occurrences,
+		ctx.StartTime.Format("15:04:05"),
+		ctx.EndTime.Format("15:04:05"),
+		ctx.MinRequired)
+}
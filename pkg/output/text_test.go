@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/ccollicutt/negalog/pkg/analyzer"
+	"github.com/ccollicutt/negalog/pkg/config"
 )
 
 func TestNewTextFormatter(t *testing.T) {
@@ -93,6 +94,55 @@ func TestTextFormatter_Format_Quiet(t *testing.T) {
 	}
 }
 
+func TestTextFormatter_Format_Suppressed(t *testing.T) {
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	report := &Report{
+		Summary: Summary{RulesChecked: 1, SuppressedIssues: 1},
+		Results: []*analyzer.RuleResult{{
+			RuleName: "heartbeat",
+			RuleType: analyzer.RuleTypePeriodic,
+			Issues:   []analyzer.Issue{},
+			Suppressed: []analyzer.Issue{{
+				Type: analyzer.IssueTypeGapExceeded,
+				Context: analyzer.IssueContext{
+					StartTime:   baseTime,
+					EndTime:     baseTime.Add(20 * time.Minute),
+					ActualGap:   20 * time.Minute,
+					ExpectedGap: 5 * time.Minute,
+				},
+				SuppressedBy: "nightly-backup",
+			}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := NewTextFormatter(FormatOptions{Verbose: true}).Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, check := range []string{
+		"No issues detected",
+		"Suppressed: 1 issue(s) during maintenance",
+		"Gap of 20m0s between 10:00:00 and 10:20:00",
+		"Suppressed by: nightly-backup",
+		"0 total issues, 1 suppressed",
+	} {
+		if !strings.Contains(output, check) {
+			t.Errorf("Output missing %q:\n%s", check, output)
+		}
+	}
+
+	buf.Reset()
+	if err := NewTextFormatter(FormatOptions{Quiet: true}).Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "0 total issues, 1 suppressed") {
+		t.Errorf("Quiet output missing suppressed count: %s", buf.String())
+	}
+}
+
 func TestTextFormatter_Format_Verbose(t *testing.T) {
 	f := NewTextFormatter(FormatOptions{Verbose: true})
 	report := createTestReport()
@@ -114,6 +164,10 @@ func TestTextFormatter_Format_Verbose(t *testing.T) {
 	if !strings.Contains(output, "Duration:") {
 		t.Error("Verbose output missing duration")
 	}
+
+	if !strings.Contains(output, "Severity: critical, tags: orders, prod") {
+		t.Errorf("Verbose output missing severity and tags:\n%s", output)
+	}
 }
 
 func TestTextFormatter_Format_AllIssueTypes(t *testing.T) {
@@ -205,6 +259,740 @@ func TestTextFormatter_Format_AllIssueTypes(t *testing.T) {
 	}
 }
 
+func TestTextFormatter_Format_BelowMinRate(t *testing.T) {
+	f := NewTextFormatter(FormatOptions{})
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	report := &Report{
+		Summary: Summary{RulesChecked: 1, RulesWithIssues: 1, TotalIssues: 1},
+		Results: []*analyzer.RuleResult{{
+			RuleName: "order-volume",
+			RuleType: analyzer.RuleTypeRate,
+			Issues: []analyzer.Issue{{
+				Type: analyzer.IssueTypeBelowMinRate,
+				Context: analyzer.IssueContext{
+					StartTime:   baseTime,
+					EndTime:     baseTime.Add(5 * time.Minute),
+					Occurrences: 120,
+					MinRequired: 500,
+				},
+			}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := f.Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, check := range []string{
+		"[RATE] order-volume",
+		"As few as 120 occurrences per window between 10:00:00 and 10:05:00 (minimum required: 500)",
+	} {
+		if !strings.Contains(output, check) {
+			t.Errorf("Output missing %q:\n%s", check, output)
+		}
+	}
+}
+
+func TestTextFormatter_Format_BelowBaseline(t *testing.T) {
+	f := NewTextFormatter(FormatOptions{})
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	report := &Report{
+		Summary: Summary{RulesChecked: 1, RulesWithIssues: 1, TotalIssues: 1},
+		Results: []*analyzer.RuleResult{{
+			RuleName: "order-volume",
+			RuleType: analyzer.RuleTypeBaseline,
+			Issues: []analyzer.Issue{{
+				Type: analyzer.IssueTypeBelowBaseline,
+				Context: analyzer.IssueContext{
+					StartTime:   baseTime,
+					EndTime:     baseTime.Add(time.Hour),
+					Occurrences: 310,
+					MinRequired: 520,
+					Baseline:    1040.25,
+				},
+			}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := f.Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, check := range []string{
+		"[BASELINE] order-volume",
+		"Only 310 occurrences between 10:00:00 and 11:00:00 (baseline: 1040.2, minimum: 520)",
+	} {
+		if !strings.Contains(output, check) {
+			t.Errorf("Output missing %q:\n%s", check, output)
+		}
+	}
+}
+
+func TestTextFormatter_Format_CountMismatch(t *testing.T) {
+	f := NewTextFormatter(FormatOptions{})
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	report := &Report{
+		Summary: Summary{RulesChecked: 1, RulesWithIssues: 1, TotalIssues: 1},
+		Results: []*analyzer.RuleResult{{
+			RuleName: "queue-balance",
+			RuleType: analyzer.RuleTypeReconciliation,
+			Issues: []analyzer.Issue{{
+				Type: analyzer.IssueTypeCountMismatch,
+				Context: analyzer.IssueContext{
+					CorrelationID: "orders",
+					StartTime:     baseTime,
+					EndTime:       baseTime.Add(time.Hour),
+					LeftCount:     1200,
+					RightCount:    1150,
+				},
+			}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := f.Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, check := range []string{
+		"[RECONCILIATION] queue-balance",
+		"id=orders: 10:00:00 to 11:00:00: left 1200, right 1150 (difference: 50)",
+	} {
+		if !strings.Contains(output, check) {
+			t.Errorf("Output missing %q:\n%s", check, output)
+		}
+	}
+}
+
+func TestTextFormatter_Format_SourceSilence(t *testing.T) {
+	f := NewTextFormatter(FormatOptions{Verbose: true})
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	report := &Report{
+		Summary: Summary{RulesChecked: 1, RulesWithIssues: 1, TotalIssues: 3},
+		Results: []*analyzer.RuleResult{{
+			RuleName: "sources-alive",
+			RuleType: analyzer.RuleTypeSourceSilence,
+			Issues: []analyzer.Issue{
+				{
+					Type: analyzer.IssueTypeSourceSilence,
+					Context: analyzer.IssueContext{
+						StartTime:   baseTime,
+						EndTime:     baseTime.Add(20 * time.Minute),
+						ActualGap:   20 * time.Minute,
+						ExpectedGap: 15 * time.Minute,
+						Source:      "/var/log/worker.log",
+						LineNum:     42,
+					},
+				},
+				{
+					Type: analyzer.IssueTypeSourceSilence,
+					Context: analyzer.IssueContext{
+						CorrelationID: "db",
+						StartTime:     baseTime,
+						EndTime:       baseTime.Add(time.Hour),
+						ActualGap:     time.Hour,
+						ExpectedGap:   15 * time.Minute,
+					},
+				},
+				{
+					Type:    analyzer.IssueTypeEmptySource,
+					Context: analyzer.IssueContext{Source: "/var/log/empty.log"},
+				},
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := f.Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, check := range []string{
+		"[SOURCE_SILENCE] sources-alive",
+		"/var/log/worker.log: silent for 20m0s between 10:00:00 and 10:20:00 (max allowed: 15m0s)",
+		"Last line: /var/log/worker.log:42",
+		"id=db: silent for 1h0m0s between 10:00:00 and 11:00:00",
+		"/var/log/empty.log: no parseable log lines",
+	} {
+		if !strings.Contains(output, check) {
+			t.Errorf("Output missing %q:\n%s", check, output)
+		}
+	}
+}
+
+func TestTextFormatter_Format_OrderViolation(t *testing.T) {
+	f := NewTextFormatter(FormatOptions{})
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	report := &Report{
+		Summary: Summary{RulesChecked: 1, RulesWithIssues: 1, TotalIssues: 1},
+		Results: []*analyzer.RuleResult{{
+			RuleName: "prepare-before-commit",
+			RuleType: analyzer.RuleTypeOrdering,
+			Issues: []analyzer.Issue{{
+				Type: analyzer.IssueTypeOrderViolation,
+				Context: analyzer.IssueContext{
+					CorrelationID: "42",
+					StartTime:     baseTime,
+					EndTime:       baseTime.Add(2 * time.Second),
+					Source:        "db.log",
+					LineNum:       12,
+					EndSource:     "coordinator.log",
+					EndLineNum:    7,
+				},
+			}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := f.Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, check := range []string{
+		"[ORDERING] prepare-before-commit",
+		"id=42: after_pattern at 10:00:00 (db.log:12) came before before_pattern at 10:00:02 (coordinator.log:7)",
+	} {
+		if !strings.Contains(output, check) {
+			t.Errorf("Output missing %q:\n%s", check, output)
+		}
+	}
+}
+
+func TestTextFormatter_Format_ForbiddenEvent(t *testing.T) {
+	f := NewTextFormatter(FormatOptions{Verbose: true})
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	report := &Report{
+		Summary: Summary{RulesChecked: 1, RulesWithIssues: 1, TotalIssues: 1},
+		Results: []*analyzer.RuleResult{{
+			RuleName: "no-double-charge",
+			RuleType: analyzer.RuleTypeConditional,
+			Issues: []analyzer.Issue{{
+				Type: analyzer.IssueTypeForbiddenEvent,
+				Context: analyzer.IssueContext{
+					CorrelationID: "p42",
+					StartTime:     baseTime,
+					EndTime:       baseTime.Add(90 * time.Second),
+					Source:        "payments.log",
+					LineNum:       10,
+					EndSource:     "payments.log",
+					EndLineNum:    14,
+					Duration:      90 * time.Second,
+					Timeout:       10 * time.Minute,
+				},
+			}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := f.Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, check := range []string{
+		"trigger id=p42 at 10:00:00: forbidden event at 10:01:30, 1m30s later (window: 10m0s)",
+		"Source: payments.log:10",
+		"Forbidden: payments.log:14",
+	} {
+		if !strings.Contains(output, check) {
+			t.Errorf("Output missing %q:\n%s", check, output)
+		}
+	}
+}
+
+func TestTextFormatter_Format_ConsequenceCount(t *testing.T) {
+	f := NewTextFormatter(FormatOptions{})
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	issue := func(id string, count, min, max int) analyzer.Issue {
+		return analyzer.Issue{
+			Type: analyzer.IssueTypeConsequenceCount,
+			Context: analyzer.IssueContext{
+				CorrelationID: id,
+				StartTime:     baseTime,
+				Timeout:       10 * time.Minute,
+				Occurrences:   count,
+				MinRequired:   min,
+				MaxAllowed:    max,
+				NoMaxAllowed:  max < 0,
+			},
+		}
+	}
+	invalid := issue("b6", 0, 0, 0)
+	invalid.Context.InvalidCount = "many"
+
+	report := &Report{
+		Summary: Summary{RulesChecked: 1, RulesWithIssues: 1, TotalIssues: 6},
+		Results: []*analyzer.RuleResult{{
+			RuleName: "batches",
+			RuleType: analyzer.RuleTypeConditional,
+			Issues: []analyzer.Issue{
+				issue("b1", 2, 3, 3),
+				issue("b2", 1, 3, -1),
+				issue("b3", 4, 0, 2),
+				issue("b4", 6, 2, 5),
+				issue("b5", 1, 0, 0),
+				invalid,
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := f.Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, check := range []string{
+		"trigger id=b1 at 10:00:00: 2 expected events within 10m0s, want exactly 3",
+		"trigger id=b2 at 10:00:00: 1 expected events within 10m0s, want at least 3",
+		"trigger id=b3 at 10:00:00: 4 expected events within 10m0s, want at most 2",
+		"trigger id=b4 at 10:00:00: 6 expected events within 10m0s, want 2 to 5",
+		"trigger id=b5 at 10:00:00: 1 expected events within 10m0s, want exactly 0",
+		`trigger id=b6 at 10:00:00: invalid expected count "many"`,
+	} {
+		if !strings.Contains(output, check) {
+			t.Errorf("Output missing %q:\n%s", check, output)
+		}
+	}
+}
+
+func TestTextFormatter_Format_UnprecededEvent(t *testing.T) {
+	f := NewTextFormatter(FormatOptions{Verbose: true})
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	report := &Report{
+		Summary: Summary{RulesChecked: 1, RulesWithIssues: 1, TotalIssues: 1},
+		Results: []*analyzer.RuleResult{{
+			RuleName: "approved-refunds",
+			RuleType: analyzer.RuleTypeConditional,
+			Issues: []analyzer.Issue{{
+				Type: analyzer.IssueTypeUnprecededEvent,
+				Context: analyzer.IssueContext{
+					CorrelationID: "o17",
+					StartTime:     baseTime,
+					Source:        "refunds.log",
+					LineNum:       3,
+					Timeout:       24 * time.Hour,
+				},
+			}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := f.Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, check := range []string{
+		"event id=o17 at 10:00:00: not preceded within 24h0m0s",
+		"Source: refunds.log:3",
+	} {
+		if !strings.Contains(output, check) {
+			t.Errorf("Output missing %q:\n%s", check, output)
+		}
+	}
+}
+
+func TestTextFormatter_Format_SequenceSteps(t *testing.T) {
+	f := NewTextFormatter(FormatOptions{})
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	report := &Report{
+		Summary: Summary{RulesChecked: 1, RulesWithIssues: 1, TotalIssues: 2},
+		Results: []*analyzer.RuleResult{{
+			RuleName: "order-flow",
+			RuleType: analyzer.RuleTypeSequence,
+			Issues: []analyzer.Issue{
+				{
+					Type: analyzer.IssueTypeMissingStep,
+					Context: analyzer.IssueContext{
+						CorrelationID: "o1",
+						StartTime:     baseTime,
+						LastStep:      "payment",
+						MissingStep:   "inventory",
+						Timeout:       60 * time.Second,
+					},
+				},
+				{
+					Type:        analyzer.IssueTypeStepOutOfOrder,
+					Description: `Step "inventory" seen before step "payment"`,
+					Context: analyzer.IssueContext{
+						CorrelationID: "o2",
+						StartTime:     baseTime,
+						EndTime:       baseTime.Add(time.Second),
+						LastStep:      "created",
+						MissingStep:   "payment",
+					},
+				},
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := f.Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := buf.String()
+	checks := []string{
+		"reached payment, missing inventory",
+		`Step "inventory" seen before step "payment"`,
+	}
+	for _, check := range checks {
+		if !strings.Contains(output, check) {
+			t.Errorf("Output missing %q", check)
+		}
+	}
+}
+
+func TestTextFormatter_Format_OrphanEnd(t *testing.T) {
+	f := NewTextFormatter(FormatOptions{})
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	report := &Report{
+		Summary: Summary{RulesChecked: 1, RulesWithIssues: 1, TotalIssues: 1},
+		Results: []*analyzer.RuleResult{{
+			RuleName: "request-flow",
+			RuleType: analyzer.RuleTypeSequence,
+			Issues: []analyzer.Issue{{
+				Type: analyzer.IssueTypeOrphanEnd,
+				Context: analyzer.IssueContext{
+					CorrelationID:     "r1",
+					EndTime:           baseTime,
+					PossiblyTruncated: true,
+				},
+			}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := f.Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "id=r1: ended at 10:00:00, no start (possibly truncated input)") {
+		t.Errorf("Output missing orphan end line:\n%s", buf.String())
+	}
+}
+
+func TestTextFormatter_Format_LateEnd(t *testing.T) {
+	f := NewTextFormatter(FormatOptions{Verbose: true})
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	report := &Report{
+		Summary: Summary{RulesChecked: 1, RulesWithIssues: 1, TotalIssues: 2},
+		Results: []*analyzer.RuleResult{{
+			RuleName: "request-flow",
+			RuleType: analyzer.RuleTypeSequence,
+			Issues: []analyzer.Issue{
+				{
+					Type: analyzer.IssueTypeLateEnd,
+					Context: analyzer.IssueContext{
+						CorrelationID: "r1",
+						StartTime:     baseTime,
+						EndTime:       baseTime.Add(61 * time.Second),
+						Source:        "app.log",
+						LineNum:       3,
+						EndSource:     "app.log",
+						EndLineNum:    42,
+						Duration:      61 * time.Second,
+						Timeout:       60 * time.Second,
+					},
+				},
+				{
+					Type: analyzer.IssueTypeMissingEnd,
+					Context: analyzer.IssueContext{
+						CorrelationID: "r2",
+						StartTime:     baseTime,
+						Timeout:       60 * time.Second,
+					},
+				},
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := f.Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := buf.String()
+	checks := []string{
+		"id=r1: started at 10:00:00, ended late at 10:01:01 after 1m1s (timeout: 1m0s)",
+		"Source: app.log:3",
+		"End: app.log:42",
+		"id=r2: started at 10:00:00, no end (timeout: 1m0s)",
+	}
+	for _, check := range checks {
+		if !strings.Contains(output, check) {
+			t.Errorf("Output missing %q:\n%s", check, output)
+		}
+	}
+}
+
+func TestTextFormatter_Format_Latency(t *testing.T) {
+	report := &Report{
+		Summary: Summary{RulesChecked: 1},
+		Results: []*analyzer.RuleResult{{
+			RuleName: "request-flow",
+			RuleType: analyzer.RuleTypeSequence,
+			Stats: analyzer.RuleStats{
+				Completed: 120,
+				Latency: &analyzer.LatencyStats{
+					Min: 250 * time.Millisecond,
+					P50: 2 * time.Second,
+					P95: 40 * time.Second,
+					P99: 57 * time.Second,
+					Max: 59 * time.Second,
+				},
+			},
+		}},
+	}
+
+	want := "Completed: 120, latency min 250ms, p50 2s, p95 40s, p99 57s, max 59s"
+
+	var buf bytes.Buffer
+	if err := NewTextFormatter(FormatOptions{Verbose: true}).Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("Verbose output missing %q:\n%s", want, buf.String())
+	}
+
+	buf.Reset()
+	if err := NewTextFormatter(FormatOptions{}).Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "latency") {
+		t.Errorf("Non-verbose output should not show latency:\n%s", buf.String())
+	}
+}
+
+func TestTextFormatter_Format_Silence(t *testing.T) {
+	f := NewTextFormatter(FormatOptions{})
+
+	baseTime := time.Date(2024, 1, 19, 18, 0, 0, 0, time.UTC)
+
+	report := &Report{
+		Summary: Summary{RulesChecked: 2, RulesWithIssues: 2, TotalIssues: 2},
+		Results: []*analyzer.RuleResult{
+			{
+				RuleName: "nightly-job",
+				RuleType: analyzer.RuleTypePeriodic,
+				Issues: []analyzer.Issue{{
+					Type: analyzer.IssueTypeTrailingSilence,
+					Context: analyzer.IssueContext{
+						CorrelationID: "web-3",
+						StartTime:     baseTime,
+						EndTime:       baseTime.Add(3 * time.Hour),
+						LineNum:       12,
+						ActualGap:     3 * time.Hour,
+						ExpectedGap:   time.Hour,
+					},
+				}},
+			},
+			{
+				RuleName: "heartbeat",
+				RuleType: analyzer.RuleTypePeriodic,
+				Issues: []analyzer.Issue{{
+					Type: analyzer.IssueTypeLeadingSilence,
+					Context: analyzer.IssueContext{
+						StartTime:   baseTime,
+						EndTime:     baseTime.Add(time.Hour),
+						ActualGap:   time.Hour,
+						ExpectedGap: 5 * time.Minute,
+					},
+				}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := f.Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := buf.String()
+	checks := []string{
+		"id=web-3: Last occurrence at 18:00:00, silent until window end 21:00:00 (3h0m0s, max allowed: 1h0m0s)",
+		"No occurrence between 18:00:00 and 19:00:00 (1h0m0s, max allowed: 5m0s)",
+	}
+	for _, check := range checks {
+		if !strings.Contains(output, check) {
+			t.Errorf("Output missing %q:\n%s", check, output)
+		}
+	}
+}
+
+func TestTextFormatter_Format_Schedule(t *testing.T) {
+	f := NewTextFormatter(FormatOptions{})
+
+	slot := time.Date(2024, 1, 16, 2, 0, 0, 0, time.UTC)
+
+	report := &Report{
+		Summary: Summary{RulesChecked: 1, RulesWithIssues: 1, TotalIssues: 2},
+		Results: []*analyzer.RuleResult{{
+			RuleName: "nightly-sync",
+			RuleType: analyzer.RuleTypePeriodic,
+			Issues: []analyzer.Issue{
+				{
+					Type: analyzer.IssueTypeMissedSlot,
+					Context: analyzer.IssueContext{
+						ScheduledTime: slot,
+						Tolerance:     15 * time.Minute,
+					},
+				},
+				{
+					Type: analyzer.IssueTypeUnexpectedRun,
+					Context: analyzer.IssueContext{
+						StartTime: slot.Add(12 * time.Hour),
+						Tolerance: 15 * time.Minute,
+					},
+				},
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := f.Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := buf.String()
+	checks := []string{
+		"Missed scheduled run at 2024-01-16 02:00 UTC (tolerance: 15m0s)",
+		"Unexpected run at 2024-01-16 14:00:00, not near any scheduled time (tolerance: 15m0s)",
+	}
+	for _, check := range checks {
+		if !strings.Contains(output, check) {
+			t.Errorf("Output missing %q:\n%s", check, output)
+		}
+	}
+}
+
+func TestTextFormatter_Format_AbsentMember(t *testing.T) {
+	f := NewTextFormatter(FormatOptions{})
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	report := &Report{
+		Summary: Summary{RulesChecked: 2, RulesWithIssues: 2, TotalIssues: 2},
+		Results: []*analyzer.RuleResult{
+			{
+				RuleName: "host-heartbeats",
+				RuleType: analyzer.RuleTypePeriodic,
+				Issues: []analyzer.Issue{{
+					Type: analyzer.IssueTypeAbsentMember,
+					Context: analyzer.IssueContext{
+						CorrelationID: "web-7",
+						StartTime:     baseTime,
+						EndTime:       baseTime.Add(time.Hour),
+					},
+				}},
+			},
+			{
+				RuleName: "backups",
+				RuleType: analyzer.RuleTypeConditional,
+				Issues: []analyzer.Issue{{
+					Type:    analyzer.IssueTypeAbsentMember,
+					Context: analyzer.IssueContext{CorrelationID: "db-2"},
+				}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := f.Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := buf.String()
+	checks := []string{
+		"id=web-7: expected roster member, not seen between 10:00:00 and 11:00:00",
+		"id=db-2: expected roster member, never seen",
+	}
+	for _, check := range checks {
+		if !strings.Contains(output, check) {
+			t.Errorf("Output missing %q:\n%s", check, output)
+		}
+	}
+}
+
+func TestTextFormatter_Format_DuplicateStarts(t *testing.T) {
+	f := NewTextFormatter(FormatOptions{})
+
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	report := &Report{
+		Summary: Summary{RulesChecked: 1, RulesWithIssues: 1, TotalIssues: 2},
+		Results: []*analyzer.RuleResult{{
+			RuleName: "request-flow",
+			RuleType: analyzer.RuleTypeSequence,
+			Issues: []analyzer.Issue{
+				{
+					Type: analyzer.IssueTypeDuplicateStart,
+					Context: analyzer.IssueContext{
+						CorrelationID: "r1",
+						StartTime:     baseTime,
+						EndTime:       baseTime.Add(5 * time.Second),
+					},
+				},
+				{
+					Type: analyzer.IssueTypeExcessiveRetries,
+					Context: analyzer.IssueContext{
+						CorrelationID: "r2",
+						StartTime:     baseTime,
+						Occurrences:   4,
+						MaxAllowed:    3,
+					},
+				},
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := f.Format(context.Background(), report, &buf); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := buf.String()
+	checks := []string{
+		"id=r1: started again at 10:00:05, already open since 10:00:00",
+		"id=r2: retried 4 times since 10:00:00 (max allowed: 3)",
+	}
+	for _, check := range checks {
+		if !strings.Contains(output, check) {
+			t.Errorf("Output missing %q", check)
+		}
+	}
+}
+
 func TestTextFormatter_Format_NoIssues(t *testing.T) {
 	f := NewTextFormatter(FormatOptions{})
 	report := &Report{
@@ -242,6 +1030,8 @@ func createTestReport() *Report {
 			RuleName:    "test-rule",
 			RuleType:    analyzer.RuleTypeSequence,
 			Description: "Test rule description",
+			Severity:    config.SeverityCritical,
+			Tags:        []string{"orders", "prod"},
 			Issues: []analyzer.Issue{{
 				Type:        analyzer.IssueTypeMissingEnd,
 				Description: "Sequence not completed",
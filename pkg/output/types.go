@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/ccollicutt/negalog/pkg/analyzer"
+	"github.com/ccollicutt/negalog/pkg/config"
 )
 
 // Report is the complete analysis output.
@@ -30,6 +31,10 @@ type Summary struct {
 	// TotalIssues is the total number of issues detected.
 	TotalIssues int
 
+	// SuppressedIssues is the number of issues suppressed by maintenance
+	// windows, which are not counted in TotalIssues.
+	SuppressedIssues int
+
 	// LinesProcessed is the total number of log lines analyzed.
 	LinesProcessed int
 }
@@ -69,10 +74,11 @@ func NewReport(result *analyzer.AnalysisResult, configFile string) *Report {
 			Duration:   result.Metadata.EndTime.Sub(result.Metadata.StartTime),
 		},
 		Summary: Summary{
-			RulesChecked:    len(result.Results),
-			RulesWithIssues: result.RulesWithIssues(),
-			TotalIssues:     result.TotalIssues(),
-			LinesProcessed:  result.Metadata.LinesProcessed,
+			RulesChecked:     len(result.Results),
+			RulesWithIssues:  result.RulesWithIssues(),
+			TotalIssues:      result.TotalIssues(),
+			SuppressedIssues: result.TotalSuppressed(),
+			LinesProcessed:   result.Metadata.LinesProcessed,
 		},
 	}
 
@@ -90,3 +96,14 @@ func NewReport(result *analyzer.AnalysisResult, configFile string) *Report {
 func (r *Report) HasIssues() bool {
 	return r.Summary.TotalIssues > 0
 }
+
+// HasIssuesAtLeast returns true if any rule of at least the given severity
+// detected issues.
+func (r *Report) HasIssuesAtLeast(min config.Severity) bool {
+	for _, result := range r.Results {
+		if result.HasIssues() && result.Severity.AtLeast(min) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/ccollicutt/negalog/pkg/analyzer"
+	"github.com/ccollicutt/negalog/pkg/config"
+)
+
+func TestReport_HasIssuesAtLeast(t *testing.T) {
+	report := &Report{
+		Results: []*analyzer.RuleResult{
+			{RuleName: "debug", Severity: config.SeverityInfo, Issues: []analyzer.Issue{{Type: analyzer.IssueTypeGapExceeded}}},
+			{RuleName: "heartbeat", Severity: config.SeverityWarning, Issues: []analyzer.Issue{{Type: analyzer.IssueTypeGapExceeded}}},
+			{RuleName: "payments", Severity: config.SeverityCritical},
+		},
+	}
+
+	tests := []struct {
+		min  config.Severity
+		want bool
+	}{
+		{config.SeverityInfo, true},
+		{config.SeverityWarning, true},
+		{config.SeverityCritical, false},
+	}
+
+	for _, tt := range tests {
+		if got := report.HasIssuesAtLeast(tt.min); got != tt.want {
+			t.Errorf("HasIssuesAtLeast(%s) = %v, want %v", tt.min, got, tt.want)
+		}
+	}
+}
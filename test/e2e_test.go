@@ -582,6 +582,11 @@ func TestE2E_CrossService(t *testing.T) {
 		t.Error("Expected TRC-005 to be flagged as incomplete")
 	}
 
+	// TRC-006 started 10s before the last log line, within its 30s timeout
+	if rr.Stats.Pending != 1 {
+		t.Errorf("Expected 1 pending request (TRC-006), got %d", rr.Stats.Pending)
+	}
+
 	t.Logf("Cross-service tracking: detected %d lost requests", len(rr.Issues))
 }
 
@@ -1220,3 +1225,42 @@ webhooks:
 		t.Log("Note: Connectivity check may be skipped if network is unavailable")
 	}
 }
+
+// TestE2E_Analyze_FailOn tests that only issues at or above --fail-on set exit code 1.
+func TestE2E_Analyze_FailOn(t *testing.T) {
+	chdir(t)
+
+	// The cross-service rule has issues and the default severity, warning
+	configFile := filepath.Join("testdata", "configs", "cross_service.yaml")
+
+	tests := []struct {
+		failOn   string
+		wantCode int
+	}{
+		{"info", 1},
+		{"warning", 1},
+		{"critical", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.failOn, func(t *testing.T) {
+			cmd := exec.Command("./bin/negalog", "analyze", configFile, "--fail-on", tt.failOn)
+			output, err := cmd.CombinedOutput()
+
+			code := 0
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				code = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v\nOutput: %s", err, output)
+			}
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d\nOutput: %s", code, tt.wantCode, output)
+			}
+
+			// Issues are reported either way
+			if !strings.Contains(string(output), "Missing:") {
+				t.Errorf("Expected issues in output:\n%s", output)
+			}
+		})
+	}
+}